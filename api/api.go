@@ -2,35 +2,80 @@ package api
 
 import (
 	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/reminders"
 	"github.com/Fs02/go-todo-backend/scores"
 	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/Fs02/go-todo-backend/websocket"
 	"github.com/go-chi/chi"
 	chimid "github.com/go-chi/chi/middleware"
 	"github.com/go-rel/rel"
 	"github.com/goware/cors"
 )
 
-// NewMux api.
-func NewMux(repository rel.Repository) *chi.Mux {
+// NewMux api, changes are published to bus and streamed to clients, websocket connections are tracked by group.
+func NewMux(repository rel.Repository, verifier auth.Verifier, tickets auth.Tickets, bus *events.Bus, group *websocket.Group) *chi.Mux {
 	var (
-		mux            = chi.NewMux()
-		scores         = scores.New(repository)
-		todos          = todos.New(repository, scores)
-		healthzHandler = handler.NewHealthz()
-		todosHandler   = handler.NewTodos(repository, todos)
-		scoreHandler   = handler.NewScore(repository)
+		mux             = chi.NewMux()
+		scores          = scores.New(repository, bus)
+		todos           = todos.New(repository, scores, bus)
+		apiKeys         = apikeys.New(repository)
+		users           = users.New(repository)
+		lists           = lists.New(repository, todos)
+		webhooks        = webhooks.New(repository, jobs.New(repository))
+		reminders       = reminders.New(repository, nil)
+		healthzHandler  = handler.NewHealthz()
+		metricsHandler  = handler.NewMetrics(metrics.Default)
+		todosHandler    = handler.NewTodos(repository, todos, reminders)
+		listsHandler    = handler.NewLists(repository, lists, todosHandler)
+		tagsHandler     = handler.NewTags(repository, todos)
+		scoreHandler    = handler.NewScore(repository, scores)
+		apiKeysHandler  = handler.NewAPIKeys(repository, apiKeys)
+		eventsHandler   = handler.NewEvents(bus, lists)
+		syncHandler     = handler.NewSync(repository, todos, lists, bus, group)
+		webhooksHandler = handler.NewWebhooks(repository, webhooks)
+		ticketsHandler  = handler.NewTickets(tickets)
 	)
 
 	healthzHandler.Add("database", repository)
 
 	mux.Use(chimid.RequestID)
 	mux.Use(chimid.RealIP)
-	mux.Use(chimid.Recoverer)
+	mux.Use(handler.Trace)
+	mux.Use(handler.Instrument)
+	mux.Use(handler.Recoverer)
 	mux.Use(cors.AllowAll().Handler)
+	mux.NotFound(handler.NotFound)
+	mux.MethodNotAllowed(handler.MethodNotAllowed)
 
 	mux.Mount("/healthz", healthzHandler)
-	mux.Mount("/todos", todosHandler)
-	mux.Mount("/score", scoreHandler)
+	mux.Mount("/metrics", metricsHandler)
+
+	// every other endpoint serves data of the authenticated user.
+	mux.Group(func(r chi.Router) {
+		r.Use(handler.Auth(verifier, apiKeys, users))
+		r.Mount("/todos", todosHandler)
+		r.Mount("/lists", listsHandler)
+		r.Mount("/tags", tagsHandler)
+		r.Mount("/score", scoreHandler)
+		r.Mount("/api-keys", apiKeysHandler)
+		r.Mount("/webhooks", webhooksHandler)
+		r.Mount("/tickets", ticketsHandler)
+	})
+
+	// browser EventSource and WebSocket can't set Authorization header, so they're also authenticated by ticket.
+	mux.Group(func(r chi.Router) {
+		r.Use(handler.TicketAuth(tickets, verifier, apiKeys, users))
+		r.Mount("/todos/events", eventsHandler)
+		r.Mount("/ws", syncHandler)
+	})
 
 	return mux
 }
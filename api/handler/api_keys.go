@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
+)
+
+// APIKeys for api keys endpoints.
+type APIKeys struct {
+	*chi.Mux
+	repository rel.Repository
+	apiKeys    apikeys.Service
+}
+
+// Index handle GET /.
+func (k APIKeys) Index(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []apikeys.APIKey
+	)
+
+	if err := k.apiKeys.Search(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Create handle POST /, the response is the only time the token is shown.
+func (k APIKeys) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		body struct {
+			Name   string         `json:"name"`
+			Scopes apikeys.Scopes `json:"scopes"`
+		}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	key := apikeys.APIKey{Name: body.Name, Scopes: body.Scopes}
+	if err := k.apiKeys.Create(ctx, &key); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", key.ID))
+	render(w, key, 201)
+}
+
+// Destroy handle DELETE /{ID}
+func (k APIKeys) Destroy(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		key = ctx.Value(loadAPIKeyKey).(apikeys.APIKey)
+	)
+
+	if err := k.apiKeys.Revoke(ctx, &key); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Load is middleware that loads active api keys to context.
+func (k APIKeys) Load(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   = r.Context()
+			id, _ = strconv.Atoi(chi.URLParam(r, "ID"))
+			key   apikeys.APIKey
+		)
+
+		if err := k.repository.Find(ctx, &key, where.Eq("id", id), where.Eq("user_id", users.FromContext(ctx)), where.Nil("revoked_at")); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadAPIKeyKey, key)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewAPIKeys handler.
+func NewAPIKeys(repository rel.Repository, apiKeys apikeys.Service) APIKeys {
+	h := APIKeys{
+		Mux:        newMux(),
+		repository: repository,
+		apiKeys:    apiKeys,
+	}
+
+	// a leaked api key can't be used to create other keys or keep itself from being revoked.
+	h.Use(Interactive)
+	h.Get("/", h.Index)
+	h.Post("/", h.Create)
+	h.With(h.Load).Delete("/{ID}", h.Destroy)
+
+	return h
+}
@@ -0,0 +1,185 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/apikeys/apikeystest"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys_Index(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		ctx               context.Context
+		response          string
+		mockAPIKeysSearch apikeystest.MockFunc
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			ctx:      ctx,
+			response: `[{"id":1, "name":"ci", "prefix":"tk_abcdefgh", "scopes":["todos:read"], "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockAPIKeysSearch: apikeystest.MockSearch(
+				[]apikeys.APIKey{{ID: 1, Name: "ci", Prefix: "tk_abcdefgh", Hash: "hash", Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}}},
+				nil,
+			),
+		},
+		{
+			name:     "authenticated by api key",
+			status:   http.StatusForbidden,
+			ctx:      apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}}),
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"Forbidden"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(test.ctx, "GET", "/", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				apiKeys    = &apikeystest.Service{}
+				handler    = handler.NewAPIKeys(repository, apiKeys)
+			)
+
+			apikeystest.Mock(apiKeys, test.mockAPIKeysSearch)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			apiKeys.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeys_Create(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		payload           string
+		response          string
+		location          string
+		mockAPIKeysCreate apikeystest.MockFunc
+	}{
+		{
+			name:     "created",
+			status:   http.StatusCreated,
+			payload:  `{"name": "ci", "scopes": ["todos:read"]}`,
+			response: `{"id":1, "name":"ci", "prefix":"tk_abcdefgh", "scopes":["todos:read"], "token":"tk_abcdefghijkl", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1",
+			mockAPIKeysCreate: apikeystest.MockCreate(
+				apikeys.APIKey{ID: 1, Name: "ci", Prefix: "tk_abcdefgh", Hash: "hash", Token: "tk_abcdefghijkl", Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}},
+				nil,
+			),
+		},
+		{
+			name:     "validation error",
+			status:   http.StatusUnprocessableEntity,
+			payload:  `{"name": "ci"}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Scopes must contain at least one of todos:read, todos:write or score:read","invalid_params":[{"name":"scopes","code":"invalid","reason":"Scopes must contain at least one of todos:read, todos:write or score:read"}]}`,
+			mockAPIKeysCreate: apikeystest.MockCreate(
+				apikeys.APIKey{Name: "ci"},
+				apikeys.ErrAPIKeyScopesInvalid,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", "/", body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				apiKeys    = &apikeystest.Service{}
+				handler    = handler.NewAPIKeys(repository, apiKeys)
+			)
+
+			apikeystest.Mock(apiKeys, test.mockAPIKeysCreate)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			apiKeys.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeys_Destroy(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		response          string
+		mockRepo          func(repo *reltest.Repository)
+		mockAPIKeysRevoke apikeystest.MockFunc
+	}{
+		{
+			name:   "ok",
+			status: http.StatusNoContent,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1)), where.Nil("revoked_at")).Result(apikeys.APIKey{ID: 1, Name: "ci"})
+			},
+			mockAPIKeysRevoke: apikeystest.MockRevoke(nil),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1)), where.Nil("revoked_at")).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", "/1", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				apiKeys    = &apikeystest.Service{}
+				handler    = handler.NewAPIKeys(repository, apiKeys)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			apikeystest.Mock(apiKeys, test.mockAPIKeysRevoke)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Equal(t, "", rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			apiKeys.AssertExpectations(t)
+		})
+	}
+}
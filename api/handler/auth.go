@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"go.uber.org/zap"
+)
+
+// Auth is middleware that authenticates bearer token using auth.Middleware, and renders request that can't be authenticated.
+func Auth(verifier auth.Verifier, apiKeys apikeys.Service, users users.Service) func(http.Handler) http.Handler {
+	return auth.Middleware(verifier, apiKeys, users, authFailed)
+}
+
+// TicketAuth is middleware that authenticates ticket query parameter, or bearer token when there's no ticket.
+// it's used by event stream and websocket endpoints, because browser can't set Authorization header on them.
+func TicketAuth(tickets auth.Tickets, verifier auth.Verifier, apiKeys apikeys.Service, users users.Service) func(http.Handler) http.Handler {
+	return tickets.Middleware(Auth(verifier, apiKeys, users), authFailed)
+}
+
+// Scope is middleware that forbids request authenticated by api key that isn't granted the scope.
+// request authenticated by jwt has access to every scope.
+func Scope(scope apikeys.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !granted(r.Context(), scope) {
+				render(w, ErrForbidden, 403)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Interactive is middleware that forbids request authenticated by api key,
+// it guards endpoints that manage credentials or where the data is sent to.
+func Interactive(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := apikeys.FromContext(r.Context()); ok {
+			logger.Warn("forbidden", zap.Uint("api_key", key.ID), zap.String("error", "endpoint requires interactive login"), tracing.Field(r.Context()))
+			render(w, ErrForbidden, 403)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// granted reports whether the requesting user may use the scope, only api key can be limited to some of the scopes.
+func granted(ctx context.Context, scope apikeys.Scope) bool {
+	if key, ok := apikeys.FromContext(ctx); ok && !key.Scopes.Contains(scope) {
+		logger.Warn("forbidden", zap.Uint("api_key", key.ID), zap.String("scope", string(scope)), tracing.Field(ctx))
+		return false
+	}
+
+	return true
+}
+
+// authFailed renders 401 without telling the reason to the client, the reason is only logged.
+// failure to check the credentials such as unavailable database is rendered as any other error.
+func authFailed(w http.ResponseWriter, r *http.Request, err error) {
+	if !auth.Rejected(err) {
+		renderError(w, r, err)
+		return
+	}
+
+	logger.Warn("unauthorized", zap.Error(err), tracing.Field(r.Context()))
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	render(w, ErrUnauthorized, 401)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// heartbeat keeps idle stream open through proxies that close silent connections.
+var heartbeat = 15 * time.Second
+
+// Events for event stream endpoint.
+type Events struct {
+	*chi.Mux
+	bus   *events.Bus
+	lists lists.Service
+}
+
+// Stream handle GET /, it streams changes visible to the user as server-sent events.
+// client resumes from the last received event through Last-Event-ID header, stream.reset event is sent first
+// when some events after it are no longer available, the client should reload its todos.
+func (e Events) Stream(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []lists.List
+		lastID uint64
+	)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		renderError(w, r, errors.New("streaming is not supported"))
+		return
+	}
+
+	if str := r.Header.Get("Last-Event-ID"); str != "" {
+		id, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(ctx))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		lastID = id
+	}
+
+	// lists are loaded once, events of list joined afterward are delivered after reconnecting.
+	if err := e.lists.Search(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	sub, replay, complete := e.bus.Subscribe(visible(ctx, result), lastID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !complete {
+		fmt.Fprint(w, "event: stream.reset\ndata: {}\n\n")
+	}
+
+	for _, event := range replay {
+		if err := e.write(ctx, w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Dropped {
+					logger.Warn("event stream dropped", tracing.Field(ctx))
+				}
+				return
+			}
+
+			if err := e.write(ctx, w, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-ctx.Done():
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// write event of a list only if the user is still a member of the list, so member removed from the list stops receiving its events
+// without reconnecting. stream is ended if membership can't be checked, the client resumes from the last event it received.
+func (e Events) write(ctx context.Context, w http.ResponseWriter, event events.Event) error {
+	if event.ListID != nil {
+		if err := e.lists.Authorize(ctx, *event.ListID, lists.ActionRead); err != nil {
+			if errors.Is(err, rel.ErrNotFound) || errors.Is(err, lists.ErrForbidden) {
+				return nil
+			}
+
+			logger.Error("authorize error", zap.Error(err), tracing.Field(ctx))
+			return err
+		}
+	}
+
+	writeEvent(w, event)
+	return nil
+}
+
+// visible filters events of lists the user was a member of when the stream started, and events of the user's own todos that don't belong to any list.
+// score changes are only visible to api key that's granted to read the score.
+func visible(ctx context.Context, result []lists.List) func(events.Event) bool {
+	var (
+		userID     = users.FromContext(ctx)
+		key, ok    = apikeys.FromContext(ctx)
+		score      = !ok || key.Scopes.Contains(apikeys.ScopeScoreRead)
+		accessible = make(map[uint]bool, len(result))
+	)
+
+	for _, list := range result {
+		accessible[list.ID] = true
+	}
+
+	return func(event events.Event) bool {
+		if event.ListID != nil {
+			return accessible[*event.ListID]
+		}
+
+		if event.Type == events.ScoreChanged && !score {
+			return false
+		}
+
+		return event.UserID == userID
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+// NewEvents handler.
+func NewEvents(bus *events.Bus, lists lists.Service) Events {
+	h := Events{
+		Mux:   newMux(),
+		bus:   bus,
+		lists: lists,
+	}
+
+	h.With(Scope(apikeys.ScopeTodosRead)).Get("/", h.Stream)
+
+	return h
+}
@@ -0,0 +1,202 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/lists/liststest"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents_Stream(t *testing.T) {
+	var (
+		bus       = events.NewBus(3)
+		all       = func(events.Event) bool { return true }
+		sub, _, _ = bus.Subscribe(all, 0)
+		listID    = uint(2)
+		otherID   = uint(3)
+		ids       = make([]uint64, 4)
+	)
+
+	bus.Publish(context.TODO(),
+		events.New(events.TodoCreated, 2, nil, map[string]int{"id": 1}),
+		events.New(events.TodoCreated, 2, &otherID, map[string]int{"id": 2}),
+		events.New(events.TodoUpdated, 2, &listID, map[string]int{"id": 3}),
+		events.New(events.TodoDeleted, 1, nil, map[string]int{"id": 4}),
+	)
+
+	for i := range ids {
+		ids[i] = (<-sub.Events()).ID
+	}
+	sub.Close()
+
+	tests := []struct {
+		name        string
+		status      int
+		lastEventID string
+		response    string
+		mockLists   []liststest.MockFunc
+	}{
+		{
+			name:      "without last event id",
+			status:    http.StatusOK,
+			response:  "",
+			mockLists: []liststest.MockFunc{liststest.MockSearch([]lists.List{{ID: 2}}, nil)},
+		},
+		{
+			name:        "resumed",
+			status:      http.StatusOK,
+			lastEventID: strconv.FormatUint(ids[1], 10),
+			response:    fmt.Sprintf("id: %d\nevent: todo.updated\ndata: {\"id\":3}\n\nid: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[2], ids[3]),
+			mockLists: []liststest.MockFunc{
+				liststest.MockSearch([]lists.List{{ID: 2}}, nil),
+				liststest.MockAuthorize(2, lists.ActionRead, nil),
+			},
+		},
+		{
+			name:        "resumed after removed from list",
+			status:      http.StatusOK,
+			lastEventID: strconv.FormatUint(ids[1], 10),
+			response:    fmt.Sprintf("id: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[3]),
+			mockLists: []liststest.MockFunc{
+				liststest.MockSearch([]lists.List{{ID: 2}}, nil),
+				liststest.MockAuthorize(2, lists.ActionRead, rel.ErrNotFound),
+			},
+		},
+		{
+			name:        "resumed while membership can't be checked",
+			status:      http.StatusOK,
+			lastEventID: strconv.FormatUint(ids[1], 10),
+			response:    "",
+			mockLists: []liststest.MockFunc{
+				liststest.MockSearch([]lists.List{{ID: 2}}, nil),
+				liststest.MockAuthorize(2, lists.ActionRead, reltest.ErrConnectionClosed),
+			},
+		},
+		{
+			name:        "resumed from evicted event",
+			status:      http.StatusOK,
+			lastEventID: strconv.FormatUint(ids[0]-1, 10),
+			response:    fmt.Sprintf("event: stream.reset\ndata: {}\n\nid: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[3]),
+			mockLists:   []liststest.MockFunc{liststest.MockSearch(nil, nil)},
+		},
+		{
+			name:        "invalid last event id",
+			status:      http.StatusBadRequest,
+			lastEventID: "abc",
+			response:    `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:      "lists unavailable",
+			status:    http.StatusServiceUnavailable,
+			response:  `{"type":"about:blank","title":"Service Unavailable","status":503}`,
+			mockLists: []liststest.MockFunc{liststest.MockSearch(nil, reltest.ErrConnectionClosed)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				// canceled request only receives the replayed events.
+				reqCtx, cancel = context.WithCancel(ctx)
+				req, _         = http.NewRequestWithContext(reqCtx, "GET", "/", nil)
+				rr             = httptest.NewRecorder()
+				service        = &liststest.Service{}
+				handler        = handler.NewEvents(bus, service)
+			)
+
+			cancel()
+			if test.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", test.lastEventID)
+			}
+
+			liststest.Mock(service, test.mockLists...)
+
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, test.status, rr.Code)
+			if test.status == http.StatusOK {
+				assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+				assert.Equal(t, test.response, rr.Body.String())
+			} else {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			}
+
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEvents_Stream_score(t *testing.T) {
+	var (
+		bus       = events.NewBus(2)
+		all       = func(events.Event) bool { return true }
+		sub, _, _ = bus.Subscribe(all, 0)
+		ids       = make([]uint64, 2)
+	)
+
+	bus.Publish(context.TODO(),
+		events.New(events.ScoreChanged, 1, nil, map[string]int{"total_point": 1}),
+		events.New(events.TodoDeleted, 1, nil, map[string]int{"id": 4}),
+	)
+
+	for i := range ids {
+		ids[i] = (<-sub.Events()).ID
+	}
+	sub.Close()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		response string
+	}{
+		{
+			name:     "jwt",
+			ctx:      ctx,
+			response: fmt.Sprintf("id: %d\nevent: score.changed\ndata: {\"total_point\":1}\n\nid: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[0], ids[1]),
+		},
+		{
+			name:     "api key granted score",
+			ctx:      apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, UserID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead, apikeys.ScopeScoreRead}}),
+			response: fmt.Sprintf("id: %d\nevent: score.changed\ndata: {\"total_point\":1}\n\nid: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[0], ids[1]),
+		},
+		{
+			name:     "api key without score",
+			ctx:      apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, UserID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}}),
+			response: fmt.Sprintf("id: %d\nevent: todo.deleted\ndata: {\"id\":4}\n\n", ids[1]),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				// canceled request only receives the replayed events.
+				reqCtx, cancel = context.WithCancel(test.ctx)
+				req, _         = http.NewRequestWithContext(reqCtx, "GET", "/", nil)
+				rr             = httptest.NewRecorder()
+				service        = &liststest.Service{}
+				handler        = handler.NewEvents(bus, service)
+			)
+
+			cancel()
+			req.Header.Set("Last-Event-ID", strconv.FormatUint(ids[0]-1, 10))
+
+			liststest.Mock(service, liststest.MockSearch(nil, nil))
+
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, test.response, rr.Body.String())
+
+			service.AssertExpectations(t)
+		})
+	}
+}
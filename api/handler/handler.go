@@ -3,8 +3,18 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
 	"go.uber.org/zap"
 )
 
@@ -12,9 +22,59 @@ var (
 	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "handler")))
 	// ErrBadRequest error.
 	ErrBadRequest = errors.New("Bad Request")
+	// ErrUnauthorized error.
+	ErrUnauthorized = errors.New("Unauthorized")
+	// ErrForbidden error.
+	ErrForbidden = errors.New("Forbidden")
+	// ErrNotFound error.
+	ErrNotFound = errors.New("Not Found")
+	// ErrMethodNotAllowed error.
+	ErrMethodNotAllowed = errors.New("Method Not Allowed")
+	// ErrInternal error.
+	ErrInternal = errors.New("Internal Server Error")
 )
 
+// problem details of error response as described by RFC 7807.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []invalidParam `json:"invalid_params,omitempty"`
+}
+
+// invalidParam describes a single invalid field of validation error.
+type invalidParam struct {
+	Name   string `json:"name"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// newProblem describes err, detail of internal error is omitted so it doesn't leak to client.
+func newProblem(err error, status int) problem {
+	p := problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	if status < 500 {
+		p.Detail = err.Error()
+	}
+
+	for _, field := range validation.Fields(err) {
+		p.InvalidParams = append(p.InvalidParams, invalidParam{Name: field.Field, Code: field.Code, Reason: field.Message})
+	}
+
+	return p
+}
+
 func render(w http.ResponseWriter, body interface{}, status int) {
+	if err, ok := body.(error); ok {
+		renderProblem(w, newProblem(err, status), status)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
@@ -25,15 +85,125 @@ func render(w http.ResponseWriter, body interface{}, status int) {
 		}{
 			Message: v,
 		})
-	case error:
-		json.NewEncoder(w).Encode(struct {
-			Error string `json:"error"`
-		}{
-			Error: v.Error(),
-		})
 	case nil:
 		// do nothing
 	default:
 		json.NewEncoder(w).Encode(body)
 	}
 }
+
+// renderProblem renders problem details, body should embed problem when it needs extension members.
+func renderProblem(w http.ResponseWriter, body interface{}, status int) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// renderError renders error returned by services with status that matches the error.
+// transient error is rendered with Retry-After header, so client knows when the request is worth retrying.
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	err = dberr.Classify(err)
+
+	status := errorStatus(err)
+	if status >= 500 {
+		logger.Error("internal error", zap.Error(err), tracing.Field(r.Context()))
+	}
+
+	if retryAfter := dberr.RetryAfter(err); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+
+	render(w, err, status)
+}
+
+// errorStatus maps err to http status, error that isn't known to be caused by the request is an internal error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return 400
+	case errors.Is(err, ErrUnauthorized):
+		return 401
+	case errors.Is(err, ErrForbidden), errors.Is(err, lists.ErrForbidden):
+		return 403
+	case errors.Is(err, ErrNotFound), errors.Is(err, rel.ErrNotFound):
+		return 404
+	case errors.Is(err, todos.ErrTagNameTaken), errors.Is(err, dberr.ErrConflict):
+		return 409
+	case errors.Is(err, todos.ErrTodoConflict):
+		return 412
+	case len(validation.Fields(err)) > 0:
+		return 422
+	case errors.Is(err, dberr.ErrUnavailable):
+		return 503
+	}
+
+	return 500
+}
+
+// Recoverer is middleware that recovers from panic, and renders it as internal error.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				// aborted handler must not be recovered, so the connection is closed.
+				if rvr == http.ErrAbortHandler {
+					panic(rvr)
+				}
+
+				logger.Error("panic", zap.Any("panic", rvr), zap.Stack("stack"), tracing.Field(r.Context()))
+				render(w, ErrInternal, 500)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newMux returns router of handler, that renders unknown route and unsupported method as problem.
+func newMux() *chi.Mux {
+	mux := chi.NewMux()
+	mux.NotFound(NotFound)
+	mux.MethodNotAllowed(MethodNotAllowed)
+
+	return mux
+}
+
+// NotFound handles request to unknown route.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	render(w, ErrNotFound, 404)
+}
+
+// MethodNotAllowed handles request with method that isn't supported by the route.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	render(w, ErrMethodNotAllowed, 405)
+}
+
+// link sets Link header pointing to the next page, the next page uses the same query with after param set to cursor.
+func link(w http.ResponseWriter, r *http.Request, cursor string) {
+	var (
+		next  = *r.URL
+		query = next.Query()
+	)
+
+	query.Set("after", cursor)
+	next.RawQuery = query.Encode()
+
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+}
+
+// etagMatch reports whether any entity tag listed in header matches etag, * matches any etag.
+// weak comparison ignores weak indicator, it should be used for If-None-Match, while If-Match uses strong comparison.
+func etagMatch(header string, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,7 +29,7 @@ func TestRender(t *testing.T) {
 		{
 			name:     "error",
 			data:     errors.New("system error"),
-			response: `{"error":"system error"}`,
+			response: `{"type":"about:blank","title":"OK","status":200,"detail":"system error"}`,
 		},
 		{
 			name:     "nil",
@@ -53,3 +60,145 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_problem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+		response   string
+	}{
+		{
+			name:     "bad request",
+			err:      ErrBadRequest,
+			status:   400,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:     "not found",
+			err:      rel.NotFoundError{},
+			status:   404,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+		},
+		{
+			name:     "forbidden",
+			err:      lists.ErrForbidden,
+			status:   403,
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
+		},
+		{
+			name:     "unique constraint",
+			err:      rel.ConstraintError{Key: "tags_user_id_name", Type: rel.UniqueConstraint},
+			status:   409,
+			response: `{"type":"about:blank","title":"Conflict","status":409,"detail":"UniqueConstraintError"}`,
+		},
+		{
+			name:     "conflict",
+			err:      todos.ErrTodoConflict,
+			status:   412,
+			response: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"Todo has been modified, reload it and try again"}`,
+		},
+		{
+			name:   "validation",
+			err:    validation.Errors{todos.ErrTodoTitleBlank, todos.ErrTodoOrderOutOfRange},
+			status: 422,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Title can't be blank, Order must be between 0 and 2147483647","invalid_params":[
+				{"name":"title","code":"blank","reason":"Title can't be blank"},
+				{"name":"order","code":"out_of_range","reason":"Order must be between 0 and 2147483647"}
+			]}`,
+		},
+		{
+			name:     "foreign key constraint",
+			err:      rel.ConstraintError{Key: "todos_list_id_fkey", Type: rel.ForeignKeyConstraint},
+			status:   409,
+			response: `{"type":"about:blank","title":"Conflict","status":409,"detail":"ForeignKeyConstraintError"}`,
+		},
+		{
+			name:       "unavailable",
+			err:        fmt.Errorf("find: %w", driver.ErrBadConn),
+			status:     503,
+			retryAfter: "5",
+			response:   `{"type":"about:blank","title":"Service Unavailable","status":503}`,
+		},
+		{
+			name:     "internal",
+			err:      errors.New("connection refused"),
+			status:   500,
+			response: `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _ = http.NewRequest("GET", "/", nil)
+				rr     = httptest.NewRecorder()
+			)
+
+			renderError(rr, req, test.err)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, test.retryAfter, rr.Header().Get("Retry-After"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+		})
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	var (
+		req, _  = http.NewRequest("GET", "/", nil)
+		rr      = httptest.NewRecorder()
+		handler = Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(errors.New("connection refused"))
+		}))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, 500, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500}`, rr.Body.String())
+}
+
+func TestNewMux(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		response string
+	}{
+		{
+			name:     "not found",
+			method:   "GET",
+			path:     "/unknown",
+			status:   404,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"Not Found"}`,
+		},
+		{
+			name:     "method not allowed",
+			method:   "POST",
+			path:     "/",
+			status:   405,
+			response: `{"type":"about:blank","title":"Method Not Allowed","status":405,"detail":"Method Not Allowed"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _ = http.NewRequest(test.method, test.path, nil)
+				rr     = httptest.NewRecorder()
+				mux    = newMux()
+			)
+
+			mux.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+			mux.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+		})
+	}
+}
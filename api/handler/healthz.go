@@ -5,10 +5,16 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/tracing"
 	"github.com/go-chi/chi"
 	"go.uber.org/zap"
 )
 
+var (
+	healthzUp = metrics.Default.Gauge("healthz_up", "Result of the last health check by service, 1 if the service is up.", "service")
+)
+
 // Pinger interface.
 type Pinger interface {
 	Ping(ctx context.Context) error
@@ -42,12 +48,14 @@ func (h Healthz) Show(w http.ResponseWriter, r *http.Request) {
 
 			pings[i].Service = service
 			if err := pinger.Ping(r.Context()); err != nil {
-				logger.Error("ping error", zap.Error(err))
+				logger.Error("ping error", zap.Error(err), tracing.Field(r.Context()))
 
 				status = 503
 				pings[i].Status = err.Error()
+				healthzUp.Set(0, service)
 			} else {
 				pings[i].Status = "UP"
+				healthzUp.Set(1, service)
 			}
 		}(i, service, pinger)
 		i++
@@ -65,7 +73,7 @@ func (h *Healthz) Add(name string, ping Pinger) {
 // NewHealthz handler.
 func NewHealthz() Healthz {
 	h := Healthz{
-		Mux:     chi.NewMux(),
+		Mux:     newMux(),
 		pingers: make(map[string]Pinger),
 	}
 
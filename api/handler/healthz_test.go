@@ -1,6 +1,7 @@
 package handler_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/metrics"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,6 +28,7 @@ func TestHealthz_Show(t *testing.T) {
 		status   int
 		path     string
 		response string
+		up       string
 	}{
 		{
 			name:     "all dependencies are healthy",
@@ -33,6 +36,7 @@ func TestHealthz_Show(t *testing.T) {
 			status:   http.StatusOK,
 			path:     "/",
 			response: `[{"service": "test", "status": "UP"}]`,
+			up:       `healthz_up{service="test"} 1`,
 		},
 		{
 			name:     "some dependencies are sick",
@@ -40,12 +44,14 @@ func TestHealthz_Show(t *testing.T) {
 			status:   http.StatusServiceUnavailable,
 			path:     "/",
 			response: `[{"service": "test", "status": "service is down"}]`,
+			up:       `healthz_up{service="test"} 0`,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
+				buf     bytes.Buffer
 				req, _  = http.NewRequest("GET", test.path, nil)
 				rr      = httptest.NewRecorder()
 				handler = handler.NewHealthz()
@@ -57,6 +63,9 @@ func TestHealthz_Show(t *testing.T) {
 
 			assert.Equal(t, test.status, rr.Code)
 			assert.JSONEq(t, test.response, rr.Body.String())
+
+			metrics.Default.WriteTo(&buf)
+			assert.Contains(t, buf.String(), test.up)
 		})
 	}
 }
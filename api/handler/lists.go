@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
+)
+
+// Lists for lists endpoints.
+type Lists struct {
+	*chi.Mux
+	repository rel.Repository
+	lists      lists.Service
+	policy     lists.Policy
+}
+
+// Index handle GET /.
+func (l Lists) Index(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []lists.List
+	)
+
+	if err := l.lists.Search(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Create handle POST /
+func (l Lists) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		list lists.List
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := l.lists.Create(ctx, &list); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", list.ID))
+	render(w, list, 201)
+}
+
+// Show handle GET /{ID}
+func (l Lists) Show(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		list = ctx.Value(loadListKey).(lists.List)
+	)
+
+	render(w, list, 200)
+}
+
+// Update handle PATCH /{ID}
+func (l Lists) Update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		list    = ctx.Value(loadListKey).(lists.List)
+		changes = rel.NewChangeset(&list)
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := l.lists.Update(ctx, &list, changes); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, list, 200)
+}
+
+// Destroy handle DELETE /{ID}
+// todos of the list are moved to trash of their creators, unless ?todos=move&list_id={ID} is given to move them to another list.
+func (l Lists) Destroy(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		query  = r.URL.Query()
+		list   = ctx.Value(loadListKey).(lists.List)
+		moveTo *uint
+	)
+
+	switch query.Get("todos") {
+	case "", "cascade":
+	case "move":
+		id, err := strconv.ParseUint(query.Get("list_id"), 10, 0)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+
+		listID := uint(id)
+		moveTo = &listID
+	default:
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := l.lists.Delete(ctx, &list, moveTo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Members handle GET /{ID}/members
+func (l Lists) Members(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		list   = ctx.Value(loadListKey).(lists.List)
+		result []lists.Member
+	)
+
+	if err := l.lists.SearchMembers(ctx, &result, list); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// CreateMember handle POST /{ID}/members
+func (l Lists) CreateMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		list   = ctx.Value(loadListKey).(lists.List)
+		member lists.Member
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := l.lists.CreateMember(ctx, list, &member); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", member.UserID))
+	render(w, member, 201)
+}
+
+// UpdateMember handle PATCH /{ID}/members/{UserID}
+func (l Lists) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		loaded  = ctx.Value(loadMemberKey).(lists.Member)
+		member  = loaded
+		changes = rel.NewChangeset(&member)
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	// only role can be changed, the member is still the same user.
+	member.UserID = loaded.UserID
+
+	if err := l.lists.UpdateMember(ctx, &member, changes); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, member, 200)
+}
+
+// DestroyMember handle DELETE /{ID}/members/{UserID}
+func (l Lists) DestroyMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		member = ctx.Value(loadMemberKey).(lists.Member)
+	)
+
+	if err := l.lists.DeleteMember(ctx, &member); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Load is middleware that loads lists to context, list is only loaded if the requesting user is its member.
+func (l Lists) Load(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   = r.Context()
+			id, _ = strconv.Atoi(chi.URLParam(r, "ID"))
+			list  lists.List
+		)
+
+		if err := l.policy.Authorize(ctx, uint(id), lists.ActionRead); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		if err := l.repository.Find(ctx, &list, where.Eq("id", id)); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadListKey, list)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LoadMember is middleware that loads member of loaded list to context, member is identified by its user id.
+func (l Lists) LoadMember(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx       = r.Context()
+			list      = ctx.Value(loadListKey).(lists.List)
+			userID, _ = strconv.Atoi(chi.URLParam(r, "UserID"))
+			member    lists.Member
+		)
+
+		if err := l.repository.Find(ctx, &member, where.Eq("list_id", list.ID), where.Eq("user_id", userID)); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadMemberKey, member)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ScopeTodos is middleware that scopes todos of the request to loaded list.
+func (l Lists) ScopeTodos(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx  = r.Context()
+			list = ctx.Value(loadListKey).(lists.List)
+		)
+
+		next.ServeHTTP(w, r.WithContext(lists.NewContext(ctx, list)))
+	})
+}
+
+// NewLists handler, todos of a list are served by todos handler scoped to the list.
+func NewLists(repository rel.Repository, service lists.Service, todos http.Handler) Lists {
+	var (
+		// lists group todos, so they share the todos scopes.
+		read  = Scope(apikeys.ScopeTodosRead)
+		write = Scope(apikeys.ScopeTodosWrite)
+	)
+
+	h := Lists{
+		Mux:        newMux(),
+		repository: repository,
+		lists:      service,
+		policy:     lists.NewPolicy(repository),
+	}
+
+	h.With(read).Get("/", h.Index)
+	h.With(write).Post("/", h.Create)
+	h.With(read, h.Load).Get("/{ID}", h.Show)
+	h.With(write, h.Load).Patch("/{ID}", h.Update)
+	h.With(write, h.Load).Delete("/{ID}", h.Destroy)
+	h.With(read, h.Load).Get("/{ID}/members", h.Members)
+	// sharing the list sends its todos to another user, so members are only managed by interactive login.
+	h.With(write, Interactive, h.Load).Post("/{ID}/members", h.CreateMember)
+	h.With(write, Interactive, h.Load, h.LoadMember).Patch("/{ID}/members/{UserID}", h.UpdateMember)
+	h.With(write, Interactive, h.Load, h.LoadMember).Delete("/{ID}/members/{UserID}", h.DestroyMember)
+	h.With(h.Load, h.ScopeTodos).Mount("/{ID}/todos", todos)
+
+	return h
+}
@@ -0,0 +1,570 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/lists/liststest"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/todos/todostest"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockListLoad(repository *reltest.Repository, role lists.Role) {
+	repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: role})
+	repository.ExpectFind(where.Eq("id", 1)).Result(lists.List{ID: 1, Name: "Home", Color: "#ff8800"})
+}
+
+func TestLists_Index(t *testing.T) {
+	var (
+		req, _     = http.NewRequestWithContext(ctx, "GET", "/", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = &liststest.Service{}
+		handler    = handler.NewLists(repository, service, nil)
+	)
+
+	liststest.Mock(service, liststest.MockSearch([]lists.List{{ID: 1, Name: "Home", Color: "#ff8800", Order: 1}}, nil))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"id":1, "name":"Home", "color":"#ff8800", "order":1, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`, rr.Body.String())
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestLists_Create(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		payload          string
+		response         string
+		location         string
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "created",
+			status:           http.StatusCreated,
+			payload:          `{"name": "Home", "color": "#ff8800"}`,
+			response:         `{"id":1, "name":"Home", "color":"#ff8800", "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location:         "/1",
+			mockListsService: liststest.MockCreate(lists.List{ID: 1, Name: "Home", Color: "#ff8800"}, nil),
+		},
+		{
+			name:             "validation error",
+			status:           http.StatusUnprocessableEntity,
+			payload:          `{"name": ""}`,
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Name can't be blank","invalid_params":[{"name":"name","code":"blank","reason":"Name can't be blank"}]}`,
+			mockListsService: liststest.MockCreate(lists.List{}, lists.ErrListNameBlank),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", "/", body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_Show(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			response: `{"id":1, "name":"Home", "color":"#ff8800", "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repository *reltest.Repository) {
+				mockListLoad(repository, lists.RoleViewer)
+			},
+		},
+		{
+			name:     "not member",
+			status:   http.StatusNotFound,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "GET", "/1", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			test.mockRepo(repository)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_Update(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		payload          string
+		response         string
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "ok",
+			status:           http.StatusOK,
+			payload:          `{"name": "Work", "order": 2}`,
+			response:         `{"id":1, "name":"Work", "color":"#ff8800", "order":2, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockListsService: liststest.MockUpdate(lists.List{ID: 1, Name: "Work", Color: "#ff8800", Order: 2}, nil),
+		},
+		{
+			name:             "not owner",
+			status:           http.StatusForbidden,
+			payload:          `{"name": "Work"}`,
+			response:         `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
+			mockListsService: liststest.MockUpdate(lists.List{ID: 1}, lists.ErrForbidden),
+		},
+		{
+			name:             "validation error",
+			status:           http.StatusUnprocessableEntity,
+			payload:          `{"color": "orange"}`,
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Color must be a hex color such as #ff8800","invalid_params":[{"name":"color","code":"invalid","reason":"Color must be a hex color such as #ff8800"}]}`,
+			mockListsService: liststest.MockUpdate(lists.List{ID: 1}, lists.ErrListColorInvalid),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "PATCH", "/1", body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			mockListLoad(repository, lists.RoleOwner)
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_Destroy(t *testing.T) {
+	var (
+		moveTo = uint(2)
+	)
+
+	tests := []struct {
+		name             string
+		status           int
+		path             string
+		response         string
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "cascade",
+			status:           http.StatusNoContent,
+			path:             "/1",
+			mockListsService: liststest.MockDelete(nil, nil),
+		},
+		{
+			name:             "explicit cascade",
+			status:           http.StatusNoContent,
+			path:             "/1?todos=cascade",
+			mockListsService: liststest.MockDelete(nil, nil),
+		},
+		{
+			name:             "move",
+			status:           http.StatusNoContent,
+			path:             "/1?todos=move&list_id=2",
+			mockListsService: liststest.MockDelete(&moveTo, nil),
+		},
+		{
+			name:             "move to inaccessible list",
+			status:           http.StatusUnprocessableEntity,
+			path:             "/1?todos=move&list_id=2",
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"List to move todos to not found","invalid_params":[{"name":"list_id","code":"not_found","reason":"List to move todos to not found"}]}`,
+			mockListsService: liststest.MockDelete(&moveTo, lists.ErrListMoveToNotFound),
+		},
+		{
+			name:             "not owner",
+			status:           http.StatusForbidden,
+			path:             "/1",
+			response:         `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
+			mockListsService: liststest.MockDelete(nil, lists.ErrForbidden),
+		},
+		{
+			name:     "move without list",
+			status:   http.StatusBadRequest,
+			path:     "/1?todos=move",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:     "unknown disposal",
+			status:   http.StatusBadRequest,
+			path:     "/1?todos=archive",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			mockListLoad(repository, lists.RoleOwner)
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Equal(t, "", rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_Members(t *testing.T) {
+	var (
+		req, _     = http.NewRequestWithContext(ctx, "GET", "/1/members", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = &liststest.Service{}
+		handler    = handler.NewLists(repository, service, nil)
+	)
+
+	mockListLoad(repository, lists.RoleViewer)
+	liststest.Mock(service, liststest.MockSearchMembers([]lists.Member{{ID: 1, ListID: 1, UserID: 1, Role: lists.RoleOwner}, {ID: 2, ListID: 1, UserID: 2, Role: lists.RoleViewer}}, nil))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"user_id":1, "role":"owner", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}, {"user_id":2, "role":"viewer", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`, rr.Body.String())
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestLists_CreateMember(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		status           int
+		payload          string
+		response         string
+		location         string
+		mockRepo         bool
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "created",
+			ctx:              ctx,
+			status:           http.StatusCreated,
+			payload:          `{"user_id": 2, "role": "editor"}`,
+			response:         `{"user_id":2, "role":"editor", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location:         "/1/members/2",
+			mockRepo:         true,
+			mockListsService: liststest.MockCreateMember(lists.Member{ID: 2, ListID: 1, UserID: 2, Role: lists.RoleEditor}, nil),
+		},
+		{
+			name:             "already a member",
+			ctx:              ctx,
+			status:           http.StatusUnprocessableEntity,
+			payload:          `{"user_id": 2, "role": "editor"}`,
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"User is already a member of the list","invalid_params":[{"name":"user_id","code":"taken","reason":"User is already a member of the list"}]}`,
+			mockRepo:         true,
+			mockListsService: liststest.MockCreateMember(lists.Member{}, lists.ErrMemberTaken),
+		},
+		{
+			name:             "not owner",
+			ctx:              ctx,
+			status:           http.StatusForbidden,
+			payload:          `{"user_id": 2, "role": "editor"}`,
+			response:         `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
+			mockRepo:         true,
+			mockListsService: liststest.MockCreateMember(lists.Member{}, lists.ErrForbidden),
+		},
+		{
+			name:     "api key",
+			ctx:      apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, UserID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosWrite}}),
+			status:   http.StatusForbidden,
+			payload:  `{"user_id": 2, "role": "editor"}`,
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"Forbidden"}`,
+		},
+		{
+			name:     "bad request",
+			ctx:      ctx,
+			status:   http.StatusBadRequest,
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(test.ctx, "POST", "/1/members", body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			req.RequestURI = "/1/members"
+
+			if test.mockRepo {
+				mockListLoad(repository, lists.RoleOwner)
+			}
+
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_UpdateMember(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		path             string
+		payload          string
+		response         string
+		found            bool
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "ok",
+			status:           http.StatusOK,
+			path:             "/1/members/2",
+			payload:          `{"role": "owner"}`,
+			response:         `{"user_id":2, "role":"owner", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			found:            true,
+			mockListsService: liststest.MockUpdateMember(lists.Member{ID: 2, ListID: 1, UserID: 2, Role: lists.RoleOwner}, nil),
+		},
+		{
+			name:     "user can't be changed",
+			status:   http.StatusOK,
+			path:     "/1/members/2",
+			payload:  `{"user_id": 3, "role": "owner"}`,
+			response: `{"user_id":2, "role":"owner", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			found:    true,
+			mockListsService: func(service *liststest.Service) {
+				service.On("UpdateMember", mock.Anything, &lists.Member{ID: 2, ListID: 1, UserID: 2, Role: lists.RoleOwner}, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:             "last owner",
+			status:           http.StatusUnprocessableEntity,
+			path:             "/1/members/2",
+			payload:          `{"role": "viewer"}`,
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"List must have at least one owner","invalid_params":[{"name":"role","code":"owner_required","reason":"List must have at least one owner"}]}`,
+			found:            true,
+			mockListsService: liststest.MockUpdateMember(lists.Member{ID: 2}, lists.ErrListOwnerRequired),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			path:     "/1/members/3",
+			payload:  `{"role": "viewer"}`,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/members/2",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			found:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "PATCH", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			mockListLoad(repository, lists.RoleOwner)
+			if test.found {
+				repository.ExpectFind(where.Eq("list_id", uint(1)), where.Eq("user_id", 2)).Result(lists.Member{ID: 2, ListID: 1, UserID: 2, Role: lists.RoleViewer})
+			} else {
+				repository.ExpectFind(where.Eq("list_id", uint(1)), where.Eq("user_id", 3)).NotFound()
+			}
+
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_DestroyMember(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		response         string
+		mockListsService liststest.MockFunc
+	}{
+		{
+			name:             "ok",
+			status:           http.StatusNoContent,
+			mockListsService: liststest.MockDeleteMember(nil),
+		},
+		{
+			name:             "last owner",
+			status:           http.StatusUnprocessableEntity,
+			response:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"List must have at least one owner","invalid_params":[{"name":"role","code":"owner_required","reason":"List must have at least one owner"}]}`,
+			mockListsService: liststest.MockDeleteMember(lists.ErrListOwnerRequired),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", "/1/members/2", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &liststest.Service{}
+				handler    = handler.NewLists(repository, service, nil)
+			)
+
+			mockListLoad(repository, lists.RoleOwner)
+			repository.ExpectFind(where.Eq("list_id", uint(1)), where.Eq("user_id", 2)).Result(lists.Member{ID: 2, ListID: 1, UserID: 2, Role: lists.RoleEditor})
+			liststest.Mock(service, test.mockListsService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Equal(t, "", rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLists_Todos(t *testing.T) {
+	var (
+		req, _       = http.NewRequestWithContext(ctx, "GET", "/1/todos", nil)
+		rr           = httptest.NewRecorder()
+		repository   = reltest.New()
+		service      = &liststest.Service{}
+		todosService = &todostest.Service{}
+		handler      = handler.NewLists(repository, service, handler.NewTodos(repository, todosService, nil))
+		scoped       = mock.MatchedBy(func(ctx context.Context) bool {
+			list, ok := lists.FromContext(ctx)
+			return ok && list.ID == 1
+		})
+	)
+
+	mockListLoad(repository, lists.RoleViewer)
+	todosService.On("Search", scoped, mock.Anything, todos.Filter{}).Return(nil)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `null`, rr.Body.String())
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+	todosService.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-chi/chi"
+	chimid "github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+)
+
+var (
+	httpRequests = metrics.Default.Counter("http_requests_total", "Total number of http requests by route and status.", "route", "status")
+	httpDuration = metrics.Default.Histogram("http_request_duration_seconds", "Duration of http requests in seconds by route and status.", metrics.DefaultBuckets, "route", "status")
+)
+
+// Metrics for metrics endpoint.
+type Metrics struct {
+	*chi.Mux
+	registry *metrics.Registry
+}
+
+// Show handle GET /
+func (m Metrics) Show(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(200)
+
+	if _, err := m.registry.WriteTo(w); err != nil {
+		logger.Warn("write metrics error", zap.Error(err), tracing.Field(r.Context()))
+	}
+}
+
+// NewMetrics handler.
+func NewMetrics(registry *metrics.Registry) Metrics {
+	h := Metrics{
+		Mux:      newMux(),
+		registry: registry,
+	}
+
+	h.Get("/", h.Show)
+
+	return h
+}
+
+// Instrument is middleware that measures count and duration of requests by chi route pattern and status.
+// route pattern is used instead of the path, so requests to different ids are measured together.
+func Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			start = time.Now()
+			ww    = chimid.NewWrapResponseWriter(w, r.ProtoMajor)
+		)
+
+		next.ServeHTTP(ww, r)
+
+		var (
+			route  = routePattern(r)
+			status = strconv.Itoa(responseStatus(ww))
+		)
+
+		httpRequests.Inc(route, status)
+		httpDuration.Observe(time.Since(start).Seconds(), route, status)
+	})
+}
+
+// routePattern returns chi route pattern that matched the request, it's only known after the request is routed.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		// index of mounted router is joined with an extra slash.
+		return strings.ReplaceAll(rctx.RoutePattern(), "//", "/")
+	}
+
+	return "unmatched"
+}
+
+// responseStatus returns written status, status is implicitly ok when handler doesn't write header.
+func responseStatus(ww chimid.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+
+	return 200
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Show(t *testing.T) {
+	var (
+		req, _   = http.NewRequest("GET", "/", nil)
+		rr       = httptest.NewRecorder()
+		registry = metrics.NewRegistry()
+		requests = registry.Counter("http_requests_total", "Total number of http requests.", "route", "status")
+		handler  = handler.NewMetrics(registry)
+	)
+
+	requests.Inc("/todos/", "200")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP http_requests_total Total number of http requests.
+# TYPE http_requests_total counter
+http_requests_total{route="/todos/",status="200"} 1
+`, rr.Body.String())
+}
+
+func TestInstrument(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		router = chi.NewRouter()
+		todos  = chi.NewRouter()
+	)
+
+	todos.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	todos.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	router.Use(handler.Instrument)
+	router.Mount("/instrumented", todos)
+
+	for _, path := range []string{"/instrumented/1", "/instrumented/2", "/instrumented/"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	metrics.Default.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `http_requests_total{route="/instrumented/{ID}",status="418"} 2`)
+	assert.Contains(t, buf.String(), `http_requests_total{route="/instrumented/",status="200"} 1`)
+	assert.Contains(t, buf.String(), `http_request_duration_seconds_count{route="/instrumented/{ID}",status="418"} 2`)
+}
@@ -2,16 +2,23 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/Fs02/go-todo-backend/apikeys"
 	"github.com/Fs02/go-todo-backend/scores"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-chi/chi"
 	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
 )
 
 // Score for score endpoints.
 type Score struct {
 	*chi.Mux
 	repository rel.Repository
+	scores     scores.Service
 }
 
 // Index handle GET /
@@ -21,7 +28,7 @@ func (s Score) Index(w http.ResponseWriter, r *http.Request) {
 		result scores.Score
 	)
 
-	s.repository.Find(ctx, &result)
+	s.repository.Find(ctx, &result, where.Eq("user_id", users.FromContext(ctx)))
 	render(w, result, 200)
 }
 
@@ -29,20 +36,50 @@ func (s Score) Index(w http.ResponseWriter, r *http.Request) {
 func (s Score) Points(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx    = r.Context()
+		query  = r.URL.Query()
 		result []scores.Point
+		filter = scores.PointFilter{
+			After: query.Get("after"),
+		}
 	)
 
-	s.repository.FindAll(ctx, &result)
+	if str := query.Get("limit"); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if err := s.scores.SearchPoints(ctx, &result, filter); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = scores.DefaultPointLimit
+	}
+
+	// a full page means there might be more points to fetch.
+	if len(result) == limit {
+		link(w, r, filter.Cursor(result[len(result)-1]))
+	}
+
 	render(w, result, 200)
 }
 
 // NewScore handler.
-func NewScore(repository rel.Repository) Score {
+func NewScore(repository rel.Repository, scores scores.Service) Score {
 	h := Score{
-		Mux:        chi.NewMux(),
+		Mux:        newMux(),
 		repository: repository,
+		scores:     scores,
 	}
 
+	h.Use(Scope(apikeys.ScopeScoreRead))
 	h.Get("/", h.Index)
 	h.Get("/points", h.Points)
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/Fs02/go-todo-backend/api/handler"
 	"github.com/Fs02/go-todo-backend/scores"
+	"github.com/Fs02/go-todo-backend/scores/scorestest"
+	"github.com/go-rel/rel/where"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,7 +27,7 @@ func TestScore_Index(t *testing.T) {
 			path:     "/",
 			response: `{"id":1, "total_point":10, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind().Result(scores.Score{ID: 1, TotalPoint: 10})
+				repo.ExpectFind(where.Eq("user_id", uint(1))).Result(scores.Score{ID: 1, TotalPoint: 10})
 			},
 		},
 	}
@@ -33,10 +35,10 @@ func TestScore_Index(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("GET", test.path, nil)
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
-				handler    = handler.NewScore(repository)
+				handler    = handler.NewScore(repository, &scorestest.Service{})
 			)
 
 			if test.mockRepo != nil {
@@ -53,42 +55,96 @@ func TestScore_Index(t *testing.T) {
 }
 
 func TestScore_Points(t *testing.T) {
+	var (
+		points = make([]scores.Point, scores.DefaultPointLimit)
+	)
+
+	for i := range points {
+		points[i] = scores.Point{ID: i + 1, Name: "todo completed", Count: 1}
+	}
+
 	tests := []struct {
-		name     string
-		status   int
-		path     string
-		response string
-		mockRepo func(repo *reltest.Repository)
+		name                   string
+		status                 int
+		path                   string
+		response               string
+		link                   string
+		mockScoresSearchPoints func(scores *scorestest.Service)
 	}{
 		{
 			name:     "ok",
 			status:   http.StatusOK,
 			path:     "/points",
 			response: `[{"id":1, "name": "todo completed", "count":1, "score_id": 0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
-			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFindAll().Result([]scores.Point{{ID: 1, Name: "todo completed", Count: 1}})
-			},
+			mockScoresSearchPoints: scorestest.MockSearchPoints(
+				[]scores.Point{{ID: 1, Name: "todo completed", Count: 1}},
+				scores.PointFilter{},
+				nil,
+			),
+		},
+		{
+			name:     "with limit",
+			status:   http.StatusOK,
+			path:     "/points?limit=1",
+			response: `[{"id":1, "name": "todo completed", "count":1, "score_id": 0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			link:     `</points?after=eyJpZCI6MX0&limit=1>; rel="next"`,
+			mockScoresSearchPoints: scorestest.MockSearchPoints(
+				[]scores.Point{{ID: 1, Name: "todo completed", Count: 1}},
+				scores.PointFilter{Limit: 1},
+				nil,
+			),
+		},
+		{
+			name:   "full default page",
+			status: http.StatusOK,
+			path:   "/points?after=eyJpZCI6MX0",
+			link:   `</points?after=eyJpZCI6NTB9>; rel="next"`,
+			mockScoresSearchPoints: scorestest.MockSearchPoints(
+				points,
+				scores.PointFilter{After: "eyJpZCI6MX0"},
+				nil,
+			),
+		},
+		{
+			name:     "invalid limit",
+			status:   http.StatusBadRequest,
+			path:     "/points?limit=ten",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:     "invalid cursor",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/points?after=invalid",
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Cursor is invalid","invalid_params":[{"name":"after","code":"invalid","reason":"Cursor is invalid"}]}`,
+			mockScoresSearchPoints: scorestest.MockSearchPoints(
+				nil,
+				scores.PointFilter{After: "invalid"},
+				scores.ErrPointCursorInvalid,
+			),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("GET", test.path, nil)
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
-				handler    = handler.NewScore(repository)
+				scores     = &scorestest.Service{}
+				handler    = handler.NewScore(repository, scores)
 			)
 
-			if test.mockRepo != nil {
-				test.mockRepo(repository)
-			}
+			scorestest.Mock(scores, test.mockScoresSearchPoints)
 
 			handler.ServeHTTP(rr, req)
 			assert.Equal(t, test.status, rr.Code)
-			assert.JSONEq(t, test.response, rr.Body.String())
+			assert.Equal(t, test.link, rr.Header().Get("Link"))
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			}
 
 			repository.AssertExpectations(t)
+			scores.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,448 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/websocket"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
+)
+
+var (
+	// syncPingInterval is how often connection is pinged, it must be shorter than syncPongWait.
+	syncPingInterval = 30 * time.Second
+	// syncPongWait is how long connection may be silent before it's considered broken.
+	syncPongWait = 60 * time.Second
+	// syncQueueSize is number of responses waiting to be written, connection that doesn't read them is closed.
+	syncQueueSize = 16
+
+	syncConnections = metrics.Default.Gauge("websocket_connections", "Number of open websocket connections.")
+)
+
+// syncRequest is a message sent by client, id is echoed in the response so client can match them.
+type syncRequest struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Lists  []uint          `json:"lists"`
+	TodoID uint            `json:"todo_id"`
+	ETag   string          `json:"etag"`
+	Todo   json.RawMessage `json:"todo"`
+}
+
+// syncResponse is a message sent to client, it's either result or error of a request, or an event.
+type syncResponse struct {
+	Type  string        `json:"type"`
+	ID    string        `json:"id,omitempty"`
+	Data  interface{}   `json:"data,omitempty"`
+	Error *problem      `json:"error,omitempty"`
+	Event *events.Event `json:"event,omitempty"`
+}
+
+// Sync for websocket endpoint, client subscribes to lists to receive their changes, and sends mutations through the same connection.
+//
+// requests are {"id", "type", ...} where type is one of:
+//   - subscribe and unsubscribe with "lists" ids.
+//   - create with "todo".
+//   - update with "todo_id", "todo" changes and optional "etag" precondition.
+//   - delete with "todo_id" and optional "etag" precondition.
+//
+// each request is answered with {"type": "result", "id", "data"} or {"type": "error", "id", "error"} where error is problem details,
+// and changes are sent as {"type": "event", "event"}, changes of the user's todos outside any list are always sent.
+type Sync struct {
+	*chi.Mux
+	repository rel.Repository
+	todos      todos.Service
+	lists      lists.Service
+	policy     todos.Policy
+	bus        *events.Bus
+	group      *websocket.Group
+}
+
+// Connect handle GET /, it upgrades the request to websocket connection that lasts until either side closes it.
+func (s Sync) Connect(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+	)
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		logger.Warn("upgrade error", zap.Error(err), tracing.Field(ctx))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+	defer conn.Close()
+
+	if !s.group.Add(conn) {
+		conn.WriteClose(websocket.CloseGoingAway, "server shutting down")
+		return
+	}
+	defer s.group.Done(conn)
+
+	syncConnections.Add(1)
+	defer syncConnections.Add(-1)
+
+	var (
+		session   = newSyncSession(ctx, conn, s.lists)
+		sub, _, _ = s.bus.Subscribe(session.visible, 0)
+		done      = make(chan struct{})
+		written   = make(chan struct{})
+	)
+	defer sub.Close()
+
+	go func() {
+		session.write(sub, done)
+		close(written)
+	}()
+
+	s.read(session)
+
+	close(done)
+	<-written
+}
+
+// read handles requests one at a time, so client that sends faster than they're handled is held back by the connection.
+func (s Sync) read(session *syncSession) {
+	var (
+		conn = session.conn
+	)
+
+	conn.SetReadDeadline(time.Now().Add(syncPongWait))
+	conn.SetPongHandler(func([]byte) {
+		conn.SetReadDeadline(time.Now().Add(syncPongWait))
+	})
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				logger.Warn("read error", zap.Error(err), tracing.Field(session.ctx))
+			}
+			return
+		}
+
+		conn.SetReadDeadline(time.Now().Add(syncPongWait))
+
+		if messageType != websocket.TextMessage {
+			conn.WriteClose(websocket.CloseUnsupportedData, "only text message is supported")
+			continue
+		}
+
+		var req syncRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			logger.Warn("decode error", zap.Error(err), tracing.Field(session.ctx))
+			session.fail(req.ID, ErrBadRequest)
+			continue
+		}
+
+		if result, err := s.handle(session, req); err != nil {
+			session.fail(req.ID, err)
+		} else {
+			session.enqueue(syncResponse{Type: "result", ID: req.ID, Data: result})
+		}
+	}
+}
+
+// handle a request in its own span, so each mutation can be traced separately from the connection.
+func (s Sync) handle(session *syncSession, req syncRequest) (interface{}, error) {
+	ctx, span := tracing.Start(session.ctx, "WS "+req.Type)
+	span.SetKind(tracing.KindServer)
+	defer span.End()
+
+	result, err := s.dispatch(ctx, session, req)
+	return result, span.Record(err)
+}
+
+// dispatch request to the service, mutations are done by todos service so they're validated and scored the same way as http endpoints.
+func (s Sync) dispatch(ctx context.Context, session *syncSession, req syncRequest) (interface{}, error) {
+	switch req.Type {
+	case "subscribe":
+		return session.subscribe(ctx, req.Lists)
+	case "unsubscribe":
+		return session.unsubscribe(req.Lists), nil
+	case "create", "update", "delete":
+		if !granted(ctx, apikeys.ScopeTodosWrite) {
+			return nil, ErrForbidden
+		}
+	default:
+		return nil, ErrBadRequest
+	}
+
+	if req.Type == "create" {
+		var todo todos.Todo
+		if err := decodeTodoBody(ctx, bytes.NewReader(req.Todo), &todo); err != nil {
+			return nil, err
+		}
+
+		if err := s.todos.Create(ctx, &todo); err != nil {
+			return nil, err
+		}
+
+		return todo, nil
+	}
+
+	todo, err := s.load(ctx, req.TodoID, req.ETag)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type == "delete" {
+		return nil, s.todos.Delete(ctx, &todo)
+	}
+
+	changes := rel.NewChangeset(&todo)
+	if err := decodeTodoBody(ctx, bytes.NewReader(req.Todo), &todo); err != nil {
+		return nil, err
+	}
+
+	if err := s.todos.Update(ctx, &todo, changes); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// load todo that's going to be changed, etag is checked the same way as If-Match header.
+func (s Sync) load(ctx context.Context, id uint, etag string) (todos.Todo, error) {
+	var (
+		todo todos.Todo
+	)
+
+	if err := s.repository.Find(ctx, &todo, where.Eq("id", id), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")); err != nil {
+		return todo, err
+	}
+
+	if err := s.policy.Authorize(ctx, todo, lists.ActionWrite); err != nil {
+		return todo, err
+	}
+
+	if etag != "" && !etagMatch(etag, todo.ETag(), false) {
+		logger.Warn("precondition failed", zap.String("etag", etag), zap.String("current", todo.ETag()), tracing.Field(ctx))
+		return todo, todos.ErrTodoConflict
+	}
+
+	return todo, nil
+}
+
+// NewSync handler, connections are added to group so they can be closed on shutdown.
+func NewSync(repository rel.Repository, todosService todos.Service, listsService lists.Service, bus *events.Bus, group *websocket.Group) Sync {
+	h := Sync{
+		Mux:        newMux(),
+		repository: repository,
+		todos:      todosService,
+		lists:      listsService,
+		policy:     todos.NewPolicy(repository),
+		bus:        bus,
+		group:      group,
+	}
+
+	h.With(Scope(apikeys.ScopeTodosRead)).Get("/", h.Connect)
+
+	return h
+}
+
+// syncSession is state of a websocket connection.
+type syncSession struct {
+	ctx    context.Context
+	conn   *websocket.Conn
+	userID uint
+	// score is set if score changes may be sent, api key must be granted to read the score.
+	score bool
+	lists lists.Service
+	send  chan []byte
+
+	mu         sync.Mutex
+	subscribed map[uint]bool
+}
+
+// visible filters events of subscribed lists, and events of the user's todos outside any list.
+func (s *syncSession) visible(event events.Event) bool {
+	if event.ListID == nil {
+		return event.UserID == s.userID && (event.Type != events.ScoreChanged || s.score)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.subscribed[*event.ListID]
+}
+
+// subscribe to lists, it fails without subscribing any list if the user isn't a member of any of them.
+// membership is checked when subscribing rather than when connecting, so list joined afterward can be subscribed.
+func (s *syncSession) subscribe(ctx context.Context, ids []uint) ([]uint, error) {
+	for _, id := range ids {
+		if err := s.lists.Authorize(ctx, id, lists.ActionRead); err != nil {
+			if errors.Is(err, rel.ErrNotFound) {
+				logger.Warn("forbidden", zap.Uint("list_id", id), tracing.Field(ctx))
+				return nil, lists.ErrForbidden
+			}
+
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.subscribed[id] = true
+	}
+
+	return s.subscriptions(), nil
+}
+
+func (s *syncSession) unsubscribe(ids []uint) []uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.subscribed, id)
+	}
+
+	return s.subscriptions()
+}
+
+// authorized checks membership of the list before its event is written, so member removed from the list stops receiving its events.
+// the list is unsubscribed once the user is no longer a member of it.
+func (s *syncSession) authorized(event events.Event) (bool, error) {
+	if event.ListID == nil {
+		return true, nil
+	}
+
+	if err := s.lists.Authorize(s.ctx, *event.ListID, lists.ActionRead); err != nil {
+		if errors.Is(err, rel.ErrNotFound) || errors.Is(err, lists.ErrForbidden) {
+			s.unsubscribe([]uint{*event.ListID})
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// subscriptions returns sorted ids of subscribed lists, mu must be held.
+func (s *syncSession) subscriptions() []uint {
+	ids := make([]uint, 0, len(s.subscribed))
+	for id := range s.subscribed {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// fail responds with problem details of err.
+func (s *syncSession) fail(id string, err error) {
+	err = dberr.Classify(err)
+
+	status := errorStatus(err)
+	if status >= 500 {
+		logger.Error("internal error", zap.Error(err), tracing.Field(s.ctx))
+	}
+
+	problem := newProblem(err, status)
+	s.enqueue(syncResponse{Type: "error", ID: id, Error: &problem})
+}
+
+// enqueue response without blocking, client that doesn't keep up with its responses is disconnected.
+func (s *syncSession) enqueue(response syncResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("encode error", zap.Error(err), tracing.Field(s.ctx))
+		return
+	}
+
+	select {
+	case s.send <- data:
+	default:
+		logger.Warn("slow connection closed", tracing.Field(s.ctx))
+		s.conn.WriteClose(websocket.CloseTryAgainLater, "too slow")
+	}
+}
+
+// write is the only writer of messages to the connection, it also pings the client to keep the connection alive.
+func (s *syncSession) write(sub *events.Subscription, done <-chan struct{}) {
+	ticker := time.NewTicker(syncPingInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				// subscription of slow client is dropped by the bus, it has to reconnect and reload its todos.
+				if sub.Dropped {
+					s.conn.WriteClose(websocket.CloseTryAgainLater, "too slow")
+				} else {
+					s.conn.WriteClose(websocket.CloseGoingAway, "server shutting down")
+				}
+				return
+			}
+
+			authorized, err := s.authorized(event)
+			if err != nil {
+				// event can't be checked, client has to reconnect and reload its todos so it doesn't miss the event.
+				logger.Error("authorize error", zap.Error(err), tracing.Field(s.ctx))
+				s.conn.WriteClose(websocket.CloseTryAgainLater, "try again later")
+				return
+			}
+
+			if !authorized {
+				continue
+			}
+
+			var data []byte
+			if data, err = json.Marshal(syncResponse{Type: "event", Event: &event}); err == nil {
+				err = s.conn.WriteMessage(websocket.TextMessage, data)
+			}
+		case data := <-s.send:
+			err = s.conn.WriteMessage(websocket.TextMessage, data)
+		case <-ticker.C:
+			err = s.conn.WriteControl(websocket.PingMessage, nil)
+		case <-done:
+			return
+		}
+
+		if err != nil {
+			// nothing can be written once closing handshake is started, the reader finishes the handshake.
+			if err != websocket.ErrClosed {
+				logger.Warn("write error", zap.Error(err), tracing.Field(s.ctx))
+				s.conn.Close()
+			}
+			return
+		}
+	}
+}
+
+func newSyncSession(ctx context.Context, conn *websocket.Conn, lists lists.Service) *syncSession {
+	key, ok := apikeys.FromContext(ctx)
+
+	return &syncSession{
+		ctx:        ctx,
+		conn:       conn,
+		userID:     users.FromContext(ctx),
+		score:      !ok || key.Scopes.Contains(apikeys.ScopeScoreRead),
+		lists:      lists,
+		send:       make(chan []byte, syncQueueSize),
+		subscribed: make(map[uint]bool),
+	}
+}
@@ -0,0 +1,313 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/lists/liststest"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/todos/todostest"
+	"github.com/Fs02/go-todo-backend/websocket"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// dialSync serves the handler as the authenticated user, and opens websocket connection to it.
+func dialSync(t *testing.T, h http.Handler) (*websocket.Conn, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}))
+
+	conn, _, err := websocket.Dial(context.TODO(), "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	return conn, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) string {
+	_, data, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestSync_Connect(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       string
+		response      string
+		mockRepo      func(repo *reltest.Repository)
+		mockTodosFunc todostest.MockFunc
+		mockListsFunc []liststest.MockFunc
+	}{
+		{
+			name:          "subscribe",
+			request:       `{"id":"1", "type":"subscribe", "lists":[2]}`,
+			response:      `{"type":"result", "id":"1", "data":[2]}`,
+			mockListsFunc: []liststest.MockFunc{liststest.MockAuthorize(2, lists.ActionRead, nil)},
+		},
+		{
+			name:     "subscribe forbidden",
+			request:  `{"id":"1", "type":"subscribe", "lists":[2, 3]}`,
+			response: `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Forbidden", "status":403, "detail":"You are not allowed to do this on the list"}}`,
+			mockListsFunc: []liststest.MockFunc{
+				liststest.MockAuthorize(2, lists.ActionRead, nil),
+				liststest.MockAuthorize(3, lists.ActionRead, rel.ErrNotFound),
+			},
+		},
+		{
+			name:          "subscribe unavailable",
+			request:       `{"id":"1", "type":"subscribe", "lists":[2]}`,
+			response:      `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Service Unavailable", "status":503}}`,
+			mockListsFunc: []liststest.MockFunc{liststest.MockAuthorize(2, lists.ActionRead, dberr.ErrUnavailable)},
+		},
+		{
+			name:     "unsubscribe",
+			request:  `{"id":"1", "type":"unsubscribe", "lists":[2]}`,
+			response: `{"type":"result", "id":"1", "data":[]}`,
+		},
+		{
+			name:          "create",
+			request:       `{"id":"1", "type":"create", "todo":{"title":"Sleep"}}`,
+			response:      `{"type":"result", "id":"1", "data":{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}}`,
+			mockTodosFunc: todostest.MockCreate(todos.Todo{ID: 1, Title: "Sleep"}, nil),
+		},
+		{
+			name:     "create with id",
+			request:  `{"id":"1", "type":"create", "todo":{"id":2, "title":"Sleep"}}`,
+			response: `{"type":"result", "id":"1", "data":{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}}`,
+			mockTodosFunc: func(service *todostest.Service) {
+				// id is assigned by database, not by client.
+				service.On("Create", mock.Anything, mock.MatchedBy(func(todo *todos.Todo) bool { return todo.ID == 0 })).
+					Return(func(ctx context.Context, out *todos.Todo) error {
+						out.ID = 1
+						return nil
+					})
+			},
+		},
+		{
+			name:          "create validation error",
+			request:       `{"id":"1", "type":"create", "todo":{"title":""}}`,
+			response:      `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Unprocessable Entity", "status":422, "detail":"Title can't be blank", "invalid_params":[{"name":"title", "code":"blank", "reason":"Title can't be blank"}]}}`,
+			mockTodosFunc: todostest.MockCreate(todos.Todo{}, todos.ErrTodoTitleBlank),
+		},
+		{
+			name:     "create without todo",
+			request:  `{"id":"1", "type":"create"}`,
+			response: `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Bad Request", "status":400, "detail":"Bad Request"}}`,
+		},
+		{
+			name:     "update",
+			request:  `{"id":"1", "type":"update", "todo_id":1, "etag":"\"0\"", "todo":{"title":"Wake up"}}`,
+			response: `{"type":"result", "id":"1", "data":{"id":1, "title":"Wake up", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", uint(1)), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosFunc: todostest.MockUpdate(todos.Todo{ID: 1, Title: "Wake up"}, nil),
+		},
+		{
+			name:     "update with id",
+			request:  `{"id":"1", "type":"update", "todo_id":1, "todo":{"id":2, "title":"Wake up"}}`,
+			response: `{"type":"result", "id":"1", "data":{"id":1, "title":"Wake up", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", uint(1)), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosFunc: todostest.MockUpdate(todos.Todo{ID: 1, Title: "Wake up"}, nil),
+		},
+		{
+			name:     "update precondition failed",
+			request:  `{"id":"1", "type":"update", "todo_id":1, "etag":"\"1\"", "todo":{"title":"Wake up"}}`,
+			response: `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Precondition Failed", "status":412, "detail":"Todo has been modified, reload it and try again"}}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", uint(1)), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+		},
+		{
+			name:     "update not found",
+			request:  `{"id":"1", "type":"update", "todo_id":1, "todo":{"title":"Wake up"}}`,
+			response: `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Not Found", "status":404, "detail":"entity not found"}}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", uint(1)), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 2, Title: "Sleep"})
+			},
+		},
+		{
+			name:     "delete",
+			request:  `{"id":"1", "type":"delete", "todo_id":1}`,
+			response: `{"type":"result", "id":"1"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", uint(1)), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosFunc: todostest.MockDelete(nil),
+		},
+		{
+			name:     "unknown type",
+			request:  `{"id":"1", "type":"archive"}`,
+			response: `{"type":"error", "id":"1", "error":{"type":"about:blank", "title":"Bad Request", "status":400, "detail":"Bad Request"}}`,
+		},
+		{
+			name:     "invalid json",
+			request:  `{`,
+			response: `{"type":"error", "error":{"type":"about:blank", "title":"Bad Request", "status":400, "detail":"Bad Request"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				repository   = reltest.New()
+				todosService = &todostest.Service{}
+				listsService = &liststest.Service{}
+				handler      = handler.NewSync(repository, todosService, listsService, events.NewBus(8), websocket.NewGroup())
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todosService, test.mockTodosFunc)
+			liststest.Mock(listsService, test.mockListsFunc...)
+
+			conn, close := dialSync(t, handler)
+			defer close()
+
+			assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(test.request)))
+			assert.JSONEq(t, test.response, readMessage(t, conn))
+
+			// server answers closing handshake once it's done with the connection.
+			assert.Nil(t, conn.WriteClose(websocket.CloseNormal, ""))
+			_, _, err := conn.ReadMessage()
+			assert.Equal(t, &websocket.CloseError{Code: websocket.CloseNormal}, err)
+
+			repository.AssertExpectations(t)
+			todosService.AssertExpectations(t)
+			listsService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSync_Connect_events(t *testing.T) {
+	var (
+		bus          = events.NewBus(8)
+		listsService = &liststest.Service{}
+		group        = websocket.NewGroup()
+		handler      = handler.NewSync(reltest.New(), &todostest.Service{}, listsService, bus, group)
+		listID       = uint(2)
+		otherID      = uint(3)
+	)
+
+	// the user is removed from the list after the first event of it.
+	listsService.On("Authorize", mock.Anything, listID, lists.ActionRead).Return(nil).Twice()
+	listsService.On("Authorize", mock.Anything, listID, lists.ActionRead).Return(rel.ErrNotFound).Once()
+
+	conn, close := dialSync(t, handler)
+	defer close()
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"1", "type":"subscribe", "lists":[2]}`)))
+	assert.JSONEq(t, `{"type":"result", "id":"1", "data":[2]}`, readMessage(t, conn))
+
+	// only events of subscribed lists and the user's own todos are received.
+	bus.Publish(context.TODO(),
+		events.New(events.TodoCreated, 2, &otherID, map[string]int{"id": 1}),
+		events.New(events.TodoCreated, 2, nil, map[string]int{"id": 2}),
+		events.New(events.TodoUpdated, 2, &listID, map[string]int{"id": 3}),
+		events.New(events.ScoreChanged, 1, nil, map[string]int{"total_point": 1}),
+	)
+
+	var message struct {
+		Type  string
+		Event events.Event
+	}
+
+	assert.Nil(t, json.Unmarshal([]byte(readMessage(t, conn)), &message))
+	assert.Equal(t, "event", message.Type)
+	assert.Equal(t, events.TodoUpdated, message.Event.Type)
+	assert.JSONEq(t, `{"id":3}`, string(message.Event.Data))
+
+	assert.Nil(t, json.Unmarshal([]byte(readMessage(t, conn)), &message))
+	assert.Equal(t, events.ScoreChanged, message.Event.Type)
+
+	// events of the list are no longer received, and the list is unsubscribed.
+	bus.Publish(context.TODO(),
+		events.New(events.TodoUpdated, 2, &listID, map[string]int{"id": 3}),
+		events.New(events.TodoDeleted, 1, nil, map[string]int{"id": 4}),
+	)
+
+	assert.Nil(t, json.Unmarshal([]byte(readMessage(t, conn)), &message))
+	assert.Equal(t, events.TodoDeleted, message.Event.Type)
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"2", "type":"unsubscribe", "lists":[]}`)))
+	assert.JSONEq(t, `{"type":"result", "id":"2", "data":[]}`, readMessage(t, conn))
+
+	// server closes the connection when it's shutting down, and waits for the closing handshake.
+	done := make(chan error)
+	go func() {
+		done <- group.Shutdown(context.TODO())
+	}()
+
+	_, _, err := conn.ReadMessage()
+	assert.Equal(t, &websocket.CloseError{Code: websocket.CloseGoingAway, Reason: "server shutting down"}, err)
+	assert.Nil(t, <-done)
+}
+
+func TestSync_Connect_badHandshake(t *testing.T) {
+	var (
+		req, _  = http.NewRequestWithContext(ctx, "GET", "/", nil)
+		rr      = httptest.NewRecorder()
+		handler = handler.NewSync(reltest.New(), &todostest.Service{}, &liststest.Service{}, nil, websocket.NewGroup())
+	)
+
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"type":"about:blank", "title":"Bad Request", "status":400, "detail":"Bad Request"}`, rr.Body.String())
+}
+
+func TestSync_Connect_score(t *testing.T) {
+	var (
+		bus     = events.NewBus(8)
+		key     = apikeys.APIKey{ID: 1, UserID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}}
+		handler = handler.NewSync(reltest.New(), &todostest.Service{}, &liststest.Service{}, bus, websocket.NewGroup())
+		// api key that isn't granted to read the score.
+		scoped = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, r.WithContext(apikeys.NewContext(r.Context(), key)))
+		})
+	)
+
+	conn, close := dialSync(t, scoped)
+	defer close()
+
+	// subscription is made once the connection is handled, the response tells it's ready.
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"1", "type":"unsubscribe", "lists":[]}`)))
+	assert.JSONEq(t, `{"type":"result", "id":"1", "data":[]}`, readMessage(t, conn))
+
+	bus.Publish(context.TODO(),
+		events.New(events.ScoreChanged, 1, nil, map[string]int{"total_point": 1}),
+		events.New(events.TodoDeleted, 1, nil, map[string]int{"id": 4}),
+	)
+
+	var message struct {
+		Type  string
+		Event events.Event
+	}
+
+	assert.Nil(t, json.Unmarshal([]byte(readMessage(t, conn)), &message))
+	assert.Equal(t, events.TodoDeleted, message.Event.Type)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
+)
+
+// Tags for tags endpoints.
+type Tags struct {
+	*chi.Mux
+	repository rel.Repository
+	todos      todos.Service
+}
+
+// Index handle GET /.
+func (t Tags) Index(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []todos.Tag
+	)
+
+	if err := t.todos.SearchTags(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Create handle POST /
+func (t Tags) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		tag todos.Tag
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := t.todos.CreateTag(ctx, &tag); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", tag.ID))
+	render(w, tag, 201)
+}
+
+// Destroy handle DELETE /{ID}
+func (t Tags) Destroy(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		tag = ctx.Value(loadTagKey).(todos.Tag)
+	)
+
+	if err := t.todos.DeleteTag(ctx, &tag); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Load is middleware that loads tags to context.
+func (t Tags) Load(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   = r.Context()
+			id, _ = strconv.Atoi(chi.URLParam(r, "ID"))
+			tag   todos.Tag
+		)
+
+		if err := t.repository.Find(ctx, &tag, where.Eq("id", id), where.Eq("user_id", users.FromContext(ctx))); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadTagKey, tag)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewTags handler.
+func NewTags(repository rel.Repository, todos todos.Service) Tags {
+	var (
+		// tags are part of todos, so they share the todos scopes.
+		read  = Scope(apikeys.ScopeTodosRead)
+		write = Scope(apikeys.ScopeTodosWrite)
+	)
+
+	h := Tags{
+		Mux:        newMux(),
+		repository: repository,
+		todos:      todos,
+	}
+
+	h.With(read).Get("/", h.Index)
+	h.With(write).Post("/", h.Create)
+	h.With(write, h.Load).Delete("/{ID}", h.Destroy)
+
+	return h
+}
@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/todos/todostest"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags_Index(t *testing.T) {
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		response            string
+		mockTodosSearchTags func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/",
+			response: `[{"id":1, "name":"work", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockTodosSearchTags: todostest.MockSearchTags(
+				[]todos.Tag{{ID: 1, Name: "work"}},
+				nil,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTags(repository, todos)
+			)
+
+			todostest.Mock(todos, test.mockTodosSearchTags)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTags_Create(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             int
+		path               string
+		payload            string
+		response           string
+		location           string
+		mockTodosCreateTag func(todos *todostest.Service)
+	}{
+		{
+			name:     "created",
+			status:   http.StatusCreated,
+			path:     "/",
+			payload:  `{"name": "work"}`,
+			response: `{"id":1, "name":"work", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1",
+			mockTodosCreateTag: todostest.MockCreateTag(
+				todos.Tag{ID: 1, Name: "work"},
+				nil,
+			),
+		},
+		{
+			name:     "name taken",
+			status:   http.StatusConflict,
+			path:     "/",
+			payload:  `{"name": "work"}`,
+			response: `{"type":"about:blank","title":"Conflict","status":409,"detail":"Tag name has already been taken"}`,
+			mockTodosCreateTag: todostest.MockCreateTag(
+				todos.Tag{Name: "work"},
+				todos.ErrTagNameTaken,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTags(repository, todos)
+			)
+
+			todostest.Mock(todos, test.mockTodosCreateTag)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTags_Destroy(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             int
+		path               string
+		response           string
+		mockRepo           func(repo *reltest.Repository)
+		mockTodosDeleteTag func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusNoContent,
+			path:     "/1",
+			response: "",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).Result(todos.Tag{ID: 1, Name: "work"})
+			},
+			mockTodosDeleteTag: todostest.MockDeleteTag(nil),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			path:     "/1",
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTags(repository, todos)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosDeleteTag)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Equal(t, "", rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-chi/chi"
+)
+
+// Tickets for tickets endpoints.
+type Tickets struct {
+	*chi.Mux
+	tickets auth.Tickets
+}
+
+// Create handle POST /, the ticket authenticates event stream and websocket request through ticket query parameter until it expires.
+func (t Tickets) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		ticket, expires = t.tickets.Issue(users.FromContext(ctx))
+	)
+
+	render(w, struct {
+		Ticket    string    `json:"ticket"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{
+		Ticket:    ticket,
+		ExpiresAt: expires,
+	}, 201)
+}
+
+// NewTickets handler.
+func NewTickets(tickets auth.Tickets) Tickets {
+	h := Tickets{
+		Mux:     newMux(),
+		tickets: tickets,
+	}
+
+	// ticket isn't limited to the scopes of api key, so it's only issued to interactive login.
+	h.With(Interactive).Post("/", h.Create)
+
+	return h
+}
@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickets_Create(t *testing.T) {
+	var (
+		req, _  = http.NewRequestWithContext(ctx, "POST", "/", nil)
+		rr      = httptest.NewRecorder()
+		handler = handler.NewTickets(auth.NewTickets([]byte("ticket")))
+		body    struct {
+			Ticket string `json:"ticket"`
+		}
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &body))
+
+	claims, err := auth.NewHS256([]byte("ticket")).Verify(body.Ticket)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", claims.Subject)
+}
+
+func TestTickets_Create_apiKey(t *testing.T) {
+	var (
+		ctx     = apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, UserID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}})
+		req, _  = http.NewRequestWithContext(ctx, "POST", "/", nil)
+		rr      = httptest.NewRecorder()
+		handler = handler.NewTickets(auth.NewTickets([]byte("ticket")))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Forbidden","status":403,"detail":"Forbidden"}`, rr.Body.String())
+}
@@ -5,10 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/reminders"
 	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-chi/chi"
 	"github.com/go-rel/rel"
 	"github.com/go-rel/rel/where"
@@ -18,8 +26,16 @@ import (
 type ctx int
 
 const (
-	bodyKey ctx = 0
-	loadKey ctx = 1
+	bodyKey         ctx = 0
+	loadKey         ctx = 1
+	loadTagKey      ctx = 2
+	loadItemKey     ctx = 3
+	loadAPIKeyKey   ctx = 4
+	loadListKey     ctx = 5
+	loadMemberKey   ctx = 6
+	loadWebhookKey  ctx = 7
+	loadDeliveryKey ctx = 8
+	loadReminderKey ctx = 9
 )
 
 // Todos for todos endpoints.
@@ -27,6 +43,8 @@ type Todos struct {
 	*chi.Mux
 	repository rel.Repository
 	todos      todos.Service
+	reminders  reminders.Service
+	policy     todos.Policy
 }
 
 // Index handle GET /.
@@ -36,16 +54,86 @@ func (t Todos) Index(w http.ResponseWriter, r *http.Request) {
 		query  = r.URL.Query()
 		result []todos.Todo
 		filter = todos.Filter{
-			Keyword: query.Get("keyword"),
+			Keyword:   query.Get("keyword"),
+			Highlight: query.Get("highlight") == "true",
 		}
 	)
 
+	if str := query.Get("list_id"); str != "" {
+		listID, err := strconv.ParseUint(str, 10, 0)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		filter.ListID = uint(listID)
+	}
+
 	if str := query.Get("completed"); str != "" {
 		completed := str == "true"
 		filter.Completed = &completed
 	}
 
-	t.todos.Search(ctx, &result, filter)
+	if str := query.Get("due_before"); str != "" {
+		dueBefore, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		filter.DueBefore = &dueBefore
+	}
+
+	if str := query.Get("due_after"); str != "" {
+		dueAfter, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		filter.DueAfter = &dueAfter
+	}
+
+	filter.Overdue = query.Get("overdue") == "true"
+
+	if str := query.Get("tags"); str != "" {
+		filter.Tags = strings.Split(str, ",")
+		filter.MatchAllTags = query.Get("tags_match") == "all"
+	}
+
+	if str := query.Get("sort"); str != "" {
+		filter.Sort = strings.Split(str, ",")
+	}
+
+	if str := query.Get("limit"); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil {
+			logger.Warn("parse error", zap.Error(err), tracing.Field(r.Context()))
+			render(w, ErrBadRequest, 400)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	filter.After = query.Get("after")
+
+	if err := t.todos.Search(ctx, &result, filter); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = todos.DefaultLimit
+	}
+
+	// a full page means there might be more todos to fetch.
+	if len(result) == limit {
+		if cursor := t.todos.Cursor(filter, result[len(result)-1]); cursor != "" {
+			link(w, r, cursor)
+		}
+	}
+
 	render(w, result, 200)
 }
 
@@ -56,21 +144,60 @@ func (t Todos) Create(w http.ResponseWriter, r *http.Request) {
 		todo todos.Todo
 	)
 
-	if err := json.NewDecoder(r.Body).Decode(&todo); err != nil {
-		logger.Warn("decode error", zap.Error(err))
-		render(w, ErrBadRequest, 400)
+	if err := decodeTodoBody(ctx, r.Body, &todo); err != nil {
+		renderError(w, r, err)
 		return
 	}
 
 	if err := t.todos.Create(ctx, &todo); err != nil {
-		render(w, err, 422)
+		renderError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", todo.ID))
+	w.Header().Set("ETag", todo.ETag())
 	render(w, todo, 201)
 }
 
+// Bulk handle POST /bulk
+func (t Todos) Bulk(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		body struct {
+			Atomic     bool                  `json:"atomic"`
+			Operations []todos.BulkOperation `json:"operations"`
+		}
+		response struct {
+			Results []todos.BulkResult `json:"results"`
+		}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := t.todos.Bulk(ctx, &response.Results, body.Operations, body.Atomic); err != nil {
+		if !errors.Is(err, todos.ErrBulkAborted) {
+			renderError(w, r, err)
+			return
+		}
+
+		// results are included, so client can tell which operation failed.
+		renderProblem(w, struct {
+			problem
+			Results []todos.BulkResult `json:"results"`
+		}{
+			problem: newProblem(err, 422),
+			Results: response.Results,
+		}, 422)
+		return
+	}
+
+	render(w, response, 200)
+}
+
 // Show handle GET /{ID}
 func (t Todos) Show(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -78,6 +205,13 @@ func (t Todos) Show(w http.ResponseWriter, r *http.Request) {
 		todo = ctx.Value(loadKey).(todos.Todo)
 	)
 
+	w.Header().Set("ETag", todo.ETag())
+
+	if header := r.Header.Get("If-None-Match"); header != "" && etagMatch(header, todo.ETag(), true) {
+		w.WriteHeader(304)
+		return
+	}
+
 	render(w, todo, 200)
 }
 
@@ -89,20 +223,247 @@ func (t Todos) Update(w http.ResponseWriter, r *http.Request) {
 		changes = rel.NewChangeset(&todo)
 	)
 
-	if err := json.NewDecoder(r.Body).Decode(&todo); err != nil {
-		logger.Warn("decode error", zap.Error(err))
-		render(w, ErrBadRequest, 400)
+	if !t.preconditionMet(w, r, todo) {
+		return
+	}
+
+	if err := decodeTodoBody(ctx, r.Body, &todo); err != nil {
+		renderError(w, r, err)
 		return
 	}
 
 	if err := t.todos.Update(ctx, &todo, changes); err != nil {
-		render(w, err, 422)
+		renderError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", todo.ETag())
 	render(w, todo, 200)
 }
 
+// Move handle POST /{ID}/move
+func (t Todos) Move(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		todo     = ctx.Value(loadKey).(todos.Todo)
+		position todos.Position
+	)
+
+	if !t.preconditionMet(w, r, todo) {
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&position); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := t.todos.Move(ctx, &todo, position); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", todo.ETag())
+	render(w, todo, 200)
+}
+
+// UpdateTags handle PUT /{ID}/tags
+func (t Todos) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+		body struct {
+			Tags []string `json:"tags"`
+		}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := t.todos.SetTags(ctx, &todo, body.Tags); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, todo, 200)
+}
+
+// Items handle GET /{ID}/items
+func (t Todos) Items(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		todo   = ctx.Value(loadKey).(todos.Todo)
+		result []todos.ChecklistItem
+	)
+
+	if err := t.todos.SearchItems(ctx, &result, todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// CreateItem handle POST /{ID}/items
+func (t Todos) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+		item todos.ChecklistItem
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	// id is assigned by database.
+	item.ID = 0
+
+	if err := t.todos.CreateItem(ctx, &todo, &item); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", item.ID))
+	render(w, item, 201)
+}
+
+// UpdateItem handle PATCH /{ID}/items/{ItemID}
+func (t Todos) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		todo    = ctx.Value(loadKey).(todos.Todo)
+		loaded  = ctx.Value(loadItemKey).(todos.ChecklistItem)
+		item    = loaded
+		changes = rel.NewChangeset(&item)
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	// the item is updated by its id, so it's kept as loaded to prevent updating or moving another item.
+	item.ID, item.TodoID = loaded.ID, loaded.TodoID
+
+	if err := t.todos.UpdateItem(ctx, &todo, &item, changes); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, item, 200)
+}
+
+// DestroyItem handle DELETE /{ID}/items/{ItemID}
+func (t Todos) DestroyItem(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+		item = ctx.Value(loadItemKey).(todos.ChecklistItem)
+	)
+
+	if err := t.todos.DeleteItem(ctx, &todo, &item); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Reminders handle GET /{ID}/reminders
+func (t Todos) Reminders(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		todo   = ctx.Value(loadKey).(todos.Todo)
+		result []reminders.Reminder
+	)
+
+	if err := t.reminders.Search(ctx, &result, todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// CreateReminder handle POST /{ID}/reminders
+func (t Todos) CreateReminder(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+		body struct {
+			Offset int `json:"offset"`
+		}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	reminder := reminders.Reminder{Offset: body.Offset}
+	if err := t.reminders.Create(ctx, &reminder, todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", reminder.ID))
+	render(w, reminder, 201)
+}
+
+// DestroyReminder handle DELETE /{ID}/reminders/{ReminderID}
+func (t Todos) DestroyReminder(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		reminder = ctx.Value(loadReminderKey).(reminders.Reminder)
+	)
+
+	if err := t.reminders.Delete(ctx, &reminder); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Snooze handle POST /{ID}/snooze, reminders of the todo are responded so client can tell when it's reminded next.
+func (t Todos) Snooze(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+		body struct {
+			Until time.Time `json:"until"`
+		}
+		result []reminders.Reminder
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	if err := t.reminders.Snooze(ctx, todo, body.Until); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := t.reminders.Search(ctx, &result, todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
 // Destroy handle DELETE /{ID}
 func (t Todos) Destroy(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -110,7 +471,15 @@ func (t Todos) Destroy(w http.ResponseWriter, r *http.Request) {
 		todo = ctx.Value(loadKey).(todos.Todo)
 	)
 
-	t.todos.Delete(ctx, &todo)
+	if !t.preconditionMet(w, r, todo) {
+		return
+	}
+
+	if err := t.todos.Delete(ctx, &todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
 	render(w, nil, 204)
 }
 
@@ -120,10 +489,96 @@ func (t Todos) Clear(w http.ResponseWriter, r *http.Request) {
 		ctx = r.Context()
 	)
 
-	t.todos.Clear(ctx)
+	if err := t.todos.Clear(ctx); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
 	render(w, nil, 204)
 }
 
+// Trash handle GET /trash
+func (t Todos) Trash(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []todos.Todo
+	)
+
+	if err := t.todos.SearchTrash(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Restore handle POST /{ID}/restore
+func (t Todos) Restore(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		todo = ctx.Value(loadKey).(todos.Todo)
+	)
+
+	if err := t.todos.Restore(ctx, &todo); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, todo, 200)
+}
+
+// decodeTodoBody decodes fields of todo changed by client, invalid field value is reported as validation error and anything else as bad request.
+// it's shared by http and websocket endpoints, so server managed fields are protected the same way.
+func decodeTodoBody(ctx context.Context, r io.Reader, todo *todos.Todo) error {
+	data, err := io.ReadAll(r)
+	if err == nil && len(data) == 0 {
+		err = io.EOF
+	}
+
+	if err == nil {
+		err = todo.Decode(data)
+	}
+
+	if err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(ctx))
+		if errors.Is(err, todos.ErrTodoPriorityInvalid) {
+			return err
+		}
+
+		return ErrBadRequest
+	}
+
+	return nil
+}
+
+// preconditionMet checks If-Match header against loaded todo, and renders 412 if it doesn't match.
+// the check is repeated by the service when writing to database, in case the todo is modified after it's loaded.
+func (t Todos) preconditionMet(w http.ResponseWriter, r *http.Request, todo todos.Todo) bool {
+	if header := r.Header.Get("If-Match"); header != "" && !etagMatch(header, todo.ETag(), false) {
+		logger.Warn("precondition failed", zap.String("if-match", header), zap.String("etag", todo.ETag()), tracing.Field(r.Context()))
+		render(w, todos.ErrTodoConflict, 412)
+		return false
+	}
+
+	return true
+}
+
+// authorized checks whether the requesting user is allowed to access loaded todo, reading requests only needs read access while the rest needs write access.
+// todo that isn't accessible is rendered as not found, so its existence isn't revealed.
+func (t Todos) authorized(w http.ResponseWriter, r *http.Request, todo todos.Todo) bool {
+	action := lists.ActionWrite
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		action = lists.ActionRead
+	}
+
+	if err := t.policy.Authorize(r.Context(), todo, action); err != nil {
+		renderError(w, r, err)
+		return false
+	}
+
+	return true
+}
+
 // Load is middleware that loads todos to context.
 func (t Todos) Load(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -133,33 +588,118 @@ func (t Todos) Load(next http.Handler) http.Handler {
 			todo  todos.Todo
 		)
 
-		if err := t.repository.Find(ctx, &todo, where.Eq("id", id)); err != nil {
-			if errors.Is(err, rel.ErrNotFound) {
-				render(w, err, 404)
+		if err := t.repository.Find(ctx, &todo, where.Eq("id", id), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		if !t.authorized(w, r, todo) {
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadKey, todo)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LoadTrashed is middleware that loads trashed todos to context.
+func (t Todos) LoadTrashed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   = r.Context()
+			id, _ = strconv.Atoi(chi.URLParam(r, "ID"))
+			todo  todos.Todo
+		)
+
+		if err := t.repository.Find(ctx, &todo, rel.Unscoped(true), where.Eq("id", id), where.NotNil("deleted_at")); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		if !t.authorized(w, r, todo) {
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadKey, todo)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LoadItem is middleware that loads checklist item of loaded todo to context.
+func (t Todos) LoadItem(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx   = r.Context()
+			todo  = ctx.Value(loadKey).(todos.Todo)
+			id, _ = strconv.Atoi(chi.URLParam(r, "ItemID"))
+		)
+
+		for _, item := range todo.Items {
+			if item.ID == uint(id) {
+				ctx = context.WithValue(ctx, loadItemKey, item)
+				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
-			panic(err)
 		}
 
-		ctx = context.WithValue(ctx, loadKey, todo)
+		render(w, rel.ErrNotFound, 404)
+	})
+}
+
+// LoadReminder is middleware that loads reminder of loaded todo to context, only reminders of the user are found.
+func (t Todos) LoadReminder(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx      = r.Context()
+			todo     = ctx.Value(loadKey).(todos.Todo)
+			id, _    = strconv.Atoi(chi.URLParam(r, "ReminderID"))
+			reminder reminders.Reminder
+		)
+
+		if err := t.repository.Find(ctx, &reminder, where.Eq("id", id), where.Eq("todo_id", todo.ID), where.Eq("user_id", users.FromContext(ctx))); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadReminderKey, reminder)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// NewTodos handler.
-func NewTodos(repository rel.Repository, todos todos.Service) Todos {
+// NewTodos handler, reminders of todos are served by reminders service.
+func NewTodos(repository rel.Repository, service todos.Service, reminders reminders.Service) Todos {
+	var (
+		read  = Scope(apikeys.ScopeTodosRead)
+		write = Scope(apikeys.ScopeTodosWrite)
+	)
+
 	h := Todos{
-		Mux:        chi.NewMux(),
+		Mux:        newMux(),
 		repository: repository,
-		todos:      todos,
+		todos:      service,
+		reminders:  reminders,
+		policy:     todos.NewPolicy(repository),
 	}
 
-	h.Get("/", h.Index)
-	h.Post("/", h.Create)
-	h.With(h.Load).Get("/{ID}", h.Show)
-	h.With(h.Load).Patch("/{ID}", h.Update)
-	h.With(h.Load).Delete("/{ID}", h.Destroy)
-	h.Delete("/", h.Clear)
+	h.With(read).Get("/", h.Index)
+	h.With(write).Post("/", h.Create)
+	h.With(write).Post("/bulk", h.Bulk)
+	h.With(read).Get("/trash", h.Trash)
+	h.With(read, h.Load).Get("/{ID}", h.Show)
+	h.With(write, h.Load).Patch("/{ID}", h.Update)
+	h.With(write, h.Load).Delete("/{ID}", h.Destroy)
+	h.With(write, h.Load).Post("/{ID}/move", h.Move)
+	h.With(write, h.Load).Put("/{ID}/tags", h.UpdateTags)
+	h.With(read, h.Load).Get("/{ID}/items", h.Items)
+	h.With(write, h.Load).Post("/{ID}/items", h.CreateItem)
+	h.With(write, h.Load, h.LoadItem).Patch("/{ID}/items/{ItemID}", h.UpdateItem)
+	h.With(write, h.Load, h.LoadItem).Delete("/{ID}/items/{ItemID}", h.DestroyItem)
+	h.With(read, h.Load).Get("/{ID}/reminders", h.Reminders)
+	h.With(write, h.Load).Post("/{ID}/reminders", h.CreateReminder)
+	h.With(write, h.Load, h.LoadReminder).Delete("/{ID}/reminders/{ReminderID}", h.DestroyReminder)
+	h.With(write, h.Load).Post("/{ID}/snooze", h.Snooze)
+	h.With(write, h.LoadTrashed).Post("/{ID}/restore", h.Restore)
+	h.With(write).Delete("/", h.Clear)
 
 	return h
 }
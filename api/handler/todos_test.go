@@ -1,22 +1,34 @@
 package handler_test
 
 import (
+	"context"
+	"database/sql/driver"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/reminders"
+	"github.com/Fs02/go-todo-backend/reminders/reminderstest"
 	"github.com/Fs02/go-todo-backend/todos"
 	"github.com/Fs02/go-todo-backend/todos/todostest"
+	"github.com/go-rel/rel"
 	"github.com/go-rel/rel/where"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestTodos_Index(t *testing.T) {
 	var (
-		trueb = true
+		trueb     = true
+		dueBefore = time.Date(2020, 6, 29, 0, 0, 0, 0, time.UTC)
+		dueAfter  = time.Date(2020, 6, 27, 0, 0, 0, 0, time.UTC)
+		dueAt     = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
 	)
 
 	tests := []struct {
@@ -24,13 +36,15 @@ func TestTodos_Index(t *testing.T) {
 		status          int
 		path            string
 		response        string
+		link            string
 		mockTodosSearch func(todos *todostest.Service)
+		mockTodosCursor func(todos *todostest.Service)
 	}{
 		{
 			name:     "ok",
 			status:   http.StatusOK,
 			path:     "/",
-			response: `[{"id":1, "title":"Sleep", "completed":false, "order":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			response: `[{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
 			mockTodosSearch: todostest.MockSearch(
 				[]todos.Todo{{ID: 1, Title: "Sleep"}},
 				todos.Filter{},
@@ -41,30 +55,155 @@ func TestTodos_Index(t *testing.T) {
 			name:     "with keyword and filter completed",
 			status:   http.StatusOK,
 			path:     "/?keyword=Wake&completed=true",
-			response: `[{"id":2, "title":"Wake", "completed":true, "order":0, "url":"todos/2", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			response: `[{"id":2, "title":"Wake", "completed":true, "order":0, "priority":"normal", "version":0, "url":"todos/2", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
 			mockTodosSearch: todostest.MockSearch(
 				[]todos.Todo{{ID: 2, Title: "Wake", Completed: true}},
 				todos.Filter{Keyword: "Wake", Completed: &trueb},
 				nil,
 			),
 		},
+		{
+			name:     "with list",
+			status:   http.StatusOK,
+			path:     "/?list_id=1",
+			response: `[{"id":2, "list_id":1, "title":"Wake", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/2", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 2, ListID: &[]uint{1}[0], Title: "Wake"}},
+				todos.Filter{ListID: 1},
+				nil,
+			),
+		},
+		{
+			name:     "invalid list",
+			status:   http.StatusBadRequest,
+			path:     "/?list_id=abc",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:     "with due date range",
+			status:   http.StatusOK,
+			path:     "/?due_after=2020-06-27T00:00:00Z&due_before=2020-06-29T00:00:00Z",
+			response: `[{"id":3, "title":"Run", "completed":false, "order":0, "priority":"normal", "version":0, "due_at":"2020-06-28T00:00:00Z", "url":"todos/3", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 3, Title: "Run", DueAt: &dueAt}},
+				todos.Filter{DueBefore: &dueBefore, DueAfter: &dueAfter},
+				nil,
+			),
+		},
+		{
+			name:     "with overdue",
+			status:   http.StatusOK,
+			path:     "/?overdue=true",
+			response: `[]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{},
+				todos.Filter{Overdue: true},
+				nil,
+			),
+		},
+		{
+			name:     "invalid due date",
+			status:   http.StatusBadRequest,
+			path:     "/?due_before=tomorrow",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+		{
+			name:     "with all tags",
+			status:   http.StatusOK,
+			path:     "/?tags=work,home&tags_match=all",
+			response: `[{"id":4, "title":"Code", "completed":false, "order":0, "priority":"normal", "version":0, "tags":["work","home"], "url":"todos/4", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 4, Title: "Code", Tags: []todos.TodoTag{
+					{TodoID: 4, TagID: 1, Tag: todos.Tag{ID: 1, Name: "work"}},
+					{TodoID: 4, TagID: 2, Tag: todos.Tag{ID: 2, Name: "home"}},
+				}}},
+				todos.Filter{Tags: []string{"work", "home"}, MatchAllTags: true},
+				nil,
+			),
+		},
+		{
+			name:     "with sort",
+			status:   http.StatusOK,
+			path:     "/?sort=-priority,title",
+			response: `[]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{},
+				todos.Filter{Sort: []string{"-priority", "title"}},
+				nil,
+			),
+		},
+		{
+			name:     "invalid sort",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/?sort=password",
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Sort must be one of priority, due_at, order, title, created_at or updated_at","invalid_params":[{"name":"sort","code":"invalid","reason":"Sort must be one of priority, due_at, order, title, created_at or updated_at"}]}`,
+			mockTodosSearch: todostest.MockSearch(
+				nil,
+				todos.Filter{Sort: []string{"password"}},
+				todos.ErrTodoSortInvalid,
+			),
+		},
+		{
+			name:     "with limit",
+			status:   http.StatusOK,
+			path:     "/?limit=1&sort=order",
+			response: `[{"id":5, "title":"Eat", "completed":false, "order":2, "priority":"normal", "version":0, "url":"todos/5", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			link:     `</?after=` + todos.Filter{Sort: []string{"order"}}.Cursor(todos.Todo{ID: 5, Order: 2}) + `&limit=1&sort=order>; rel="next"`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 5, Title: "Eat", Order: 2}},
+				todos.Filter{Sort: []string{"order"}, Limit: 1},
+				nil,
+			),
+			mockTodosCursor: todostest.MockCursor(todos.Filter{Sort: []string{"order"}}.Cursor(todos.Todo{ID: 5, Order: 2})),
+		},
+		{
+			name:     "with keyword highlighted by relevance",
+			status:   http.StatusOK,
+			path:     "/?keyword=eat&highlight=true&limit=1",
+			response: `[{"id":5, "title":"Eat", "snippet":"<mark>Eat</mark>", "completed":false, "order":2, "priority":"normal", "version":0, "url":"todos/5", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			link:     `</?after=ranked&highlight=true&keyword=eat&limit=1>; rel="next"`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 5, Title: "Eat", Order: 2, Snippet: "<mark>Eat</mark>"}},
+				todos.Filter{Keyword: "eat", Highlight: true, Limit: 1},
+				nil,
+			),
+			mockTodosCursor: todostest.MockCursor("ranked"),
+		},
+		{
+			name:     "last page",
+			status:   http.StatusOK,
+			path:     "/?limit=2&after=cursor",
+			response: `[{"id":5, "title":"Eat", "completed":false, "order":2, "priority":"normal", "version":0, "url":"todos/5", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockTodosSearch: todostest.MockSearch(
+				[]todos.Todo{{ID: 5, Title: "Eat", Order: 2}},
+				todos.Filter{Limit: 2, After: "cursor"},
+				nil,
+			),
+		},
+		{
+			name:     "invalid limit",
+			status:   http.StatusBadRequest,
+			path:     "/?limit=all",
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("GET", test.path, nil)
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
-			todostest.Mock(todos, test.mockTodosSearch)
+			todostest.Mock(todos, test.mockTodosSearch, test.mockTodosCursor)
 
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.link, rr.Header().Get("Link"))
 			assert.JSONEq(t, test.response, rr.Body.String())
 
 			repository.AssertExpectations(t)
@@ -88,7 +227,7 @@ func TestTodos_Create(t *testing.T) {
 			status:   http.StatusCreated,
 			path:     "/",
 			payload:  `{"title": "Sleep"}`,
-			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
 			location: "/1",
 			mockTodosCreate: todostest.MockCreate(
 				todos.Todo{ID: 1, Title: "Sleep"},
@@ -100,18 +239,25 @@ func TestTodos_Create(t *testing.T) {
 			status:   http.StatusUnprocessableEntity,
 			path:     "/",
 			payload:  `{"title": ""}`,
-			response: `{"error":"Title can't be blank"}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Title can't be blank","invalid_params":[{"name":"title","code":"blank","reason":"Title can't be blank"}]}`,
 			mockTodosCreate: todostest.MockCreate(
 				todos.Todo{Title: "Sleep"},
 				todos.ErrTodoTitleBlank,
 			),
 		},
+		{
+			name:     "invalid priority",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/",
+			payload:  `{"title": "Sleep", "priority": "critical"}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Priority must be one of low, normal, high or urgent","invalid_params":[{"name":"priority","code":"invalid","reason":"Priority must be one of low, normal, high or urgent"}]}`,
+		},
 		{
 			name:     "bad request",
 			status:   http.StatusBadRequest,
 			path:     "/",
 			payload:  ``,
-			response: `{"error":"Bad Request"}`,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
 		},
 	}
 
@@ -119,11 +265,11 @@ func TestTodos_Create(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			var (
 				body       = strings.NewReader(test.payload)
-				req, _     = http.NewRequest("POST", test.path, body)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
 			todostest.Mock(todos, test.mockTodosCreate)
@@ -140,39 +286,165 @@ func TestTodos_Create(t *testing.T) {
 	}
 }
 
+func TestTodos_Bulk(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		path          string
+		payload       string
+		response      string
+		mockTodosBulk func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/bulk",
+			payload:  `{"operations": [{"action": "complete", "id": 1}, {"action": "delete", "id": 2}]}`,
+			response: `{"results": [{"action":"complete", "id":1, "todo":{"id":1, "title":"Sleep", "completed":true, "order":0, "priority":"normal", "version":1, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}}, {"action":"delete", "id":2, "error":"Todo not found"}]}`,
+			mockTodosBulk: todostest.MockBulk(
+				[]todos.BulkResult{
+					{Action: todos.BulkComplete, ID: 1, Todo: &todos.Todo{ID: 1, Title: "Sleep", Completed: true, LockVersion: 1}},
+					{Action: todos.BulkDelete, ID: 2, Error: todos.ErrBulkTodoNotFound.Error()},
+				},
+				[]todos.BulkOperation{{Action: todos.BulkComplete, ID: 1}, {Action: todos.BulkDelete, ID: 2}},
+				false,
+				nil,
+			),
+		},
+		{
+			name:     "aborted",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/bulk",
+			payload:  `{"atomic": true, "operations": [{"action": "complete", "id": 1}, {"action": "delete", "id": 2}]}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Bulk is rolled back because one of the operations failed", "results": [{"action":"complete", "id":1, "error":"Operation is rolled back because other operation failed"}, {"action":"delete", "id":2, "error":"Todo not found"}]}`,
+			mockTodosBulk: todostest.MockBulk(
+				[]todos.BulkResult{
+					{Action: todos.BulkComplete, ID: 1, Error: todos.ErrBulkRolledBack.Error()},
+					{Action: todos.BulkDelete, ID: 2, Error: todos.ErrBulkTodoNotFound.Error()},
+				},
+				[]todos.BulkOperation{{Action: todos.BulkComplete, ID: 1}, {Action: todos.BulkDelete, ID: 2}},
+				true,
+				todos.ErrBulkAborted,
+			),
+		},
+		{
+			name:     "invalid action",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/bulk",
+			payload:  `{"operations": [{"action": "archive", "id": 1}]}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Action must be one of create, update, delete, complete or uncomplete","invalid_params":[{"name":"action","code":"invalid","reason":"Action must be one of create, update, delete, complete or uncomplete"}]}`,
+			mockTodosBulk: todostest.MockBulk(
+				nil,
+				[]todos.BulkOperation{{Action: "archive", ID: 1}},
+				false,
+				todos.ErrBulkActionInvalid,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/bulk",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			todostest.Mock(todos, test.mockTodosBulk)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTodos_Show(t *testing.T) {
 	tests := []struct {
-		name     string
-		status   int
-		path     string
-		response string
-		isPanic  bool
-		mockRepo func(repo *reltest.Repository)
+		name        string
+		status      int
+		path        string
+		response    string
+		etag        string
+		ifNoneMatch string
+		retryAfter  string
+		mockRepo    func(repo *reltest.Repository)
 	}{
 		{
 			name:     "ok",
 			status:   http.StatusOK,
 			path:     "/1",
-			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			etag:     `"0"`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).Result(todos.Todo{ID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+		},
+		{
+			name:     "with progress",
+			status:   http.StatusOK,
+			path:     "/1",
+			etag:     `"0"`,
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "progress":{"done":1, "total":2}, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth", Completed: true},
+					{ID: 2, TodoID: 1, Title: "Floss"},
+				}})
+			},
+		},
+		{
+			name:        "not modified",
+			status:      http.StatusNotModified,
+			path:        "/1",
+			etag:        `"2"`,
+			ifNoneMatch: `"1", W/"2"`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+		},
+		{
+			name:        "modified",
+			status:      http.StatusOK,
+			path:        "/1",
+			response:    `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":3, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			etag:        `"3"`,
+			ifNoneMatch: `"2"`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 3})
 			},
 		},
 		{
 			name:     "not found",
 			status:   http.StatusNotFound,
 			path:     "/1",
-			response: `{"error":"entity not found"}`,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).NotFound()
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).NotFound()
 			},
 		},
 		{
-			name:    "panic",
-			path:    "/1",
-			isPanic: true,
+			name:       "unavailable",
+			status:     http.StatusServiceUnavailable,
+			path:       "/1",
+			response:   `{"type":"about:blank","title":"Service Unavailable","status":503}`,
+			retryAfter: "5",
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).ConnectionClosed()
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).ConnectionClosed()
 			},
 		},
 	}
@@ -180,25 +452,30 @@ func TestTodos_Show(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("GET", test.path, nil)
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+
 			if test.mockRepo != nil {
 				test.mockRepo(repository)
 			}
 
-			if test.isPanic {
-				assert.Panics(t, func() {
-					handler.ServeHTTP(rr, req)
-				})
-			} else {
-				handler.ServeHTTP(rr, req)
-				assert.Equal(t, test.status, rr.Code)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.etag, rr.Header().Get("ETag"))
+			assert.Equal(t, test.retryAfter, rr.Header().Get("Retry-After"))
+			if test.response != "" {
 				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Empty(t, rr.Body.String())
 			}
 
 			repository.AssertExpectations(t)
@@ -208,11 +485,16 @@ func TestTodos_Show(t *testing.T) {
 }
 
 func TestTodos_Update(t *testing.T) {
+	var (
+		listID = uint(1)
+	)
+
 	tests := []struct {
 		name            string
 		status          int
 		path            string
 		payload         string
+		ifMatch         string
 		response        string
 		mockRepo        func(repo *reltest.Repository)
 		mockTodosUpdate func(todos *todostest.Service)
@@ -222,9 +504,23 @@ func TestTodos_Update(t *testing.T) {
 			status:   http.StatusOK,
 			path:     "/1",
 			payload:  `{"title": "Wake"}`,
-			response: `{"id":1, "title":"Wake", "completed":false, "order":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			response: `{"id":1, "title":"Wake", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosUpdate: todostest.MockUpdate(
+				todos.Todo{ID: 1, Title: "Wake"},
+				nil,
+			),
+		},
+		{
+			name:     "id can't be changed",
+			status:   http.StatusOK,
+			path:     "/1",
+			payload:  `{"id": 2, "title": "Wake"}`,
+			response: `{"id":1, "title":"Wake", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).Result(todos.Todo{ID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
 			},
 			mockTodosUpdate: todostest.MockUpdate(
 				todos.Todo{ID: 1, Title: "Wake"},
@@ -236,24 +532,125 @@ func TestTodos_Update(t *testing.T) {
 			status:   http.StatusUnprocessableEntity,
 			path:     "/1",
 			payload:  `{"title": ""}`,
-			response: `{"error":"Title can't be blank"}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Title can't be blank","invalid_params":[{"name":"title","code":"blank","reason":"Title can't be blank"}]}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).Result(todos.Todo{ID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
 			},
 			mockTodosUpdate: todostest.MockUpdate(
 				todos.Todo{ID: 1, Title: ""},
 				todos.ErrTodoTitleBlank,
 			),
 		},
+		{
+			name:     "if match",
+			status:   http.StatusOK,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			ifMatch:  `"2"`,
+			response: `{"id":1, "title":"Wake", "completed":false, "order":0, "priority":"normal", "version":3, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+			mockTodosUpdate: todostest.MockUpdate(
+				todos.Todo{ID: 1, Title: "Wake", LockVersion: 3},
+				nil,
+			),
+		},
+		{
+			name:     "if match mismatch",
+			status:   http.StatusPreconditionFailed,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			ifMatch:  `"1"`,
+			response: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"Todo has been modified, reload it and try again"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+		},
+		{
+			name:     "conflict",
+			status:   http.StatusPreconditionFailed,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			response: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"Todo has been modified, reload it and try again"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+			mockTodosUpdate: todostest.MockUpdate(
+				todos.Todo{ID: 1, Title: "Wake", LockVersion: 2},
+				todos.ErrTodoConflict,
+			),
+		},
 		{
 			name:     "bad request",
 			status:   http.StatusBadRequest,
 			path:     "/1",
 			payload:  ``,
-			response: `{"error":"Bad Request"}`,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+		},
+		{
+			name:     "editor of list",
+			status:   http.StatusOK,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			response: `{"id":1, "list_id":1, "title":"Wake", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 2, ListID: &listID, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("list_id", listID), where.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleEditor})
+			},
+			mockTodosUpdate: todostest.MockUpdate(
+				todos.Todo{ID: 1, UserID: 2, ListID: &listID, Title: "Wake"},
+				nil,
+			),
+		},
+		{
+			name:     "viewer of list",
+			status:   http.StatusForbidden,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 2, ListID: &listID, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("list_id", listID), where.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleViewer})
+			},
+		},
+		{
+			name:     "not member of list",
+			status:   http.StatusNotFound,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 2, ListID: &listID, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("list_id", listID), where.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+		{
+			name:     "todo of other user",
+			status:   http.StatusNotFound,
+			path:     "/1",
+			payload:  `{"title": "Wake"}`,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 2, Title: "Sleep"})
+			},
+		},
+		{
+			name:     "moved to list without write access",
+			status:   http.StatusForbidden,
+			path:     "/1",
+			payload:  `{"list_id": 1}`,
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"You are not allowed to do this on the list"}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).Result(todos.Todo{ID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
 			},
+			mockTodosUpdate: todostest.MockUpdate(
+				todos.Todo{ID: 1, UserID: 1, ListID: &listID, Title: "Sleep"},
+				lists.ErrForbidden,
+			),
 		},
 	}
 
@@ -261,13 +658,17 @@ func TestTodos_Update(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			var (
 				body       = strings.NewReader(test.payload)
-				req, _     = http.NewRequest("PATCH", test.path, body)
+				req, _     = http.NewRequestWithContext(ctx, "PATCH", test.path, body)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+
 			if test.mockRepo != nil {
 				test.mockRepo(repository)
 			}
@@ -285,47 +686,83 @@ func TestTodos_Update(t *testing.T) {
 	}
 }
 
-func TestTodos_Destroy(t *testing.T) {
+func TestTodos_Move(t *testing.T) {
+	var (
+		two = uint(2)
+	)
+
 	tests := []struct {
-		name            string
-		status          int
-		path            string
-		response        string
-		mockRepo        func(repo *reltest.Repository)
-		mockTodosDelete func(todos *todostest.Service)
+		name          string
+		status        int
+		path          string
+		payload       string
+		response      string
+		mockRepo      func(repo *reltest.Repository)
+		mockTodosMove func(todos *todostest.Service)
 	}{
 		{
 			name:     "ok",
-			status:   http.StatusNoContent,
-			path:     "/1",
-			response: "",
+			status:   http.StatusOK,
+			path:     "/1/move",
+			payload:  `{"after": 2}`,
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":1536, "priority":"normal", "version":1, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
 			mockRepo: func(repo *reltest.Repository) {
-				repo.ExpectFind(where.Eq("id", 1)).Result(todos.Todo{ID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosMove: todostest.MockMove(
+				todos.Todo{ID: 1, Title: "Sleep", Order: 1536, LockVersion: 1},
+				todos.Position{After: &two},
+				nil,
+			),
+		},
+		{
+			name:     "invalid position",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/1/move",
+			payload:  `{}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Position must refer to other todos, and before must come after the after todo","invalid_params":[{"name":"position","code":"invalid","reason":"Position must refer to other todos, and before must come after the after todo"}]}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosMove: todostest.MockMove(
+				todos.Todo{ID: 1, Title: "Sleep"},
+				todos.Position{},
+				todos.ErrTodoPositionInvalid,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/move",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
 			},
-			mockTodosDelete: todostest.MockDelete(),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("DELETE", test.path, nil)
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
 			if test.mockRepo != nil {
 				test.mockRepo(repository)
 			}
 
-			todostest.Mock(todos, test.mockTodosDelete)
+			todostest.Mock(todos, test.mockTodosMove)
 
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, test.status, rr.Code)
-			assert.Equal(t, test.response, rr.Body.String())
+			assert.JSONEq(t, test.response, rr.Body.String())
 
 			repository.AssertExpectations(t)
 			todos.AssertExpectations(t)
@@ -333,31 +770,890 @@ func TestTodos_Destroy(t *testing.T) {
 	}
 }
 
-func TestTodos_Clear(t *testing.T) {
+func TestTodos_UpdateTags(t *testing.T) {
 	tests := []struct {
-		name           string
-		status         int
-		path           string
-		response       string
-		mockTodosClear func(todos *todostest.Service)
+		name             string
+		status           int
+		path             string
+		payload          string
+		response         string
+		mockRepo         func(repo *reltest.Repository)
+		mockTodosSetTags func(todos *todostest.Service)
 	}{
 		{
-			name:           "created",
-			status:         http.StatusNoContent,
-			path:           "/",
-			response:       "",
-			mockTodosClear: todostest.MockClear(),
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/tags",
+			payload:  `{"tags": ["work"]}`,
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "tags":["work"], "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosSetTags: todostest.MockSetTags(
+				[]string{"work"},
+				todos.Todo{ID: 1, Title: "Sleep", Tags: []todos.TodoTag{{TodoID: 1, TagID: 1, Tag: todos.Tag{ID: 1, Name: "work"}}}},
+				nil,
+			),
+		},
+		{
+			name:     "validation error",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/1/tags",
+			payload:  `{"tags": [""]}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Tag name can't be blank","invalid_params":[{"name":"name","code":"blank","reason":"Tag name can't be blank"}]}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosSetTags: todostest.MockSetTags(
+				[]string{""},
+				todos.Todo{ID: 1, Title: "Sleep"},
+				todos.ErrTagNameBlank,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/tags",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "PUT", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosSetTags)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Items(t *testing.T) {
+	tests := []struct {
+		name                 string
+		status               int
+		path                 string
+		response             string
+		mockRepo             func(repo *reltest.Repository)
+		mockTodosSearchItems func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/items",
+			response: `[{"id":1, "todo_id":1, "title":"Brush teeth", "completed":false, "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosSearchItems: todostest.MockSearchItems(
+				[]todos.ChecklistItem{{ID: 1, TodoID: 1, Title: "Brush teeth"}},
+				nil,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosSearchItems)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_CreateItem(t *testing.T) {
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		payload             string
+		response            string
+		location            string
+		mockRepo            func(repo *reltest.Repository)
+		mockTodosCreateItem func(todos *todostest.Service)
+	}{
+		{
+			name:     "created",
+			status:   http.StatusCreated,
+			path:     "/1/items",
+			payload:  `{"title": "Brush teeth"}`,
+			response: `{"id":1, "todo_id":1, "title":"Brush teeth", "completed":false, "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1/items/1",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosCreateItem: todostest.MockCreateItem(
+				todos.ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				nil,
+			),
+		},
+		{
+			name:     "validation error",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/1/items",
+			payload:  `{"title": ""}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Item title can't be blank","invalid_params":[{"name":"title","code":"blank","reason":"Item title can't be blank"}]}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosCreateItem: todostest.MockCreateItem(
+				todos.ChecklistItem{TodoID: 1},
+				todos.ErrChecklistItemTitleBlank,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/items",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			req.RequestURI = test.path
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosCreateItem)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_UpdateItem(t *testing.T) {
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		payload             string
+		response            string
+		mockRepo            func(repo *reltest.Repository)
+		mockTodosUpdateItem func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/items/1",
+			payload:  `{"completed": true}`,
+			response: `{"id":1, "todo_id":1, "title":"Brush teeth", "completed":true, "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				}})
+			},
+			mockTodosUpdateItem: todostest.MockUpdateItem(
+				todos.ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth", Completed: true},
+				nil,
+			),
+		},
+		{
+			name:     "id and todo can't be changed",
+			status:   http.StatusOK,
+			path:     "/1/items/1",
+			payload:  `{"id": 2, "todo_id": 2, "completed": true}`,
+			response: `{"id":1, "todo_id":1, "title":"Brush teeth", "completed":true, "order":0, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				}})
+			},
+			mockTodosUpdateItem: todostest.MockUpdateItem(
+				todos.ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth", Completed: true},
+				nil,
+			),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			path:     "/1/items/2",
+			payload:  `{"completed": true}`,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				}})
+			},
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/items/1",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				}})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "PATCH", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosUpdateItem)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_DestroyItem(t *testing.T) {
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		response            string
+		mockRepo            func(repo *reltest.Repository)
+		mockTodosDeleteItem func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusNoContent,
+			path:     "/1/items/1",
+			response: "",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", Items: []todos.ChecklistItem{
+					{ID: 1, TodoID: 1, Title: "Brush teeth"},
+				}})
+			},
+			mockTodosDeleteItem: todostest.MockDeleteItem(nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosDeleteItem)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Reminders(t *testing.T) {
+	var (
+		todo = todos.Todo{ID: 1, UserID: 1, Title: "Sleep"}
+	)
+
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		response            string
+		mockRepo            func(repo *reltest.Repository)
+		mockRemindersSearch reminderstest.MockFunc
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/reminders",
+			response: `[{"id":1, "todo_id":1, "offset":15, "remind_at":null, "sent_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockRemindersSearch: reminderstest.MockSearch(
+				[]reminders.Reminder{{ID: 1, TodoID: 1, UserID: 1, Offset: 15}},
+				todo,
+				nil,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				reminders  = &reminderstest.Service{}
+				handler    = handler.NewTodos(repository, nil, reminders)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			reminderstest.Mock(reminders, test.mockRemindersSearch)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			reminders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_CreateReminder(t *testing.T) {
+	var (
+		due      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		remindAt = time.Date(2020, 6, 28, 8, 45, 0, 0, time.UTC)
+		todo     = todos.Todo{ID: 1, UserID: 1, Title: "Sleep", DueAt: &due}
+	)
+
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		payload             string
+		response            string
+		location            string
+		mockRepo            func(repo *reltest.Repository)
+		mockRemindersCreate reminderstest.MockFunc
+	}{
+		{
+			name:     "created",
+			status:   http.StatusCreated,
+			path:     "/1/reminders",
+			payload:  `{"offset": 15}`,
+			response: `{"id":1, "todo_id":1, "offset":15, "remind_at":"2020-06-28T08:45:00Z", "sent_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1/reminders/1",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockRemindersCreate: reminderstest.MockCreate(
+				reminders.Reminder{ID: 1, TodoID: 1, UserID: 1, Offset: 15, DueAt: &due, RemindAt: &remindAt},
+				todo,
+				nil,
+			),
+		},
+		{
+			name:     "only offset is decoded",
+			status:   http.StatusCreated,
+			path:     "/1/reminders",
+			payload:  `{"id": 7, "todo_id": 9, "offset": 15, "sent_at": "2020-06-28T08:45:00Z"}`,
+			response: `{"id":1, "todo_id":1, "offset":15, "remind_at":"2020-06-28T08:45:00Z", "sent_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1/reminders/1",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockRemindersCreate: func(service *reminderstest.Service) {
+				service.On("Create", mock.Anything, &reminders.Reminder{Offset: 15}, todo).
+					Return(func(ctx context.Context, out *reminders.Reminder, todo todos.Todo) error {
+						*out = reminders.Reminder{ID: 1, TodoID: 1, UserID: 1, Offset: 15, DueAt: &due, RemindAt: &remindAt}
+						return nil
+					})
+			},
+		},
+		{
+			name:     "validation error",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/1/reminders",
+			payload:  `{"offset": -1}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Offset must be between 0 and 40320 minutes","invalid_params":[{"name":"offset","code":"out_of_range","reason":"Offset must be between 0 and 40320 minutes"}]}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockRemindersCreate: reminderstest.MockCreate(
+				reminders.Reminder{TodoID: 1, UserID: 1, Offset: -1},
+				todo,
+				reminders.ErrReminderOffsetOutOfRange,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/reminders",
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				reminders  = &reminderstest.Service{}
+				handler    = handler.NewTodos(repository, nil, reminders)
+			)
+
+			req.RequestURI = test.path
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			reminderstest.Mock(reminders, test.mockRemindersCreate)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			reminders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_DestroyReminder(t *testing.T) {
+	tests := []struct {
+		name                string
+		status              int
+		path                string
+		response            string
+		mockRepo            func(repo *reltest.Repository)
+		mockRemindersDelete reminderstest.MockFunc
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusNoContent,
+			path:     "/1/reminders/2",
+			response: "",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 2), where.Eq("todo_id", uint(1)), where.Eq("user_id", uint(1))).Result(reminders.Reminder{ID: 2, TodoID: 1, UserID: 1, Offset: 15})
+			},
+			mockRemindersDelete: reminderstest.MockDelete(nil),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			path:     "/1/reminders/2",
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+				repo.ExpectFind(where.Eq("id", 2), where.Eq("todo_id", uint(1)), where.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				reminders  = &reminderstest.Service{}
+				handler    = handler.NewTodos(repository, nil, reminders)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			reminderstest.Mock(reminders, test.mockRemindersDelete)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response == "" {
+				assert.Equal(t, test.response, rr.Body.String())
+			} else {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			reminders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Snooze(t *testing.T) {
+	var (
+		until = time.Date(2020, 6, 28, 9, 10, 0, 0, time.UTC)
+		todo  = todos.Todo{ID: 1, UserID: 1, Title: "Sleep"}
+	)
+
+	tests := []struct {
+		name          string
+		status        int
+		path          string
+		payload       string
+		response      string
+		mockRepo      func(repo *reltest.Repository)
+		mockReminders []reminderstest.MockFunc
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/snooze",
+			payload:  `{"until": "2020-06-28T09:10:00Z"}`,
+			response: `[{"id":1, "todo_id":1, "offset":15, "remind_at":"2020-06-28T09:10:00Z", "sent_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockReminders: []reminderstest.MockFunc{
+				reminderstest.MockSnooze(todo, until, nil),
+				reminderstest.MockSearch([]reminders.Reminder{{ID: 1, TodoID: 1, UserID: 1, Offset: 15, RemindAt: &until}}, todo, nil),
+			},
+		},
+		{
+			name:     "past",
+			status:   http.StatusUnprocessableEntity,
+			path:     "/1/snooze",
+			payload:  `{"until": "2020-06-28T09:10:00Z"}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Snooze time must be in the future","invalid_params":[{"name":"until","code":"past","reason":"Snooze time must be in the future"}]}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+			mockReminders: []reminderstest.MockFunc{
+				reminderstest.MockSnooze(todo, until, reminders.ErrSnoozeUntilPast),
+			},
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			path:     "/1/snooze",
+			payload:  `{"until": "tomorrow"}`,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todo)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				reminders  = &reminderstest.Service{}
+				handler    = handler.NewTodos(repository, nil, reminders)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			reminderstest.Mock(reminders, test.mockReminders...)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			reminders.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Destroy(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		path            string
+		ifMatch         string
+		response        string
+		mockRepo        func(repo *reltest.Repository)
+		mockTodosDelete func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusNoContent,
+			path:     "/1",
+			response: "",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep"})
+			},
+			mockTodosDelete: todostest.MockDelete(nil),
+		},
+		{
+			name:     "if match mismatch",
+			status:   http.StatusPreconditionFailed,
+			path:     "/1",
+			ifMatch:  `"1"`,
+			response: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"Todo has been modified, reload it and try again"}` + "\n",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+		},
+		{
+			name:     "conflict",
+			status:   http.StatusPreconditionFailed,
+			path:     "/1",
+			ifMatch:  `"2"`,
+			response: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"Todo has been modified, reload it and try again"}` + "\n",
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), rel.Preload("tags"), rel.Preload("tags.tag"), rel.Preload("items")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", LockVersion: 2})
+			},
+			mockTodosDelete: todostest.MockDelete(todos.ErrTodoConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosDelete)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Trash(t *testing.T) {
+	var (
+		deletedAt = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name                 string
+		status               int
+		path                 string
+		response             string
+		mockTodosSearchTrash func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/trash",
+			response: `[{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z", "deleted_at":"2020-06-28T00:00:00Z"}]`,
+			mockTodosSearchTrash: todostest.MockSearchTrash(
+				[]todos.Todo{{ID: 1, Title: "Sleep", DeletedAt: &deletedAt}},
+				nil,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "GET", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			todostest.Mock(todos, test.mockTodosSearchTrash)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Restore(t *testing.T) {
+	var (
+		deletedAt = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name             string
+		status           int
+		path             string
+		response         string
+		mockRepo         func(repo *reltest.Repository)
+		mockTodosRestore func(todos *todostest.Service)
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			path:     "/1/restore",
+			response: `{"id":1, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":0, "url":"todos/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(rel.Unscoped(true), where.Eq("id", 1), where.NotNil("deleted_at")).Result(todos.Todo{ID: 1, UserID: 1, Title: "Sleep", DeletedAt: &deletedAt})
+			},
+			mockTodosRestore: todostest.MockRestore(nil),
+		},
+		{
+			name:     "not trashed",
+			status:   http.StatusNotFound,
+			path:     "/1/restore",
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(rel.Unscoped(true), where.Eq("id", 1), where.NotNil("deleted_at")).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "POST", test.path, nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				todos      = &todostest.Service{}
+				handler    = handler.NewTodos(repository, todos, nil)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			todostest.Mock(todos, test.mockTodosRestore)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodos_Clear(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		path           string
+		response       string
+		mockTodosClear func(todos *todostest.Service)
+	}{
+		{
+			name:           "created",
+			status:         http.StatusNoContent,
+			path:           "/",
+			response:       "",
+			mockTodosClear: todostest.MockClear(nil),
+		},
+		{
+			name:           "unavailable",
+			status:         http.StatusServiceUnavailable,
+			path:           "/",
+			response:       `{"type":"about:blank","title":"Service Unavailable","status":503}`,
+			mockTodosClear: todostest.MockClear(dberr.Classify(driver.ErrBadConn)),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			var (
-				req, _     = http.NewRequest("DELETE", test.path, nil)
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", test.path, nil)
 				rr         = httptest.NewRecorder()
 				repository = reltest.New()
 				todos      = &todostest.Service{}
-				handler    = handler.NewTodos(repository, todos)
+				handler    = handler.NewTodos(repository, todos, nil)
 			)
 
 			todostest.Mock(todos, test.mockTodosClear)
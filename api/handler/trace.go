@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	chimid "github.com/go-chi/chi/middleware"
+)
+
+// Trace is middleware that starts server span of the request, continuing the caller's trace from traceparent header.
+// span is named after method and chi route pattern once the request is routed.
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx, span = tracing.Start(tracing.Extract(r.Context(), r.Header), r.Method)
+			ww        = chimid.NewWrapResponseWriter(w, r.ProtoMajor)
+		)
+
+		defer span.End()
+
+		span.SetKind(tracing.KindServer)
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.RequestURI())
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		var (
+			route  = routePattern(r)
+			status = responseStatus(ww)
+		)
+
+		span.SetName(r.Method + " " + route)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.status_code", strconv.Itoa(status))
+
+		// client error isn't an error of the server.
+		if status >= 500 {
+			span.Record(errors.New(http.StatusText(status)))
+		}
+	})
+}
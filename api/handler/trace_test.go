@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	var (
+		exporter = tracing.NewMemory()
+		router   = chi.NewRouter()
+		todos    = chi.NewRouter()
+		outgoing string
+	)
+
+	tracing.Default.SetExporter(exporter)
+	defer tracing.Default.SetExporter(nil)
+
+	todos.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+		header := http.Header{}
+		tracing.Inject(r.Context(), header)
+		outgoing = header.Get("traceparent")
+
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	router.Use(handler.Trace)
+	router.Mount("/todos", todos)
+
+	req, _ := http.NewRequest("GET", "/todos/1?fields=title", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "GET /todos/{ID}", spans[0].Name)
+	assert.Equal(t, tracing.KindServer, spans[0].Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].Context.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.String())
+	assert.Equal(t, "Bad Gateway", spans[0].Error)
+	assert.Equal(t, map[string]string{
+		"http.method":      "GET",
+		"http.target":      "/todos/1?fields=title",
+		"http.route":       "/todos/{ID}",
+		"http.status_code": "502",
+	}, spans[0].Attributes)
+	assert.Equal(t, tracing.FormatTraceparent(spans[0].Context), outgoing)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/go-chi/chi"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"go.uber.org/zap"
+)
+
+// Webhooks for webhooks endpoints.
+type Webhooks struct {
+	*chi.Mux
+	repository rel.Repository
+	webhooks   webhooks.Service
+}
+
+// Index handle GET /.
+func (h Webhooks) Index(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		result []webhooks.Webhook
+	)
+
+	if err := h.webhooks.Search(ctx, &result); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Create handle POST /, the response is the only time the signing secret is shown.
+func (h Webhooks) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		body struct {
+			URL    string          `json:"url"`
+			Events webhooks.Events `json:"events"`
+		}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logger.Warn("decode error", zap.Error(err), tracing.Field(r.Context()))
+		render(w, ErrBadRequest, 400)
+		return
+	}
+
+	webhook := webhooks.Webhook{URL: body.URL, Events: body.Events}
+	if err := h.webhooks.Create(ctx, &webhook); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprint(r.RequestURI, "/", webhook.ID))
+	render(w, webhook, 201)
+}
+
+// Destroy handle DELETE /{ID}
+func (h Webhooks) Destroy(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		webhook = ctx.Value(loadWebhookKey).(webhooks.Webhook)
+	)
+
+	if err := h.webhooks.Delete(ctx, &webhook); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, nil, 204)
+}
+
+// Deliveries handle GET /{ID}/deliveries, the most recent deliveries come first.
+func (h Webhooks) Deliveries(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		webhook = ctx.Value(loadWebhookKey).(webhooks.Webhook)
+		result  []webhooks.Delivery
+	)
+
+	if err := h.webhooks.SearchDeliveries(ctx, &result, webhook); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, result, 200)
+}
+
+// Redeliver handle POST /{ID}/deliveries/{DeliveryID}/redeliver, the delivery is sent again regardless of its status.
+func (h Webhooks) Redeliver(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		delivery = ctx.Value(loadDeliveryKey).(webhooks.Delivery)
+	)
+
+	if err := h.webhooks.Redeliver(ctx, &delivery); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render(w, delivery, 202)
+}
+
+// Load is middleware that loads webhooks to context.
+func (h Webhooks) Load(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx     = r.Context()
+			id, _   = strconv.Atoi(chi.URLParam(r, "ID"))
+			webhook webhooks.Webhook
+		)
+
+		if err := h.repository.Find(ctx, &webhook, where.Eq("id", id), where.Eq("user_id", users.FromContext(ctx))); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadWebhookKey, webhook)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LoadDelivery is middleware that loads delivery of the loaded webhook to context.
+func (h Webhooks) LoadDelivery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx      = r.Context()
+			webhook  = ctx.Value(loadWebhookKey).(webhooks.Webhook)
+			id, _    = strconv.Atoi(chi.URLParam(r, "DeliveryID"))
+			delivery webhooks.Delivery
+		)
+
+		if err := h.repository.Find(ctx, &delivery, where.Eq("id", id), where.Eq("webhook_id", webhook.ID)); err != nil {
+			renderError(w, r, err)
+			return
+		}
+
+		ctx = context.WithValue(ctx, loadDeliveryKey, delivery)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewWebhooks handler.
+func NewWebhooks(repository rel.Repository, webhooks webhooks.Service) Webhooks {
+	h := Webhooks{
+		Mux:        newMux(),
+		repository: repository,
+		webhooks:   webhooks,
+	}
+
+	// a leaked api key can't be used to send the user's data elsewhere.
+	h.Use(Interactive)
+	h.Get("/", h.Index)
+	h.Post("/", h.Create)
+	h.With(h.Load).Delete("/{ID}", h.Destroy)
+	h.With(h.Load).Get("/{ID}/deliveries", h.Deliveries)
+	h.With(h.Load, h.LoadDelivery).Post("/{ID}/deliveries/{DeliveryID}/redeliver", h.Redeliver)
+
+	return h
+}
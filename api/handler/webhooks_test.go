@@ -0,0 +1,270 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/api/handler"
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/Fs02/go-todo-backend/webhooks/webhookstest"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhooks_Index(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             int
+		ctx                context.Context
+		response           string
+		mockWebhooksSearch webhookstest.MockFunc
+	}{
+		{
+			name:     "ok",
+			status:   http.StatusOK,
+			ctx:      ctx,
+			response: `[{"id":1, "url":"https://example.com/hook", "events":["todo.created"], "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`,
+			mockWebhooksSearch: webhookstest.MockSearch(
+				[]webhooks.Webhook{{ID: 1, URL: "https://example.com/hook", Events: webhooks.Events{events.TodoCreated}, Secret: "whsec_secret"}},
+				nil,
+			),
+		},
+		{
+			name:     "authenticated by api key",
+			status:   http.StatusForbidden,
+			ctx:      apikeys.NewContext(ctx, apikeys.APIKey{ID: 1, Scopes: apikeys.Scopes{apikeys.ScopeTodosRead}}),
+			response: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"Forbidden"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(test.ctx, "GET", "/", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &webhookstest.Service{}
+				handler    = handler.NewWebhooks(repository, service)
+			)
+
+			webhookstest.Mock(service, test.mockWebhooksSearch)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhooks_Create(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             int
+		payload            string
+		response           string
+		location           string
+		mockWebhooksCreate webhookstest.MockFunc
+	}{
+		{
+			name:     "created",
+			status:   http.StatusCreated,
+			payload:  `{"url": "https://example.com/hook", "events": ["todo.created"]}`,
+			response: `{"id":1, "url":"https://example.com/hook", "events":["todo.created"], "secret":"whsec_secret", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			location: "/1",
+			mockWebhooksCreate: webhookstest.MockCreate(
+				webhooks.Webhook{ID: 1, URL: "https://example.com/hook", Events: webhooks.Events{events.TodoCreated}, Secret: "whsec_secret", SigningSecret: "whsec_secret"},
+				nil,
+			),
+		},
+		{
+			name:     "validation error",
+			status:   http.StatusUnprocessableEntity,
+			payload:  `{"url": "/hook", "events": ["todo.created"]}`,
+			response: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"URL must be an absolute http or https URL","invalid_params":[{"name":"url","code":"invalid","reason":"URL must be an absolute http or https URL"}]}`,
+			mockWebhooksCreate: webhookstest.MockCreate(
+				webhooks.Webhook{URL: "/hook"},
+				webhooks.ErrWebhookURLInvalid,
+			),
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			payload:  ``,
+			response: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Bad Request"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				body       = strings.NewReader(test.payload)
+				req, _     = http.NewRequestWithContext(ctx, "POST", "/", body)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &webhookstest.Service{}
+				handler    = handler.NewWebhooks(repository, service)
+			)
+
+			webhookstest.Mock(service, test.mockWebhooksCreate)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.Equal(t, test.location, rr.Header().Get("Location"))
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhooks_Destroy(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             int
+		response           string
+		mockRepo           func(repo *reltest.Repository)
+		mockWebhooksDelete webhookstest.MockFunc
+	}{
+		{
+			name:   "ok",
+			status: http.StatusNoContent,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).Result(webhooks.Webhook{ID: 1, UserID: 1})
+			},
+			mockWebhooksDelete: webhookstest.MockDelete(nil),
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "DELETE", "/1", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &webhookstest.Service{}
+				handler    = handler.NewWebhooks(repository, service)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			webhookstest.Mock(service, test.mockWebhooksDelete)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, rr.Body.String())
+			} else {
+				assert.Equal(t, "", rr.Body.String())
+			}
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhooks_Deliveries(t *testing.T) {
+	var (
+		req, _     = http.NewRequestWithContext(ctx, "GET", "/1/deliveries", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = &webhookstest.Service{}
+		handler    = handler.NewWebhooks(repository, service)
+		webhook    = webhooks.Webhook{ID: 1, UserID: 1}
+	)
+
+	repository.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).Result(webhook)
+	webhookstest.Mock(service, webhookstest.MockSearchDeliveries(
+		[]webhooks.Delivery{{ID: 1, WebhookID: 1, Event: events.TodoCreated, Payload: `{"id":1}`, Status: webhooks.DeliveryDead, Attempts: 8, LastError: "webhooks: unexpected status 500", ResponseStatus: 500}},
+		webhook,
+		nil,
+	))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"id":1, "webhook_id":1, "event":"todo.created", "payload":{"id":1}, "status":"dead", "attempts":8, "next_attempt_at":"0001-01-01T00:00:00Z",
+		"last_error":"webhooks: unexpected status 500", "response_status":500, "delivered_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}]`, rr.Body.String())
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestWebhooks_Redeliver(t *testing.T) {
+	tests := []struct {
+		name                  string
+		status                int
+		response              string
+		mockRepo              func(repo *reltest.Repository)
+		mockWebhooksRedeliver webhookstest.MockFunc
+	}{
+		{
+			name:     "accepted",
+			status:   http.StatusAccepted,
+			response: `{"id":2, "webhook_id":1, "event":"todo.created", "payload":{"id":1}, "status":"pending", "attempts":0, "next_attempt_at":"0001-01-01T00:00:00Z", "delivered_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).Result(webhooks.Webhook{ID: 1, UserID: 1})
+				repo.ExpectFind(where.Eq("id", 2), where.Eq("webhook_id", uint(1))).Result(webhooks.Delivery{ID: 2, WebhookID: 1, Status: webhooks.DeliveryDead})
+			},
+			mockWebhooksRedeliver: webhookstest.MockRedeliver(
+				webhooks.Delivery{ID: 2, WebhookID: 1, Event: events.TodoCreated, Payload: `{"id":1}`, Status: webhooks.DeliveryPending},
+				nil,
+			),
+		},
+		{
+			name:     "delivery not found",
+			status:   http.StatusNotFound,
+			response: `{"type":"about:blank","title":"Not Found","status":404,"detail":"entity not found"}`,
+			mockRepo: func(repo *reltest.Repository) {
+				repo.ExpectFind(where.Eq("id", 1), where.Eq("user_id", uint(1))).Result(webhooks.Webhook{ID: 1, UserID: 1})
+				repo.ExpectFind(where.Eq("id", 2), where.Eq("webhook_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				req, _     = http.NewRequestWithContext(ctx, "POST", "/1/deliveries/2/redeliver", nil)
+				rr         = httptest.NewRecorder()
+				repository = reltest.New()
+				service    = &webhookstest.Service{}
+				handler    = handler.NewWebhooks(repository, service)
+			)
+
+			test.mockRepo(repository)
+			webhookstest.Mock(service, test.mockWebhooksRedeliver)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.status, rr.Code)
+			assert.JSONEq(t, test.response, rr.Body.String())
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
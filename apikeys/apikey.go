@@ -0,0 +1,132 @@
+package apikeys
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrAPIKeyNameBlank validation error.
+	ErrAPIKeyNameBlank = validation.New("name", "blank", "API key name can't be blank")
+	// ErrAPIKeyScopesInvalid validation error.
+	ErrAPIKeyScopesInvalid = validation.New("scopes", "invalid", "Scopes must contain at least one of todos:read, todos:write or score:read")
+	// ErrAPIKeyInvalid returned when token doesn't belong to any active api key.
+	ErrAPIKeyInvalid = errors.New("API key is invalid")
+)
+
+// Scope of access granted to api key.
+type Scope string
+
+const (
+	// ScopeTodosRead allows reading todos and tags.
+	ScopeTodosRead Scope = "todos:read"
+	// ScopeTodosWrite allows creating, updating and deleting todos and tags.
+	ScopeTodosWrite Scope = "todos:write"
+	// ScopeScoreRead allows reading score and its points.
+	ScopeScoreRead Scope = "score:read"
+)
+
+// Valid returns true if scope is known.
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeTodosRead, ScopeTodosWrite, ScopeScoreRead:
+		return true
+	}
+
+	return false
+}
+
+// Scopes granted to api key, stored as space separated string.
+type Scopes []Scope
+
+// Contains returns true if scope is granted.
+func (s Scopes) Contains(scope Scope) bool {
+	for i := range s {
+		if s[i] == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valid returns true if there's at least one scope and every scope is known.
+func (s Scopes) valid() bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i := range s {
+		if !s[i].Valid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Value implements driver.Valuer.
+func (s Scopes) Value() (driver.Value, error) {
+	strs := make([]string, len(s))
+	for i := range s {
+		strs[i] = string(s[i])
+	}
+
+	return strings.Join(strs, " "), nil
+}
+
+// Scan implements sql.Scanner.
+func (s *Scopes) Scan(src interface{}) error {
+	var str string
+	switch v := src.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case nil:
+	default:
+		return errors.New("apikeys: unsupported scopes type")
+	}
+
+	*s = nil
+	for _, field := range strings.Fields(str) {
+		*s = append(*s, Scope(field))
+	}
+
+	return nil
+}
+
+// APIKey respresent a record stored in api_keys table.
+// only hash of the token is stored, the token itself is only available right after the key is created.
+type APIKey struct {
+	ID     uint   `json:"id"`
+	UserID uint   `json:"-"`
+	Name   string `json:"name"`
+	// Prefix is the beginning of the token, it helps identifying the key without revealing the token.
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	Scopes    Scopes     `json:"scopes"`
+	Token     string     `json:"token,omitempty" db:"-"`
+	RevokedAt *time.Time `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate api key.
+func (k APIKey) Validate() error {
+	var errs validation.Errors
+
+	if len(strings.TrimSpace(k.Name)) == 0 {
+		errs.Add(ErrAPIKeyNameBlank)
+	}
+
+	if !k.Scopes.valid() {
+		errs.Add(ErrAPIKeyScopesInvalid)
+	}
+
+	return errs.Err()
+}
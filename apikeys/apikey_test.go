@@ -0,0 +1,69 @@
+package apikeys
+
+import (
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		key  APIKey
+		err  error
+	}{
+		{
+			name: "valid",
+			key:  APIKey{Name: "ci", Scopes: Scopes{ScopeTodosRead, ScopeScoreRead}},
+		},
+		{
+			name: "name is blank",
+			key:  APIKey{Name: " ", Scopes: Scopes{ScopeTodosRead}},
+			err:  validation.Errors{ErrAPIKeyNameBlank},
+		},
+		{
+			name: "without scopes",
+			key:  APIKey{Name: "ci"},
+			err:  validation.Errors{ErrAPIKeyScopesInvalid},
+		},
+		{
+			name: "unknown scope",
+			key:  APIKey{Name: "ci", Scopes: Scopes{ScopeTodosRead, "admin"}},
+			err:  validation.Errors{ErrAPIKeyScopesInvalid},
+		},
+		{
+			name: "every invalid field",
+			key:  APIKey{},
+			err:  validation.Errors{ErrAPIKeyNameBlank, ErrAPIKeyScopesInvalid},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.key.Validate())
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	var (
+		scopes  = Scopes{ScopeTodosRead, ScopeScoreRead}
+		scanned Scopes
+	)
+
+	assert.True(t, scopes.Contains(ScopeTodosRead))
+	assert.False(t, scopes.Contains(ScopeTodosWrite))
+
+	value, err := scopes.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "todos:read score:read", value)
+
+	assert.Nil(t, scanned.Scan([]byte("todos:read score:read")))
+	assert.Equal(t, scopes, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.NotNil(t, scanned.Scan(1))
+}
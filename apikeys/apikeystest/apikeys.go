@@ -0,0 +1,60 @@
+package apikeystest
+
+import (
+	context "context"
+
+	apikeys "github.com/Fs02/go-todo-backend/apikeys"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock api key functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockSearch util.
+func MockSearch(result []apikeys.APIKey, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Search", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]apikeys.APIKey) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreate util.
+func MockCreate(result apikeys.APIKey, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Create", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *apikeys.APIKey) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockRevoke util.
+func MockRevoke(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Revoke", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockAuthenticate util.
+func MockAuthenticate(result apikeys.APIKey, token string, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Authenticate", mock.Anything, mock.Anything, token).
+			Return(func(ctx context.Context, out *apikeys.APIKey, token string) error {
+				*out = result
+				return err
+			})
+	}
+}
@@ -0,0 +1,29 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+)
+
+type authenticate struct {
+	repository rel.Repository
+}
+
+// Authenticate finds active api key of the token.
+func (a authenticate) Authenticate(ctx context.Context, key *APIKey, token string) error {
+	if !IsToken(token) {
+		return ErrAPIKeyInvalid
+	}
+
+	if err := a.repository.Find(ctx, key, rel.Eq("hash", hash(token)), rel.Nil("revoked_at")); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrAPIKeyInvalid
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var (
+		token  = "tk_secret"
+		result = APIKey{ID: 1, UserID: 1, Name: "ci", Hash: hash(token), Scopes: Scopes{ScopeTodosRead}}
+	)
+
+	tests := []struct {
+		name     string
+		token    string
+		key      APIKey
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:  "ok",
+			token: token,
+			key:   result,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("hash", hash(token)), rel.Nil("revoked_at")).Result(result)
+			},
+		},
+		{
+			name:  "not found",
+			token: token,
+			err:   ErrAPIKeyInvalid,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("hash", hash(token)), rel.Nil("revoked_at")).NotFound()
+			},
+		},
+		{
+			name:  "not api key",
+			token: "eyJhbGciOiJIUzI1NiJ9",
+			err:   ErrAPIKeyInvalid,
+		},
+		{
+			name:  "error",
+			token: token,
+			err:   reltest.ErrConnectionClosed,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("hash", hash(token)), rel.Nil("revoked_at")).ConnectionClosed()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = New(repository)
+				key        APIKey
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			assert.Equal(t, test.err, service.Authenticate(ctx, &key, test.token))
+			assert.Equal(t, test.key, key)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package apikeys
+
+import (
+	"context"
+)
+
+type ctxKey int
+
+const (
+	apiKeyKey ctxKey = 0
+)
+
+// NewContext returns context that carries api key used to authenticate the request.
+func NewContext(ctx context.Context, key APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// FromContext returns api key used to authenticate the request, false if the request isn't authenticated by api key.
+func FromContext(ctx context.Context) (APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey).(APIKey)
+	return key, ok
+}
@@ -0,0 +1,29 @@
+package apikeys
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type create struct {
+	repository rel.Repository
+}
+
+// Create api key with a new token, the token is set to the key and can't be retrieved later.
+func (c create) Create(ctx context.Context, key *APIKey) error {
+	if err := key.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	key.UserID = users.FromContext(ctx)
+	key.Token = generateToken()
+	key.Prefix = key.Token[:tokenPrefixLen]
+	key.Hash = hash(key.Token)
+
+	return c.repository.Insert(ctx, key)
+}
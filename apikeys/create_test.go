@@ -0,0 +1,47 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository)
+		key        = APIKey{Name: "ci", Scopes: Scopes{ScopeTodosRead}}
+	)
+
+	repository.ExpectInsert().ForType("apikeys.APIKey")
+
+	assert.Nil(t, service.Create(ctx, &key))
+	assert.NotEmpty(t, key.ID)
+	assert.Equal(t, uint(1), key.UserID)
+	assert.True(t, IsToken(key.Token))
+	assert.Len(t, key.Token, 35)
+	assert.Equal(t, key.Token[:11], key.Prefix)
+	assert.Equal(t, hash(key.Token), key.Hash)
+	assert.NotEqual(t, key.Token, key.Hash)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_validateError(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository)
+		key        = APIKey{Name: "ci"}
+	)
+
+	assert.Equal(t, validation.Errors{ErrAPIKeyScopesInvalid}, service.Create(ctx, &key))
+	assert.Empty(t, key.Token)
+
+	repository.AssertExpectations(t)
+}
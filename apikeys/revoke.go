@@ -0,0 +1,24 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+var (
+	now = time.Now
+)
+
+type revoke struct {
+	repository rel.Repository
+}
+
+// Revoke api key, the key is kept for record but its token is no longer accepted.
+func (r revoke) Revoke(ctx context.Context, key *APIKey) error {
+	t := now()
+	key.RevokedAt = &t
+
+	return r.repository.Update(ctx, key)
+}
@@ -0,0 +1,30 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevoke(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		key        = APIKey{ID: 1, Name: "ci"}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectUpdate().For(&APIKey{ID: 1, Name: "ci", RevokedAt: &today})
+
+	assert.Nil(t, service.Revoke(ctx, &key))
+	assert.Equal(t, &today, key.RevokedAt)
+
+	repository.AssertExpectations(t)
+}
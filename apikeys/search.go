@@ -0,0 +1,17 @@
+package apikeys
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+)
+
+type search struct {
+	repository rel.Repository
+}
+
+// Search active api keys of the user.
+func (s search) Search(ctx context.Context, keys *[]APIKey) error {
+	return s.repository.FindAll(ctx, keys, rel.Where(rel.Eq("user_id", users.FromContext(ctx)), rel.Nil("revoked_at")).SortAsc("id"))
+}
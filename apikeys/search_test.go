@@ -0,0 +1,28 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository)
+		keys       []APIKey
+		result     = []APIKey{{ID: 1, Name: "ci", Scopes: Scopes{ScopeTodosRead}}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1)), rel.Nil("revoked_at")).SortAsc("id")).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &keys))
+	assert.Equal(t, result, keys)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,44 @@
+package apikeys
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "apikeys")))
+)
+
+//go:generate mockery --name=Service --case=underscore --output apikeystest --outpkg apikeystest
+
+// Service instance for api key's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Search(ctx context.Context, keys *[]APIKey) error
+	Create(ctx context.Context, key *APIKey) error
+	Revoke(ctx context.Context, key *APIKey) error
+	Authenticate(ctx context.Context, key *APIKey, token string) error
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	search
+	create
+	revoke
+	authenticate
+}
+
+var _ Service = (*service)(nil)
+
+// New API keys service.
+func New(repository rel.Repository) Service {
+	return service{
+		search:       search{repository: repository},
+		create:       create{repository: repository},
+		revoke:       revoke{repository: repository},
+		authenticate: authenticate{repository: repository},
+	}
+}
@@ -0,0 +1,37 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// TokenPrefix distinguishes api key from other kind of bearer token.
+	TokenPrefix = "tk_"
+	// tokenPrefixLen is the length of the token stored as key prefix.
+	tokenPrefixLen = len(TokenPrefix) + 8
+)
+
+// IsToken returns true if str looks like an api key token.
+func IsToken(str string) bool {
+	return strings.HasPrefix(str, TokenPrefix)
+}
+
+// generateToken returns a new random token, with 192 bits of randomness.
+func generateToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+}
+
+// hash token for lookup, the token is random enough that a fast unsalted hash is sufficient.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTokenInvalid returned when token is malformed, its signature doesn't match or it's not yet valid.
+	ErrTokenInvalid = errors.New("auth: invalid token")
+	// ErrTokenExpired returned when token is past its expiration time.
+	ErrTokenExpired = errors.New("auth: token expired")
+
+	now = time.Now
+)
+
+const (
+	// HS256 is HMAC using SHA-256.
+	HS256 = "HS256"
+	// RS256 is RSASSA-PKCS1-v1_5 using SHA-256.
+	RS256 = "RS256"
+)
+
+// Header of a token.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Claims of a token, only registered claims that are used by the api are decoded.
+// name and email are optional profile of the user, they're stored when the user is provisioned.
+type Claims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+}
+
+type key struct {
+	alg    string
+	secret []byte
+	public *rsa.PublicKey
+}
+
+// Verifier verifies signed tokens against a set of keys.
+type Verifier struct {
+	// keys by key id, a key without id is stored with empty id.
+	keys map[string]key
+}
+
+// Verify token signature and time claims, and returns its claims.
+// token header must specify the same algorithm as the key, so a public key can't be used as hmac secret.
+func (v Verifier) Verify(token string) (Claims, error) {
+	var (
+		header Header
+		claims Claims
+		parts  = strings.Split(token, ".")
+	)
+
+	if len(parts) != 3 {
+		return claims, ErrTokenInvalid
+	}
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return claims, err
+	}
+
+	key, ok := v.key(header.Kid)
+	if !ok || key.alg != header.Alg {
+		return claims, ErrTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, ErrTokenInvalid
+	}
+
+	if !key.verify(parts[0]+"."+parts[1], signature) {
+		return claims, ErrTokenInvalid
+	}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return claims, err
+	}
+
+	t := now().Unix()
+	if claims.ExpiresAt != 0 && t >= claims.ExpiresAt {
+		return claims, ErrTokenExpired
+	}
+
+	if claims.NotBefore != 0 && t < claims.NotBefore {
+		return claims, ErrTokenInvalid
+	}
+
+	return claims, nil
+}
+
+// key looks up key by id, the only key is used when token doesn't specify the key id.
+func (v Verifier) key(kid string) (key, bool) {
+	if k, ok := v.keys[kid]; ok {
+		return k, true
+	}
+
+	if kid == "" && len(v.keys) == 1 {
+		for _, k := range v.keys {
+			return k, true
+		}
+	}
+
+	return key{}, false
+}
+
+func (k key) verify(signed string, signature []byte) bool {
+	switch k.alg {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write([]byte(signed))
+		return hmac.Equal(signature, mac.Sum(nil))
+	case RS256:
+		digest := sha256.Sum256([]byte(signed))
+		return rsa.VerifyPKCS1v15(k.public, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return ErrTokenInvalid
+	}
+
+	return nil
+}
+
+// NewHS256 returns verifier of tokens signed using hmac secret.
+func NewHS256(secret []byte) Verifier {
+	return Verifier{
+		keys: map[string]key{"": {alg: HS256, secret: secret}},
+	}
+}
+
+// NewRS256 returns verifier of tokens signed using private key of the rsa public key.
+func NewRS256(public *rsa.PublicKey) Verifier {
+	return Verifier{
+		keys: map[string]key{"": {alg: RS256, public: public}},
+	}
+}
@@ -0,0 +1,120 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/auth/authtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	secret     = []byte("secret")
+	private, _ = rsa.GenerateKey(rand.Reader, 2048)
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	var (
+		hs256    = auth.NewHS256(secret)
+		rs256    = auth.NewRS256(&private.PublicKey)
+		future   = time.Now().Add(time.Hour).Unix()
+		past     = time.Now().Add(-time.Hour).Unix()
+		valid    = auth.Claims{Subject: "1", ExpiresAt: future}
+		other    = authtest.HS256([]byte("other"), "", valid)
+		tampered = authtest.HS256(secret, "", valid)
+	)
+
+	// replace claims with the claims of other subject, while keeping the signature.
+	parts := strings.Split(tampered, ".")
+	parts[1] = strings.Split(authtest.HS256(secret, "", auth.Claims{Subject: "2", ExpiresAt: future}), ".")[1]
+	tampered = strings.Join(parts, ".")
+
+	tests := []struct {
+		name     string
+		verifier auth.Verifier
+		token    string
+		claims   auth.Claims
+		err      error
+	}{
+		{
+			name:     "hs256",
+			verifier: hs256,
+			token:    authtest.HS256(secret, "", valid),
+			claims:   valid,
+		},
+		{
+			name:     "rs256",
+			verifier: rs256,
+			token:    authtest.RS256(private, "", valid),
+			claims:   valid,
+		},
+		{
+			name:     "without expiration",
+			verifier: hs256,
+			token:    authtest.HS256(secret, "", auth.Claims{Subject: "1"}),
+			claims:   auth.Claims{Subject: "1"},
+		},
+		{
+			name:     "expired",
+			verifier: hs256,
+			token:    authtest.HS256(secret, "", auth.Claims{Subject: "1", ExpiresAt: past}),
+			claims:   auth.Claims{Subject: "1", ExpiresAt: past},
+			err:      auth.ErrTokenExpired,
+		},
+		{
+			name:     "not yet valid",
+			verifier: hs256,
+			token:    authtest.HS256(secret, "", auth.Claims{Subject: "1", NotBefore: future}),
+			claims:   auth.Claims{Subject: "1", NotBefore: future},
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "wrong secret",
+			verifier: hs256,
+			token:    other,
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "tampered",
+			verifier: hs256,
+			token:    tampered,
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "algorithm mismatch",
+			verifier: rs256,
+			token:    authtest.HS256(secret, "", valid),
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "unknown key id",
+			verifier: hs256,
+			token:    authtest.HS256(secret, "other", valid),
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "malformed",
+			verifier: hs256,
+			token:    "abc.def",
+			err:      auth.ErrTokenInvalid,
+		},
+		{
+			name:     "malformed header",
+			verifier: hs256,
+			token:    "abc.def.ghi",
+			err:      auth.ErrTokenInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := test.verifier.Verify(test.token)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.claims, claims)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package authtest
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/Fs02/go-todo-backend/auth"
+)
+
+// HS256 mints token signed using hmac secret.
+func HS256(secret []byte, kid string, claims auth.Claims) string {
+	return sign(auth.Header{Alg: auth.HS256, Kid: kid, Typ: "JWT"}, claims, func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	})
+}
+
+// RS256 mints token signed using rsa private key.
+func RS256(private *rsa.PrivateKey, kid string, claims auth.Claims) string {
+	return sign(auth.Header{Alg: auth.RS256, Kid: kid, Typ: "JWT"}, claims, func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+		if err != nil {
+			panic(err)
+		}
+
+		return signature
+	})
+}
+
+func sign(header auth.Header, claims auth.Claims, signer func([]byte) []byte) string {
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signer([]byte(signed)))
+}
+
+func encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+)
+
+var (
+	// ErrKeyInvalid returned when key or key set can't be parsed.
+	ErrKeyInvalid = errors.New("auth: invalid key")
+)
+
+// ParseRSAPublicKey parses PEM encoded PKIX or PKCS1 rsa public key.
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrKeyInvalid
+	}
+
+	if public, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return public, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrKeyInvalid
+	}
+
+	public, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrKeyInvalid
+	}
+
+	return public, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// N and E are modulus and exponent of rsa key.
+	N string `json:"n"`
+	E string `json:"e"`
+	// K is the secret of symmetric key.
+	K string `json:"k"`
+}
+
+// ParseJWKS returns verifier of tokens signed using any of the signing keys in json web key set.
+// rsa keys are used for RS256 and symmetric keys for HS256, keys for encryption are ignored.
+func ParseJWKS(data []byte) (Verifier, error) {
+	var (
+		set struct {
+			Keys []jwk `json:"keys"`
+		}
+		verifier = Verifier{keys: make(map[string]key)}
+	)
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return verifier, ErrKeyInvalid
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var (
+			parsed key
+			err    error
+		)
+
+		switch k.Kty {
+		case "RSA":
+			parsed, err = k.rsa()
+		case "oct":
+			parsed, err = k.oct()
+		default:
+			continue
+		}
+
+		if err != nil {
+			return verifier, err
+		}
+
+		if k.Alg != "" && k.Alg != parsed.alg {
+			continue
+		}
+
+		verifier.keys[k.Kid] = parsed
+	}
+
+	if len(verifier.keys) == 0 {
+		return verifier, ErrKeyInvalid
+	}
+
+	return verifier, nil
+}
+
+// LoadJWKS reads json web key set from file, see ParseJWKS.
+func LoadJWKS(filename string) (Verifier, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Verifier{}, err
+	}
+
+	return ParseJWKS(data)
+}
+
+func (k jwk) rsa() (key, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return key{}, ErrKeyInvalid
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 {
+		return key{}, ErrKeyInvalid
+	}
+
+	public := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+
+	return key{alg: RS256, public: public}, nil
+}
+
+func (k jwk) oct() (key, error) {
+	secret, err := base64.RawURLEncoding.DecodeString(k.K)
+	if err != nil || len(secret) == 0 {
+		return key{}, ErrKeyInvalid
+	}
+
+	return key{alg: HS256, secret: secret}, nil
+}
@@ -0,0 +1,137 @@
+package auth_test
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/auth/authtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRSAPublicKey(t *testing.T) {
+	pkix, _ := x509.MarshalPKIXPublicKey(&private.PublicKey)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{
+			name: "pkix",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}),
+		},
+		{
+			name: "pkcs1",
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&private.PublicKey)}),
+		},
+		{
+			name: "not pem",
+			data: []byte("secret"),
+			err:  auth.ErrKeyInvalid,
+		},
+		{
+			name: "not public key",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("secret")}),
+			err:  auth.ErrKeyInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			public, err := auth.ParseRSAPublicKey(test.data)
+			assert.Equal(t, test.err, err)
+			if err == nil {
+				assert.Equal(t, &private.PublicKey, public)
+			}
+		})
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	var (
+		filename = filepath.Join(t.TempDir(), "jwks.json")
+		claims   = auth.Claims{Subject: "1"}
+		jwks     = fmt.Sprintf(`{"keys": [
+			{"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig", "n": %q, "e": %q},
+			{"kty": "oct", "kid": "oct", "k": %q},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": %q, "e": %q},
+			{"kty": "EC", "kid": "ec", "crv": "P-256"}
+		]}`,
+			base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+			base64.RawURLEncoding.EncodeToString(secret),
+			base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+		)
+	)
+
+	assert.Nil(t, os.WriteFile(filename, []byte(jwks), 0600))
+
+	verifier, err := auth.LoadJWKS(filename)
+	assert.Nil(t, err)
+
+	t.Run("rsa key", func(t *testing.T) {
+		result, err := verifier.Verify(authtest.RS256(private, "rsa", claims))
+		assert.Nil(t, err)
+		assert.Equal(t, claims, result)
+	})
+
+	t.Run("symmetric key", func(t *testing.T) {
+		result, err := verifier.Verify(authtest.HS256(secret, "oct", claims))
+		assert.Nil(t, err)
+		assert.Equal(t, claims, result)
+	})
+
+	t.Run("encryption key", func(t *testing.T) {
+		_, err := verifier.Verify(authtest.RS256(private, "enc", claims))
+		assert.Equal(t, auth.ErrTokenInvalid, err)
+	})
+
+	t.Run("without key id", func(t *testing.T) {
+		_, err := verifier.Verify(authtest.HS256(secret, "", claims))
+		assert.Equal(t, auth.ErrTokenInvalid, err)
+	})
+}
+
+func TestParseJWKS_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not json",
+			data: `keys`,
+		},
+		{
+			name: "empty",
+			data: `{"keys": []}`,
+		},
+		{
+			name: "invalid modulus",
+			data: `{"keys": [{"kty": "RSA", "n": "!", "e": "AQAB"}]}`,
+		},
+		{
+			name: "blank secret",
+			data: `{"keys": [{"kty": "oct", "k": ""}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := auth.ParseJWKS([]byte(test.data))
+			assert.Equal(t, auth.ErrKeyInvalid, err)
+		})
+	}
+}
+
+func TestLoadJWKS_notFound(t *testing.T) {
+	_, err := auth.LoadJWKS(filepath.Join(t.TempDir(), "jwks.json"))
+	assert.True(t, os.IsNotExist(err))
+}
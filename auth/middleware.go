@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Fs02/go-todo-backend/apikeys"
+	"github.com/Fs02/go-todo-backend/users"
+)
+
+var (
+	// ErrTokenMissing returned when request doesn't have bearer token.
+	ErrTokenMissing = errors.New("auth: missing bearer token")
+	// ErrSubjectInvalid returned when subject of token is not a user id.
+	ErrSubjectInvalid = errors.New("auth: subject is not a user id")
+)
+
+// Failure renders response of request that can't be authenticated, err is the reason.
+type Failure func(w http.ResponseWriter, r *http.Request, err error)
+
+// Middleware authenticates bearer token, and stores id of the requesting user to context.
+// the token is either api key, or jwt whose subject is the user id.
+// user of jwt is provisioned on the first request, because records of the user reference it.
+// request that can't be authenticated is passed to fail, so the response is rendered the same way as the rest of the api.
+func Middleware(verifier Verifier, apiKeys apikeys.Service, provisioner users.Service, fail Failure) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				ctx   = r.Context()
+				token = r.Header.Get("Authorization")
+			)
+
+			if !strings.HasPrefix(token, "Bearer ") {
+				fail(w, r, ErrTokenMissing)
+				return
+			}
+
+			token = strings.TrimPrefix(token, "Bearer ")
+
+			if apikeys.IsToken(token) {
+				var key apikeys.APIKey
+				if err := apiKeys.Authenticate(ctx, &key, token); err != nil {
+					fail(w, r, err)
+					return
+				}
+
+				ctx = apikeys.NewContext(users.NewContext(ctx, key.UserID), key)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				fail(w, r, err)
+				return
+			}
+
+			id, err := strconv.ParseUint(claims.Subject, 10, 0)
+			if err != nil || id == 0 {
+				fail(w, r, ErrSubjectInvalid)
+				return
+			}
+
+			user := users.User{ID: uint(id), Name: claims.Name, Email: claims.Email}
+			if err := provisioner.Provision(ctx, &user); err != nil {
+				fail(w, r, err)
+				return
+			}
+
+			ctx = users.NewContext(ctx, user.ID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Rejected reports whether err is caused by credentials that aren't valid, rather than failure to check them such as unavailable database.
+func Rejected(err error) bool {
+	return errors.Is(err, ErrTokenMissing) ||
+		errors.Is(err, ErrTokenInvalid) ||
+		errors.Is(err, ErrTokenExpired) ||
+		errors.Is(err, ErrSubjectInvalid) ||
+		errors.Is(err, apikeys.ErrAPIKeyInvalid)
+}
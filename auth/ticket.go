@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/users"
+)
+
+// TicketLifetime is how long ticket can be used after it's issued.
+const TicketLifetime = time.Minute
+
+// Tickets issues and verifies short-lived tokens for requests that can't set Authorization header,
+// such as browser EventSource and WebSocket, the ticket is sent as ticket query parameter instead.
+// tickets are signed using their own secret, so a ticket is never accepted as bearer token.
+type Tickets struct {
+	secret   []byte
+	verifier Verifier
+}
+
+// Issue ticket of the user, it returns the ticket and its expiration time.
+func (t Tickets) Issue(userID uint) (string, time.Time) {
+	var (
+		issued  = now()
+		expires = issued.Add(TicketLifetime)
+		claims  = Claims{Subject: strconv.FormatUint(uint64(userID), 10), ExpiresAt: expires.Unix(), IssuedAt: issued.Unix()}
+		signed  = encodeSegment(Header{Alg: HS256, Typ: "JWT"}) + "." + encodeSegment(claims)
+		mac     = hmac.New(sha256.New, t.secret)
+	)
+
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), expires
+}
+
+// Middleware authenticates ticket query parameter, and stores id of the requesting user to context.
+// request without ticket is authenticated by bearer, so clients that can set Authorization header don't need a ticket.
+func (t Tickets) Middleware(bearer func(http.Handler) http.Handler, fail Failure) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := bearer(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ticket := r.URL.Query().Get("ticket")
+			if ticket == "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := t.verifier.Verify(ticket)
+			if err != nil {
+				fail(w, r, err)
+				return
+			}
+
+			id, err := strconv.ParseUint(claims.Subject, 10, 0)
+			if err != nil || id == 0 {
+				fail(w, r, ErrSubjectInvalid)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(users.NewContext(r.Context(), uint(id))))
+		})
+	}
+}
+
+func encodeSegment(value interface{}) string {
+	// header and claims are always encodable.
+	data, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// NewTickets returns tickets signed using hmac secret, the secret must be different from the secret of bearer tokens.
+func NewTickets(secret []byte) Tickets {
+	return Tickets{
+		secret:   secret,
+		verifier: NewHS256(secret),
+	}
+}
@@ -0,0 +1,114 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/apikeys/apikeystest"
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/auth/authtest"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/users/userstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickets_Issue(t *testing.T) {
+	var (
+		tickets         = auth.NewTickets([]byte("ticket"))
+		ticket, expires = tickets.Issue(1)
+	)
+
+	claims, err := auth.NewHS256([]byte("ticket")).Verify(ticket)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, expires.Unix(), claims.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(auth.TicketLifetime), expires, time.Second)
+
+	// ticket is signed using its own secret, so it isn't a bearer token.
+	_, err = auth.NewHS256(secret).Verify(ticket)
+	assert.Equal(t, auth.ErrTokenInvalid, err)
+}
+
+func TestTickets_Middleware(t *testing.T) {
+	var (
+		tickets   = auth.NewTickets([]byte("ticket"))
+		ticket, _ = tickets.Issue(1)
+		expired   = time.Now().Add(-time.Hour).Unix()
+	)
+
+	tests := []struct {
+		name             string
+		path             string
+		authorization    string
+		err              error
+		userID           uint
+		mockUsersService userstest.MockFunc
+	}{
+		{
+			name:   "ticket",
+			path:   "/?ticket=" + ticket,
+			userID: 1,
+		},
+		{
+			name:             "bearer",
+			path:             "/",
+			authorization:    "Bearer " + authtest.HS256(secret, "", auth.Claims{Subject: "2"}),
+			userID:           2,
+			mockUsersService: userstest.MockProvision(users.User{ID: 2}, nil),
+		},
+		{
+			name: "bearer token as ticket",
+			path: "/?ticket=" + authtest.HS256(secret, "", auth.Claims{Subject: "2"}),
+			err:  auth.ErrTokenInvalid,
+		},
+		{
+			name: "expired",
+			path: "/?ticket=" + authtest.HS256([]byte("ticket"), "", auth.Claims{Subject: "1", ExpiresAt: expired}),
+			err:  auth.ErrTokenExpired,
+		},
+		{
+			name: "subject is not user id",
+			path: "/?ticket=" + authtest.HS256([]byte("ticket"), "", auth.Claims{Subject: "john"}),
+			err:  auth.ErrSubjectInvalid,
+		},
+		{
+			name: "missing",
+			path: "/",
+			err:  auth.ErrTokenMissing,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				err         error
+				userID      uint
+				req, _      = http.NewRequest("GET", test.path, nil)
+				rr          = httptest.NewRecorder()
+				apiKeys     = &apikeystest.Service{}
+				provisioner = &userstest.Service{}
+				fail        = func(w http.ResponseWriter, r *http.Request, reason error) { err = reason }
+				bearer      = auth.Middleware(auth.NewHS256(secret), apiKeys, provisioner, fail)
+				handler     = tickets.Middleware(bearer, fail)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					userID = users.FromContext(r.Context())
+				}))
+			)
+
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+
+			userstest.Mock(provisioner, test.mockUsersService)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.userID, userID)
+
+			apiKeys.AssertExpectations(t)
+			provisioner.AssertExpectations(t)
+		})
+	}
+}
@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"crypto/rand"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Fs02/go-todo-backend/api"
-	"github.com/go-rel/postgres"
+	"github.com/Fs02/go-todo-backend/auth"
+	"github.com/Fs02/go-todo-backend/db"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/Fs02/go-todo-backend/reminders"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/Fs02/go-todo-backend/websocket"
 	"github.com/go-rel/rel"
-	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -23,11 +28,16 @@ var (
 )
 
 func main() {
+	// tracing is initialized first, so it's closed last after spans of the other modules are ended.
+	initTracing()
+
 	var (
 		ctx        = context.Background()
 		port       = os.Getenv("PORT")
 		repository = initRepository()
-		mux        = api.NewMux(repository)
+		bus        = initBus(repository)
+		group      = initWebsocket()
+		mux        = api.NewMux(repository, initAuth(), initTickets(), bus, group)
 		server     = http.Server{
 			Addr:    ":" + port,
 			Handler: mux,
@@ -35,6 +45,9 @@ func main() {
 		shutdown = make(chan struct{})
 	)
 
+	// end event streams when shutting down, otherwise server waits for them to be closed by clients.
+	server.RegisterOnShutdown(bus.Close)
+
 	go gracefulShutdown(ctx, &server, shutdown)
 
 	logger.Info("server starting: http://localhost" + server.Addr)
@@ -46,43 +59,97 @@ func main() {
 }
 
 func initRepository() rel.Repository {
-	var (
-		logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "repository")))
-		dsn       = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-			os.Getenv("POSTGRESQL_USERNAME"),
-			os.Getenv("POSTGRESQL_PASSWORD"),
-			os.Getenv("POSTGRESQL_HOST"),
-			os.Getenv("POSTGRESQL_PORT"),
-			os.Getenv("POSTGRESQL_DATABASE"))
-	)
-
-	adapter, err := postgres.Open(dsn)
+	repository, closeDB, err := db.Open(db.DSN())
 	if err != nil {
 		logger.Fatal(err.Error(), zap.Error(err))
 	}
 	// add to graceful shutdown list.
-	shutdowns = append(shutdowns, adapter.Close)
+	shutdowns = append(shutdowns, closeDB)
+
+	return repository
+}
 
-	repository := rel.New(adapter)
-	repository.Instrumentation(func(ctx context.Context, op string, message string, args ...interface{}) func(err error) {
-		// no op for rel functions.
-		if strings.HasPrefix(op, "rel-") {
-			return func(error) {}
+// initAuth prepares verifier of bearer tokens from either JWT_SECRET, JWT_PUBLIC_KEY or JWT_JWKS.
+// JWT_SECRET is hmac secret of HS256 tokens, JWT_PUBLIC_KEY and JWT_JWKS are path to pem encoded rsa public key and json web key set file.
+func initAuth() auth.Verifier {
+	switch {
+	case os.Getenv("JWT_JWKS") != "":
+		verifier, err := auth.LoadJWKS(os.Getenv("JWT_JWKS"))
+		if err != nil {
+			logger.Fatal("invalid jwks", zap.Error(err))
 		}
+		return verifier
+	case os.Getenv("JWT_PUBLIC_KEY") != "":
+		data, err := os.ReadFile(os.Getenv("JWT_PUBLIC_KEY"))
+		if err != nil {
+			logger.Fatal("invalid public key", zap.Error(err))
+		}
+
+		public, err := auth.ParseRSAPublicKey(data)
+		if err != nil {
+			logger.Fatal("invalid public key", zap.Error(err))
+		}
+		return auth.NewRS256(public)
+	case os.Getenv("JWT_SECRET") != "":
+		return auth.NewHS256([]byte(os.Getenv("JWT_SECRET")))
+	}
 
-		t := time.Now()
+	logger.Fatal("missing JWT_SECRET, JWT_PUBLIC_KEY or JWT_JWKS")
+	return auth.Verifier{}
+}
 
-		return func(err error) {
-			duration := time.Since(t)
-			if err != nil {
-				logger.Error(message, zap.Error(err), zap.Duration("duration", duration), zap.String("operation", op))
-			} else {
-				logger.Info(message, zap.Duration("duration", duration), zap.String("operation", op))
-			}
+// initTickets prepares tickets signed using TICKET_SECRET, tickets of one instance are only accepted by
+// other instances when they share the secret, random secret is used when it's not set.
+func initTickets() auth.Tickets {
+	secret := []byte(os.Getenv("TICKET_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatal("random ticket secret error", zap.Error(err))
 		}
+
+		logger.Warn("missing TICKET_SECRET, tickets are only accepted by this instance")
+	}
+
+	if string(secret) == os.Getenv("JWT_SECRET") {
+		logger.Fatal("TICKET_SECRET must be different from JWT_SECRET")
+	}
+
+	return auth.NewTickets(secret)
+}
+
+// initTracing exports spans as configured by environment, see tracing.ExportEnv.
+func initTracing() {
+	// add to graceful shutdown list, so spans of the last requests are sent.
+	shutdowns = append(shutdowns, tracing.ExportEnv(""))
+}
+
+// initWebsocket returns group of websocket connections, they're closed on shutdown before the modules they use.
+func initWebsocket() *websocket.Group {
+	group := websocket.NewGroup()
+
+	// add to graceful shutdown list, clients are given time to finish closing handshake.
+	shutdowns = append(shutdowns, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return group.Shutdown(ctx)
 	})
 
-	return repository
+	return group
+}
+
+// initBus returns bus whose events are stored as webhook deliveries, and reschedule reminders of changed todos,
+// in the transaction of the change. deliveries and reminders are sent by the worker.
+func initBus(repository rel.Repository) *events.Bus {
+	var (
+		bus = events.NewBus(events.DefaultBufferSize)
+	)
+
+	bus.AddOutbox(webhooks.New(repository, jobs.New(repository)))
+	bus.AddOutbox(reminders.New(repository, nil))
+
+	return bus
 }
 
 func gracefulShutdown(ctx context.Context, server *http.Server, shutdown chan struct{}) {
@@ -100,9 +167,11 @@ func gracefulShutdown(ctx context.Context, server *http.Server, shutdown chan st
 		logger.Fatal("shutdown error", zap.Error(err))
 	}
 
-	// close any other modules.
-	for i := range shutdowns {
-		shutdowns[i]()
+	// close any other modules, in reverse order so modules are closed before what they depend on such as database.
+	for i := len(shutdowns) - 1; i >= 0; i-- {
+		if err := shutdowns[i](); err != nil {
+			logger.Error("shutdown error", zap.Error(err))
+		}
 	}
 
 	close(shutdown)
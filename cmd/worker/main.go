@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/db"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/Fs02/go-todo-backend/reminders"
+	"github.com/Fs02/go-todo-backend/scores"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultConcurrency is number of jobs run at the same time, unless set by WORKER_CONCURRENCY.
+	defaultConcurrency = 4
+	// drainTimeout is how long running jobs are waited on shutdown before they're canceled.
+	drainTimeout = 30 * time.Second
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "main")))
+	shutdowns []func() error
+)
+
+func main() {
+	// tracing is initialized first, so it's closed last after spans of the other modules are ended.
+	initTracing()
+
+	var (
+		ctx        = context.Background()
+		repository = initRepository()
+		jobsSvc    = jobs.New(repository)
+		worker     = jobs.NewWorker(repository, jobsSvc, initConcurrency())
+		shutdown   = make(chan struct{})
+	)
+
+	initWebhooks(worker, repository, jobsSvc)
+	initPurge(worker, repository)
+	initReminders(worker, repository)
+
+	go gracefulShutdown(worker, shutdown)
+
+	logger.Info("worker starting")
+	worker.Run(ctx)
+
+	<-shutdown
+}
+
+func initRepository() rel.Repository {
+	repository, closeDB, err := db.Open(db.DSN())
+	if err != nil {
+		logger.Fatal(err.Error(), zap.Error(err))
+	}
+	// add to graceful shutdown list.
+	shutdowns = append(shutdowns, closeDB)
+
+	return repository
+}
+
+// initTracing exports spans as configured by environment, see tracing.ExportEnv.
+// spans of jobs are children of the span that enqueued them, so they're linked to the request that caused them.
+func initTracing() {
+	// add to graceful shutdown list, so spans of the last jobs are sent.
+	shutdowns = append(shutdowns, tracing.ExportEnv("-worker"))
+}
+
+// initConcurrency returns number of jobs run at the same time from WORKER_CONCURRENCY.
+func initConcurrency() int {
+	str := os.Getenv("WORKER_CONCURRENCY")
+	if str == "" {
+		return defaultConcurrency
+	}
+
+	concurrency, err := strconv.Atoi(str)
+	if err != nil || concurrency < 1 {
+		logger.Fatal("invalid worker concurrency", zap.String("value", str))
+	}
+
+	return concurrency
+}
+
+// initWebhooks sends due webhook deliveries, the job is enqueued whenever a delivery is stored or retried,
+// and also runs every few minutes to pick up deliveries left by worker that stopped while sending them.
+func initWebhooks(worker *jobs.Worker, repository rel.Repository, jobsSvc jobs.Service) {
+	service := webhooks.New(repository, jobsSvc)
+
+	worker.Every(webhooks.DeliverJob, 5*time.Minute)
+	worker.Handle(webhooks.DeliverJob, func(ctx context.Context, job jobs.Job) error {
+		// keep sending while there are due deliveries.
+		for {
+			count, err := service.Deliver(ctx)
+			if err != nil || count == 0 {
+				return err
+			}
+		}
+	})
+}
+
+// initPurge periodically removes todos that has been in trash longer than TRASH_RETENTION.
+func initPurge(worker *jobs.Worker, repository rel.Repository) {
+	var (
+		service   = todos.New(repository, scores.New(repository, nil), nil)
+		retention = todos.DefaultRetention
+	)
+
+	if str := os.Getenv("TRASH_RETENTION"); str != "" {
+		duration, err := time.ParseDuration(str)
+		if err != nil {
+			logger.Fatal("invalid trash retention", zap.Error(err))
+		}
+		retention = duration
+	}
+
+	worker.Every(todos.PurgeJob, time.Hour)
+	worker.Handle(todos.PurgeJob, func(ctx context.Context, job jobs.Job) error {
+		_, err := service.Purge(ctx, retention)
+		return err
+	})
+}
+
+// initReminders sends due reminders every minute through every configured notifier.
+// email is sent through SMTP_HOST and webhook is posted to REMINDER_WEBHOOK_URL, reminders are only logged when neither is set.
+func initReminders(worker *jobs.Worker, repository rel.Repository) {
+	var (
+		notifiers reminders.Notifiers
+	)
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		addr := net.JoinHostPort(host, os.Getenv("SMTP_PORT"))
+		notifiers = append(notifiers, reminders.NewSMTP(addr, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM")))
+	}
+
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, reminders.NewWebhook(url, os.Getenv("REMINDER_WEBHOOK_SECRET")))
+	}
+
+	if len(notifiers) == 0 {
+		notifiers = append(notifiers, reminders.NewLog())
+	}
+
+	service := reminders.New(repository, notifiers)
+
+	worker.Every(reminders.DispatchJob, time.Minute)
+	worker.Handle(reminders.DispatchJob, func(ctx context.Context, job jobs.Job) error {
+		// keep sending while there are due reminders.
+		for {
+			count, err := service.Dispatch(ctx)
+			if err != nil || count == 0 {
+				return err
+			}
+		}
+	})
+}
+
+func gracefulShutdown(worker *jobs.Worker, shutdown chan struct{}) {
+	var (
+		sigint = make(chan os.Signal, 1)
+	)
+
+	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
+	<-sigint
+
+	logger.Info("shutting down worker gracefully")
+
+	// stop claiming jobs, and wait for running jobs to finish.
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := worker.Shutdown(ctx); err != nil {
+		logger.Error("shutdown error", zap.Error(err))
+	}
+
+	// close any other modules, in reverse order so modules are closed before what they depend on such as database.
+	for i := len(shutdowns) - 1; i >= 0; i-- {
+		if err := shutdowns[i](); err != nil {
+			logger.Error("shutdown error", zap.Error(err))
+		}
+	}
+
+	close(shutdown)
+}
@@ -0,0 +1,36 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	// ErrInvalid returned when cursor can't be decoded.
+	ErrInvalid = errors.New("cursor: invalid cursor")
+)
+
+// Encode value as an opaque cursor, value must be json encodable.
+func Encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode opaque cursor into value.
+func Decode(str string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	type page struct {
+		ID int `json:"id"`
+	}
+
+	var (
+		result page
+		str    = Encode(page{ID: 10})
+	)
+
+	assert.Nil(t, Decode(str, &result))
+	assert.Equal(t, page{ID: 10}, result)
+}
+
+func TestDecode_invalid(t *testing.T) {
+	var (
+		result struct{}
+	)
+
+	assert.Equal(t, ErrInvalid, Decode("%%%", &result))
+	assert.Equal(t, ErrInvalid, Decode(Encode("str"), &result))
+}
+
+func TestEncode_panic(t *testing.T) {
+	assert.Panics(t, func() {
+		Encode(make(chan int))
+	})
+}
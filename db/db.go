@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/postgres"
+	"github.com/go-rel/rel"
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "repository")))
+
+	queryDuration = metrics.Default.Histogram("rel_query_duration_seconds", "Duration of database queries in seconds by operation.", metrics.DefaultBuckets, "operation")
+)
+
+// DSN of postgres database from POSTGRESQL_HOST, POSTGRESQL_PORT, POSTGRESQL_DATABASE, POSTGRESQL_USERNAME and POSTGRESQL_PASSWORD.
+func DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("POSTGRESQL_USERNAME"),
+		os.Getenv("POSTGRESQL_PASSWORD"),
+		os.Getenv("POSTGRESQL_HOST"),
+		os.Getenv("POSTGRESQL_PORT"),
+		os.Getenv("POSTGRESQL_DATABASE"))
+}
+
+// Open repository of postgres database, every query is logged, measured and traced as child span of the caller.
+// the returned function closes the database, it should be called on shutdown.
+func Open(dsn string) (rel.Repository, func() error, error) {
+	adapter, err := postgres.Open(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repository := rel.New(adapter)
+	repository.Instrumentation(instrument)
+
+	return repository, adapter.Close, nil
+}
+
+func instrument(ctx context.Context, op string, message string, args ...interface{}) func(err error) {
+	// rel passes the same ctx to the adapter, so span of rel function can't be the parent of the query span.
+	// only the query is traced as child of the caller's span, rel functions are already covered by span of the service.
+	if strings.HasPrefix(op, "rel-") {
+		return func(error) {}
+	}
+
+	_, span := tracing.Start(ctx, op)
+	span.SetKind(tracing.KindClient)
+	span.SetAttribute("db.system", "postgresql")
+	span.SetAttribute("db.statement", message)
+
+	t := time.Now()
+
+	return func(err error) {
+		duration := time.Since(t)
+		queryDuration.Observe(duration.Seconds(), op)
+
+		span.Record(err)
+		span.End()
+
+		if err != nil {
+			logger.Error(message, zap.Error(err), zap.Duration("duration", duration), zap.String("operation", op), tracing.Field(ctx))
+		} else {
+			logger.Info(message, zap.Duration("duration", duration), zap.String("operation", op), tracing.Field(ctx))
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddDueAtToTodos definition
+func MigrateAddDueAtToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DateTime("due_at")
+	})
+
+	schema.CreateIndex("todos", "due_at", []string{"due_at"})
+}
+
+// RollbackAddDueAtToTodos definition
+func RollbackAddDueAtToTodos(schema *rel.Schema) {
+	schema.DropIndex("todos", "due_at")
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("due_at")
+	})
+}
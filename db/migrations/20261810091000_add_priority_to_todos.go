@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddPriorityToTodos definition
+func MigrateAddPriorityToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.SmallInt("priority", rel.Required(true), rel.Default(0))
+	})
+
+	schema.CreateIndex("todos", "priority", []string{"priority"})
+}
+
+// RollbackAddPriorityToTodos definition
+func RollbackAddPriorityToTodos(schema *rel.Schema) {
+	schema.DropIndex("todos", "priority")
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("priority")
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateTags definition
+func MigrateCreateTags(schema *rel.Schema) {
+	schema.CreateTable("tags", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+
+		t.Unique([]string{"name"})
+	})
+}
+
+// RollbackCreateTags definition
+func RollbackCreateTags(schema *rel.Schema) {
+	schema.DropTable("tags")
+}
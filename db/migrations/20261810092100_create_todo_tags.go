@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateTodoTags definition
+func MigrateCreateTodoTags(schema *rel.Schema) {
+	schema.CreateTable("todo_tags", func(t *rel.Table) {
+		t.ID("id")
+		t.Int("todo_id", rel.Unsigned(true))
+		t.Int("tag_id", rel.Unsigned(true))
+
+		t.ForeignKey("todo_id", "todos", "id", rel.OnDelete("CASCADE"))
+		t.ForeignKey("tag_id", "tags", "id", rel.OnDelete("CASCADE"))
+		t.Unique([]string{"todo_id", "tag_id"})
+	})
+
+	schema.CreateIndex("todo_tags", "todo_tags_tag_id", []string{"tag_id"})
+}
+
+// RollbackCreateTodoTags definition
+func RollbackCreateTodoTags(schema *rel.Schema) {
+	schema.DropTable("todo_tags")
+}
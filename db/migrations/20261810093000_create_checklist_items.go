@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateChecklistItems definition
+func MigrateCreateChecklistItems(schema *rel.Schema) {
+	schema.CreateTable("checklist_items", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("todo_id", rel.Unsigned(true))
+		t.String("title")
+		t.Bool("completed")
+		t.Int("order")
+
+		t.ForeignKey("todo_id", "todos", "id", rel.OnDelete("CASCADE"))
+	})
+
+	schema.CreateIndex("checklist_items", "checklist_items_todo_id_order", []string{"todo_id", "order"})
+}
+
+// RollbackCreateChecklistItems definition
+func RollbackCreateChecklistItems(schema *rel.Schema) {
+	schema.DropTable("checklist_items")
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRecurrenceToTodos definition
+func MigrateAddRecurrenceToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.String("recurrence")
+	})
+}
+
+// RollbackAddRecurrenceToTodos definition
+func RollbackAddRecurrenceToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("recurrence")
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddDeletedAtToTodos definition
+func MigrateAddDeletedAtToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DateTime("deleted_at")
+	})
+
+	schema.CreateIndex("todos", "deleted_at", []string{"deleted_at"})
+}
+
+// RollbackAddDeletedAtToTodos definition
+func RollbackAddDeletedAtToTodos(schema *rel.Schema) {
+	schema.DropIndex("todos", "deleted_at")
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("deleted_at")
+	})
+}
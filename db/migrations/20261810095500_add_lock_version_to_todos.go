@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddLockVersionToTodos definition
+func MigrateAddLockVersionToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.Int("lock_version", rel.Default(0), rel.Required(true))
+	})
+}
+
+// RollbackAddLockVersionToTodos definition
+func RollbackAddLockVersionToTodos(schema *rel.Schema) {
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("lock_version")
+	})
+}
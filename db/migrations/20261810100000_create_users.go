@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateUsers definition
+func MigrateCreateUsers(schema *rel.Schema) {
+	schema.CreateTable("users", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+		t.String("email", rel.Required(true))
+
+		t.Unique([]string{"email"})
+	})
+}
+
+// RollbackCreateUsers definition
+func RollbackCreateUsers(schema *rel.Schema) {
+	schema.DropTable("users")
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddUserID definition
+func MigrateAddUserID(schema *rel.Schema) {
+	for _, table := range []string{"todos", "tags", "scores", "points"} {
+		schema.AlterTable(table, func(t *rel.AlterTable) {
+			t.Int("user_id", rel.Unsigned(true))
+			t.ForeignKey("user_id", "users", "id", rel.OnDelete("CASCADE"))
+		})
+
+		schema.CreateIndex(table, table+"_user_id", []string{"user_id"})
+	}
+
+	// records created before multi user belongs to the first user, which is created if there's any record.
+	schema.Exec(rel.Raw(`INSERT INTO users (name, email, created_at, updated_at)
+		SELECT 'Owner', 'owner@localhost', NOW(), NOW()
+		WHERE NOT EXISTS (SELECT 1 FROM users) AND (EXISTS (SELECT 1 FROM todos) OR EXISTS (SELECT 1 FROM tags) OR EXISTS (SELECT 1 FROM scores));`))
+	for _, table := range []string{"todos", "tags", "scores", "points"} {
+		schema.Exec(rel.Raw("UPDATE " + table + " SET user_id = (SELECT MIN(id) FROM users) WHERE user_id IS NULL;"))
+	}
+
+	// tag names are unique per user.
+	schema.Exec(rel.Raw("ALTER TABLE tags DROP CONSTRAINT tags_name_key;"))
+	schema.CreateUniqueIndex("tags", "tags_user_id_name", []string{"user_id", "name"})
+}
+
+// RollbackAddUserID definition
+func RollbackAddUserID(schema *rel.Schema) {
+	schema.DropIndex("tags", "tags_user_id_name")
+	schema.Exec(rel.Raw("ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);"))
+
+	for _, table := range []string{"todos", "tags", "scores", "points"} {
+		schema.DropIndex(table, table+"_user_id")
+		schema.AlterTable(table, func(t *rel.AlterTable) {
+			t.DropColumn("user_id")
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateAPIKeys definition
+func MigrateCreateAPIKeys(schema *rel.Schema) {
+	schema.CreateTable("api_keys", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.String("name", rel.Required(true))
+		t.String("prefix", rel.Required(true))
+		t.String("hash", rel.Required(true))
+		t.String("scopes", rel.Required(true))
+		t.DateTime("revoked_at")
+
+		t.ForeignKey("user_id", "users", "id", rel.OnDelete("CASCADE"))
+		t.Unique([]string{"hash"})
+	})
+
+	schema.CreateIndex("api_keys", "api_keys_user_id", []string{"user_id"})
+}
+
+// RollbackCreateAPIKeys definition
+func RollbackCreateAPIKeys(schema *rel.Schema) {
+	schema.DropTable("api_keys")
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateLists definition
+func MigrateCreateLists(schema *rel.Schema) {
+	schema.CreateTable("lists", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+	})
+
+	schema.CreateTable("list_members", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("list_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.String("role", rel.Required(true))
+
+		t.ForeignKey("list_id", "lists", "id", rel.OnDelete("CASCADE"))
+		t.ForeignKey("user_id", "users", "id", rel.OnDelete("CASCADE"))
+		t.Unique([]string{"list_id", "user_id"})
+	})
+
+	schema.CreateIndex("list_members", "list_members_user_id", []string{"user_id"})
+
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.Int("list_id", rel.Unsigned(true))
+		t.ForeignKey("list_id", "lists", "id", rel.OnDelete("CASCADE"))
+	})
+
+	schema.CreateIndex("todos", "todos_list_id", []string{"list_id"})
+}
+
+// RollbackCreateLists definition
+func RollbackCreateLists(schema *rel.Schema) {
+	schema.DropIndex("todos", "todos_list_id")
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("list_id")
+	})
+
+	schema.DropTable("list_members")
+	schema.DropTable("lists")
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddColorAndOrderToLists definition
+func MigrateAddColorAndOrderToLists(schema *rel.Schema) {
+	schema.AlterTable("lists", func(t *rel.AlterTable) {
+		t.String("color", rel.Limit(7))
+		t.Int("order", rel.Required(true), rel.Default(0))
+	})
+}
+
+// RollbackAddColorAndOrderToLists definition
+func RollbackAddColorAndOrderToLists(schema *rel.Schema) {
+	schema.AlterTable("lists", func(t *rel.AlterTable) {
+		t.DropColumn("order")
+		t.DropColumn("color")
+	})
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddSearchToTodos definition
+func MigrateAddSearchToTodos(schema *rel.Schema) {
+	// generated column keeps the search vector in sync with title, the text search configuration must match the one used by todos search.
+	schema.Exec("ALTER TABLE todos ADD COLUMN search tsvector GENERATED ALWAYS AS (to_tsvector('english', coalesce(title, ''))) STORED")
+	schema.Exec("CREATE INDEX todos_search ON todos USING GIN (search)")
+}
+
+// RollbackAddSearchToTodos definition
+func RollbackAddSearchToTodos(schema *rel.Schema) {
+	schema.DropIndex("todos", "todos_search")
+	schema.AlterTable("todos", func(t *rel.AlterTable) {
+		t.DropColumn("search")
+	})
+}
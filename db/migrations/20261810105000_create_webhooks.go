@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateWebhooks definition
+func MigrateCreateWebhooks(schema *rel.Schema) {
+	schema.CreateTable("webhooks", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.String("url", rel.Limit(2048), rel.Required(true))
+		t.String("events", rel.Required(true))
+		t.String("secret", rel.Required(true))
+
+		t.ForeignKey("user_id", "users", "id", rel.OnDelete("CASCADE"))
+	})
+
+	schema.CreateIndex("webhooks", "webhooks_user_id", []string{"user_id"})
+
+	schema.CreateTable("webhook_deliveries", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("webhook_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.String("event", rel.Required(true))
+		t.Text("payload", rel.Required(true))
+		t.String("status", rel.Required(true))
+		t.Int("attempts", rel.Default(0), rel.Required(true))
+		t.DateTime("next_attempt_at", rel.Required(true))
+		t.Text("last_error")
+		t.Int("response_status")
+		t.DateTime("delivered_at")
+
+		t.ForeignKey("webhook_id", "webhooks", "id", rel.OnDelete("CASCADE"))
+	})
+
+	// worker looks for due pending deliveries, while deliveries of a webhook are listed from the latest.
+	schema.CreateIndex("webhook_deliveries", "webhook_deliveries_status_next_attempt_at", []string{"status", "next_attempt_at"})
+	schema.CreateIndex("webhook_deliveries", "webhook_deliveries_webhook_id", []string{"webhook_id"})
+}
+
+// RollbackCreateWebhooks definition
+func RollbackCreateWebhooks(schema *rel.Schema) {
+	schema.DropTable("webhook_deliveries")
+	schema.DropTable("webhooks")
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateJobs definition
+func MigrateCreateJobs(schema *rel.Schema) {
+	schema.CreateTable("jobs", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+		t.String("key")
+		t.Text("payload")
+		t.String("status", rel.Required(true))
+		t.DateTime("run_at", rel.Required(true))
+		t.Int("attempts", rel.Default(0), rel.Required(true))
+		t.Int("max_attempts", rel.Required(true))
+		t.Text("last_error")
+		t.String("traceparent")
+		t.DateTime("finished_at")
+	})
+
+	// worker looks for due pending jobs, and only a single pending job may have the same key.
+	schema.CreateIndex("jobs", "jobs_status_run_at", []string{"status", "run_at"})
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX jobs_key ON jobs (key) WHERE status = 'pending';"))
+}
+
+// RollbackCreateJobs definition
+func RollbackCreateJobs(schema *rel.Schema) {
+	schema.DropTable("jobs")
+}
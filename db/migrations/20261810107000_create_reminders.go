@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateReminders definition
+func MigrateCreateReminders(schema *rel.Schema) {
+	schema.CreateTable("reminders", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("todo_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("offset_minutes", rel.Default(0), rel.Required(true))
+		t.DateTime("due_at")
+		t.DateTime("remind_at")
+		t.DateTime("sent_at")
+
+		t.ForeignKey("todo_id", "todos", "id", rel.OnDelete("CASCADE"))
+		t.ForeignKey("user_id", "users", "id", rel.OnDelete("CASCADE"))
+	})
+
+	// worker looks for due reminders that aren't sent, and a user can only have one reminder of a todo at the same offset.
+	schema.CreateIndex("reminders", "reminders_remind_at", []string{"remind_at"})
+	schema.CreateUniqueIndex("reminders", "reminders_todo_id_user_id_offset_minutes", []string{"todo_id", "user_id", "offset_minutes"})
+
+	schema.CreateTable("reminder_notifications", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.Int("reminder_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("todo_id", rel.Unsigned(true), rel.Required(true))
+		t.Int("user_id", rel.Unsigned(true), rel.Required(true))
+		t.String("title", rel.Required(true))
+		t.DateTime("due_at")
+		t.DateTime("remind_at", rel.Required(true))
+		t.Text("error")
+
+		t.ForeignKey("reminder_id", "reminders", "id", rel.OnDelete("CASCADE"))
+	})
+
+	// a todo is reminded to a user once at a time, even when multiple reminders are snoozed to the same time.
+	schema.CreateUniqueIndex("reminder_notifications", "reminder_notifications_todo_id_user_id_remind_at", []string{"todo_id", "user_id", "remind_at"})
+}
+
+// RollbackCreateReminders definition
+func RollbackCreateReminders(schema *rel.Schema) {
+	schema.DropTable("reminder_notifications")
+	schema.DropTable("reminders")
+}
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateProvisionUsers definition
+func MigrateProvisionUsers(schema *rel.Schema) {
+	// users are provisioned by id of the identity provider, whose token may not have email, so only emails that are set are unique.
+	schema.Exec(rel.Raw("ALTER TABLE users DROP CONSTRAINT users_email_key;"))
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX users_email ON users (email) WHERE email <> '';"))
+}
+
+// RollbackProvisionUsers definition
+func RollbackProvisionUsers(schema *rel.Schema) {
+	schema.DropIndex("users", "users_email")
+	schema.Exec(rel.Raw("ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);"))
+}
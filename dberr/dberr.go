@@ -0,0 +1,103 @@
+package dberr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound is class of error caused by missing record.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is class of error caused by conflicting data, such as violated unique or foreign key constraint.
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable is class of transient error, the same operation may succeed when it's retried later.
+	ErrUnavailable = errors.New("unavailable")
+
+	// retryAfter of transient error, it's long enough for a reconnect or a lock to be released.
+	retryAfter = 5 * time.Second
+)
+
+// Error returned by repository along with its class.
+type Error struct {
+	Class      error
+	Err        error
+	RetryAfter time.Duration
+}
+
+// Error returns message of the underlying error, class is only used for comparison.
+func (e Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is class of the error.
+func (e Error) Is(target error) bool {
+	return target == e.Class
+}
+
+// Classify wraps err returned by repository with its class, err that can't be classified is returned as is.
+func Classify(err error) error {
+	var (
+		classified Error
+		constraint rel.ConstraintError
+	)
+
+	switch {
+	case err == nil, errors.As(err, &classified):
+		return err
+	case errors.Is(err, rel.ErrNotFound):
+		return Error{Class: ErrNotFound, Err: err}
+	case errors.As(err, &constraint):
+		return Error{Class: ErrConflict, Err: err}
+	case transient(err):
+		return Error{Class: ErrUnavailable, Err: err, RetryAfter: retryAfter}
+	}
+
+	return err
+}
+
+// RetryAfter returns how long to wait before retrying operation that failed with err, zero if it shouldn't be retried.
+func RetryAfter(err error) time.Duration {
+	var classified Error
+	if errors.As(Classify(err), &classified) {
+		return classified.RetryAfter
+	}
+
+	return 0
+}
+
+// transient reports whether err is caused by lost connection, timeout, overloaded database or aborted concurrent transaction.
+func transient(err error) bool {
+	var (
+		pqErr  *pq.Error
+		netErr net.Error
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return true
+	case errors.As(err, &pqErr):
+		switch pqErr.Code.Class() {
+		case "08", "53", "57": // connection exception, insufficient resources and operator intervention.
+			return true
+		}
+
+		// serialization failure and deadlock.
+		return pqErr.Code == "40001" || pqErr.Code == "40P01"
+	case errors.As(err, &netErr):
+		return true
+	}
+
+	return false
+}
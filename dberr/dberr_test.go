@@ -0,0 +1,102 @@
+package dberr
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	var (
+		unique   = rel.ConstraintError{Key: "tags_user_id_name", Type: rel.UniqueConstraint, Err: errors.New("duplicate key")}
+		deadlock = &pq.Error{Code: "40P01", Message: "deadlock detected"}
+	)
+
+	tests := []struct {
+		name       string
+		err        error
+		class      error
+		retryAfter time.Duration
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:  "not found",
+			err:   rel.NotFoundError{},
+			class: ErrNotFound,
+		},
+		{
+			name:  "constraint",
+			err:   unique,
+			class: ErrConflict,
+		},
+		{
+			name:       "deadline exceeded",
+			err:        fmt.Errorf("query: %w", context.DeadlineExceeded),
+			class:      ErrUnavailable,
+			retryAfter: retryAfter,
+		},
+		{
+			name:       "bad connection",
+			err:        driver.ErrBadConn,
+			class:      ErrUnavailable,
+			retryAfter: retryAfter,
+		},
+		{
+			name:       "connection failure",
+			err:        &pq.Error{Code: "08006", Message: "connection failure"},
+			class:      ErrUnavailable,
+			retryAfter: retryAfter,
+		},
+		{
+			name:       "too many connections",
+			err:        &pq.Error{Code: "53300", Message: "too many connections"},
+			class:      ErrUnavailable,
+			retryAfter: retryAfter,
+		},
+		{
+			name:       "deadlock",
+			err:        deadlock,
+			class:      ErrUnavailable,
+			retryAfter: retryAfter,
+		},
+		{
+			name: "syntax error",
+			err:  &pq.Error{Code: "42601", Message: "syntax error"},
+		},
+		{
+			name: "unknown",
+			err:  errors.New("unknown"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Classify(test.err)
+
+			if test.class == nil {
+				assert.Equal(t, test.err, err)
+			} else {
+				assert.True(t, errors.Is(err, test.class))
+				assert.Equal(t, test.err, errors.Unwrap(err))
+			}
+
+			assert.Equal(t, test.retryAfter, RetryAfter(test.err))
+		})
+	}
+}
+
+func TestClassify_classified(t *testing.T) {
+	err := Classify(rel.NotFoundError{})
+
+	assert.Equal(t, err, Classify(err))
+	assert.Equal(t, "entity not found", err.Error())
+}
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBufferSize is number of recent events kept for resuming subscription.
+	DefaultBufferSize = 1024
+	// subscriptionSize is number of events waiting to be received by a subscriber, slower subscriber is dropped.
+	subscriptionSize = 64
+)
+
+// Outbox stores events, or what's derived from them, for use outside the request, such as webhook deliveries and reminder schedules.
+// events are stored using the transaction in ctx, so they're only stored when the change is committed.
+type Outbox interface {
+	Store(ctx context.Context, events []Event) error
+}
+
+// Bus delivers published events to subscribers within the process, recent events are kept in a ring buffer so
+// subscriber that reconnects can resume from the last event it received.
+// nil bus is valid, it drops every published event.
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	ring        []Event
+	head        int
+	count       int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	outboxes    []Outbox
+}
+
+// AddOutbox adds outbox that stores every published event, it must be added before any event is published.
+func (b *Bus) AddOutbox(outbox Outbox) {
+	b.outboxes = append(b.outboxes, outbox)
+}
+
+// Publish events, events published within Transaction are stored to outboxes in the same transaction,
+// and held from subscribers until the transaction commits.
+// error is returned when any outbox fails to store the events, the transaction should be rolled back.
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	if b == nil || len(events) == 0 {
+		return nil
+	}
+
+	for _, outbox := range b.outboxes {
+		if err := outbox.Store(ctx, events); err != nil {
+			return err
+		}
+	}
+
+	if p, ok := ctx.Value(pendingKey).(*pending); ok {
+		p.add(b, events)
+		return nil
+	}
+
+	b.publish(events)
+	return nil
+}
+
+func (b *Bus) publish(events []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		b.lastID++
+		event.ID = b.lastID
+
+		b.ring[(b.head+b.count)%len(b.ring)] = event
+		if b.count < len(b.ring) {
+			b.count++
+		} else {
+			b.head = (b.head + 1) % len(b.ring)
+		}
+
+		for sub := range b.subscribers {
+			if sub.filter(event) {
+				b.deliver(sub, event)
+			}
+		}
+	}
+}
+
+// deliver never blocks publisher, subscriber that can't keep up is dropped so it can resume from the buffer.
+func (b *Bus) deliver(sub *Subscription, event Event) {
+	select {
+	case sub.events <- event:
+	default:
+		logger.Warn("slow subscriber dropped")
+		sub.Dropped = true
+		b.remove(sub)
+	}
+}
+
+// Subscribe to events that matches the filter, events after lastID are replayed when lastID isn't zero.
+// complete is false when some events after lastID are no longer in the buffer, or lastID is from previous process,
+// the subscriber should reload its state as it might have missed some changes.
+func (b *Bus) Subscribe(filter func(Event) bool, lastID uint64) (sub *Subscription, replay []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{events: make(chan Event, subscriptionSize), filter: filter, bus: b}
+	if b.closed {
+		close(sub.events)
+		return sub, nil, true
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+
+	complete = lastID == b.lastID || (lastID < b.lastID && b.count > 0 && lastID+1 >= b.ring[b.head].ID)
+	for i := 0; i < b.count; i++ {
+		if event := b.ring[(b.head+i)%len(b.ring)]; event.ID > lastID && filter(event) {
+			replay = append(replay, event)
+		}
+	}
+
+	return sub, replay, complete
+}
+
+// Close ends every subscription, so long running subscribers such as event stream can finish during shutdown.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// NewBus with buffer that keeps the given number of recent events.
+// ids continue from the start time, so id received from previous process is never mistaken as recent event.
+func NewBus(size int) *Bus {
+	return &Bus{
+		lastID:      uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+		ring:        make([]Event, size),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription to events of a bus.
+type Subscription struct {
+	events chan Event
+	filter func(Event) bool
+	bus    *Bus
+	// Dropped is set when the subscription is ended because the subscriber couldn't keep up, it's only safe to read after Events is closed.
+	Dropped bool
+}
+
+// Events returns channel of subscribed events, it's closed when the subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
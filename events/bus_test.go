@@ -0,0 +1,201 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func all(Event) bool { return true }
+
+func TestBus_Publish(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		bus       = NewBus(4)
+		listID    = uint(2)
+		mine      = func(e Event) bool { return e.UserID == 1 }
+		sub, _, _ = bus.Subscribe(mine, 0)
+	)
+
+	bus.Publish(ctx, New(TodoCreated, 1, nil, map[string]int{"id": 1}), New(TodoCreated, 2, &listID, map[string]int{"id": 2}))
+
+	event := <-sub.Events()
+	assert.Equal(t, TodoCreated, event.Type)
+	assert.Equal(t, `{"id":1}`, string(event.Data))
+	assert.Equal(t, bus.lastID-1, event.ID)
+	assert.Empty(t, sub.Events())
+
+	sub.Close()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	assert.False(t, sub.Dropped)
+}
+
+func TestBus_Publish_nil(t *testing.T) {
+	var bus *Bus
+
+	assert.NotPanics(t, func() {
+		bus.Publish(context.TODO(), New(TodosCleared, 1, nil, struct{}{}))
+	})
+}
+
+// outbox stores events to slice, or fails with err.
+type outbox struct {
+	events []Event
+	err    error
+}
+
+func (o *outbox) Store(ctx context.Context, events []Event) error {
+	if o.err != nil {
+		return o.err
+	}
+
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func TestBus_Publish_outbox(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		bus       = NewBus(4)
+		stored    = &outbox{}
+		sub, _, _ = bus.Subscribe(all, 0)
+	)
+
+	bus.AddOutbox(stored)
+
+	assert.Nil(t, bus.Publish(ctx, New(TodoCreated, 1, nil, 1)))
+	assert.Len(t, stored.events, 1)
+	assert.Equal(t, TodoCreated, (<-sub.Events()).Type)
+}
+
+func TestBus_Publish_outboxError(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		bus       = NewBus(4)
+		err       = errors.New("error")
+		sub, _, _ = bus.Subscribe(all, 0)
+	)
+
+	bus.AddOutbox(&outbox{err: err})
+
+	assert.Equal(t, err, bus.Publish(ctx, New(TodoCreated, 1, nil, 1)))
+	assert.Empty(t, sub.Events())
+}
+
+func TestBus_Publish_slowSubscriber(t *testing.T) {
+	var (
+		bus       = NewBus(DefaultBufferSize)
+		sub, _, _ = bus.Subscribe(all, 0)
+	)
+
+	for i := 0; i <= subscriptionSize; i++ {
+		bus.Publish(context.TODO(), New(TodoUpdated, 1, nil, i))
+	}
+
+	count := 0
+	for range sub.Events() {
+		count++
+	}
+
+	assert.Equal(t, subscriptionSize, count)
+	assert.True(t, sub.Dropped)
+}
+
+func TestBus_Subscribe_resume(t *testing.T) {
+	var (
+		bus   = NewBus(3)
+		start = bus.lastID
+	)
+
+	for i := 1; i <= 5; i++ {
+		bus.Publish(context.TODO(), New(TodoUpdated, uint(i%2), nil, i))
+	}
+
+	tests := []struct {
+		name     string
+		lastID   uint64
+		replay   []string
+		complete bool
+	}{
+		{
+			name:     "fresh",
+			lastID:   0,
+			complete: true,
+		},
+		{
+			name:     "recent",
+			lastID:   start + 3,
+			replay:   []string{"4", "5"},
+			complete: true,
+		},
+		{
+			name:     "oldest in buffer",
+			lastID:   start + 2,
+			replay:   []string{"3", "4", "5"},
+			complete: true,
+		},
+		{
+			name:     "up to date",
+			lastID:   start + 5,
+			complete: true,
+		},
+		{
+			name:   "evicted",
+			lastID: start + 1,
+			replay: []string{"3", "4", "5"},
+		},
+		{
+			name:   "future",
+			lastID: start + 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sub, replay, complete := bus.Subscribe(all, test.lastID)
+			defer sub.Close()
+
+			var data []string
+			for _, event := range replay {
+				data = append(data, string(event.Data))
+			}
+
+			assert.Equal(t, test.replay, data)
+			assert.Equal(t, test.complete, complete)
+		})
+	}
+}
+
+func TestBus_Subscribe_filter(t *testing.T) {
+	var (
+		bus   = NewBus(3)
+		start = bus.lastID
+	)
+
+	bus.Publish(context.TODO(), New(TodoUpdated, 1, nil, 1), New(TodoUpdated, 2, nil, 2))
+
+	sub, replay, complete := bus.Subscribe(func(e Event) bool { return e.UserID == 2 }, start)
+	defer sub.Close()
+
+	assert.Len(t, replay, 1)
+	assert.Equal(t, "2", string(replay[0].Data))
+	assert.True(t, complete)
+}
+
+func TestBus_Close(t *testing.T) {
+	var (
+		bus       = NewBus(3)
+		sub, _, _ = bus.Subscribe(all, 0)
+	)
+
+	bus.Close()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+
+	late, _, _ := bus.Subscribe(all, 0)
+	_, open = <-late.Events()
+	assert.False(t, open)
+}
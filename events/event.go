@@ -0,0 +1,47 @@
+package events
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "events")))
+)
+
+// Type of event.
+type Type string
+
+const (
+	// TodoCreated is published when todo is created or restored from trash.
+	TodoCreated Type = "todo.created"
+	// TodoUpdated is published when todo or any of its tags and checklist items is changed.
+	TodoUpdated Type = "todo.updated"
+	// TodoDeleted is published when todo is moved to trash.
+	TodoDeleted Type = "todo.deleted"
+	// TodosCleared is published for every list whose todos are moved to trash at once, it carries ids of the cleared todos.
+	TodosCleared Type = "todos.cleared"
+	// ScoreChanged is published when user earns or loses points.
+	ScoreChanged Type = "score.changed"
+)
+
+// Event of a change, it's delivered to the user that owns the change, or every member of the list the change belongs to.
+type Event struct {
+	ID     uint64          `json:"id"`
+	Type   Type            `json:"type"`
+	UserID uint            `json:"-"`
+	ListID *uint           `json:"-"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// New event, data is encoded right away so published event can't be changed by the publisher.
+func New(typ Type, userID uint, listID *uint, data interface{}) Event {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		logger.Error("encode event error", zap.Error(err), zap.String("event", string(typ)))
+		raw = json.RawMessage("null")
+	}
+
+	return Event{Type: typ, UserID: userID, ListID: listID, Data: raw}
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-rel/rel"
+)
+
+type ctxKey int
+
+const (
+	pendingKey ctxKey = 0
+)
+
+// pending events published within a transaction, and functions to run after it commits.
+type pending struct {
+	mu     sync.Mutex
+	buses  []*Bus
+	events [][]Event
+	hooks  []func()
+}
+
+func (p *pending) add(bus *Bus, events []Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buses = append(p.buses, bus)
+	p.events = append(p.events, events)
+}
+
+func (p *pending) merge(child *pending) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buses = append(p.buses, child.buses...)
+	p.events = append(p.events, child.events...)
+	p.hooks = append(p.hooks, child.hooks...)
+}
+
+func (p *pending) publish() {
+	for i := range p.buses {
+		p.buses[i].publish(p.events[i])
+	}
+
+	for _, hook := range p.hooks {
+		hook()
+	}
+}
+
+// AfterCommit runs fn after the outermost Transaction of ctx commits, or right away when ctx isn't in a Transaction.
+// fn is dropped along with the events when the transaction is rolled back, it's used to record changes such as metrics only once they're committed.
+func AfterCommit(ctx context.Context, fn func()) {
+	p, ok := ctx.Value(pendingKey).(*pending)
+	if !ok {
+		fn()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hooks = append(p.hooks, fn)
+}
+
+// Transaction runs fn in a transaction of the repository, events published within fn are held until the outermost transaction commits,
+// so subscribers never see changes that are rolled back. events of nested transaction are dropped if the nested transaction fails.
+func Transaction(ctx context.Context, repository rel.Repository, fn func(ctx context.Context) error) error {
+	var (
+		parent, nested = ctx.Value(pendingKey).(*pending)
+		held           = &pending{}
+	)
+
+	err := repository.Transaction(context.WithValue(ctx, pendingKey, held), fn)
+	if err != nil {
+		return err
+	}
+
+	if nested {
+		parent.merge(held)
+	} else {
+		held.publish()
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		bus        = NewBus(DefaultBufferSize)
+		sub, _, _  = bus.Subscribe(all, 0)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {})
+	})
+
+	err := Transaction(ctx, repository, func(ctx context.Context) error {
+		bus.Publish(ctx, New(TodoCreated, 1, nil, 1))
+
+		// failed nested transaction doesn't fail the outer transaction, but its events are dropped.
+		Transaction(ctx, repository, func(ctx context.Context) error {
+			bus.Publish(ctx, New(TodoCreated, 1, nil, 2))
+			return errors.New("rollback")
+		})
+
+		assert.Nil(t, Transaction(ctx, repository, func(ctx context.Context) error {
+			bus.Publish(ctx, New(ScoreChanged, 1, nil, 3))
+			return nil
+		}))
+
+		// held until the outermost transaction commits.
+		assert.Empty(t, sub.Events())
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1", string((<-sub.Events()).Data))
+	assert.Equal(t, "3", string((<-sub.Events()).Data))
+	assert.Empty(t, sub.Events())
+
+	repository.AssertExpectations(t)
+}
+
+func TestTransaction_rollback(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		bus        = NewBus(DefaultBufferSize)
+		sub, _, _  = bus.Subscribe(all, 0)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	err := Transaction(ctx, repository, func(ctx context.Context) error {
+		bus.Publish(ctx, New(TodoCreated, 1, nil, 1))
+		return errors.New("rollback")
+	})
+
+	assert.Equal(t, errors.New("rollback"), err)
+	assert.Empty(t, sub.Events())
+
+	repository.AssertExpectations(t)
+}
+
+func TestAfterCommit(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		ran        []int
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {})
+	})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	assert.Nil(t, Transaction(ctx, repository, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, 1) })
+
+		// dropped along with the failed nested transaction.
+		Transaction(ctx, repository, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, 2) })
+			return errors.New("rollback")
+		})
+
+		assert.Empty(t, ran)
+		return nil
+	}))
+
+	assert.Equal(t, errors.New("rollback"), Transaction(ctx, repository, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, 3) })
+		return errors.New("rollback")
+	}))
+
+	// outside of transaction it runs right away.
+	AfterCommit(ctx, func() { ran = append(ran, 4) })
+
+	assert.Equal(t, []int{1, 4}, ran)
+
+	repository.AssertExpectations(t)
+}
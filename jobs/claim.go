@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type claim struct {
+	repository rel.Repository
+}
+
+// Claim up to limit due jobs of the names, claimed jobs are hidden from other workers until the lease expires,
+// so job of a worker that stops without recording the result runs again afterward. claiming counts as an attempt.
+// jobs are locked with SKIP LOCKED, so multiple workers can claim at the same time without waiting for each other.
+func (c claim) Claim(ctx context.Context, jobs *[]Job, names []string, limit int, lease time.Duration) error {
+	var (
+		t = now()
+	)
+
+	if len(names) == 0 || limit <= 0 {
+		return nil
+	}
+
+	return dberr.Classify(c.repository.Transaction(ctx, func(ctx context.Context) error {
+		query := rel.Where(rel.Eq("status", StatusPending), rel.Lte("run_at", t), rel.InString("name", names)).
+			SortAsc("run_at").SortAsc("id").Limit(limit).Lock("FOR UPDATE SKIP LOCKED")
+
+		if err := c.repository.FindAll(ctx, jobs, query); err != nil {
+			return err
+		}
+
+		if len(*jobs) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, len(*jobs))
+		for i := range *jobs {
+			ids[i] = (*jobs)[i].ID
+			(*jobs)[i].Attempts++
+			(*jobs)[i].RunAt = t.Add(lease)
+		}
+
+		_, err := c.repository.UpdateAny(ctx, rel.From("jobs").Where(rel.In("id", ids...)), rel.Set("run_at", t.Add(lease)), rel.Inc("attempts"))
+		return err
+	}))
+}
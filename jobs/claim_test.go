@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaim(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		names      = []string{"todos.purge", "webhooks.deliver"}
+		query      = rel.Where(rel.Eq("status", StatusPending), rel.Lte("run_at", today), rel.InString("name", names)).
+				SortAsc("run_at").SortAsc("id").Limit(2).Lock("FOR UPDATE SKIP LOCKED")
+		jobs []Job
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Job{{ID: 1, Name: "todos.purge", Attempts: 1}, {ID: 2, Name: "webhooks.deliver"}})
+		repository.ExpectUpdateAny(rel.From("jobs").Where(rel.In("id", uint(1), uint(2))), rel.Set("run_at", today.Add(time.Minute)), rel.Inc("attempts")).UpdatedCount(2)
+	})
+
+	assert.Nil(t, service.Claim(ctx, &jobs, names, 2, time.Minute))
+	assert.Equal(t, []Job{
+		{ID: 1, Name: "todos.purge", Attempts: 2, RunAt: today.Add(time.Minute)},
+		{ID: 2, Name: "webhooks.deliver", Attempts: 1, RunAt: today.Add(time.Minute)},
+	}, jobs)
+
+	repository.AssertExpectations(t)
+}
+
+func TestClaim_empty(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		jobs       []Job
+	)
+
+	assert.Nil(t, service.Claim(ctx, &jobs, nil, 2, time.Minute))
+	assert.Nil(t, service.Claim(ctx, &jobs, []string{"todos.purge"}, 0, time.Minute))
+	assert.Empty(t, jobs)
+
+	repository.AssertExpectations(t)
+}
+
+func TestClaim_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		names      = []string{"todos.purge"}
+		jobs       []Job
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Eq("status", StatusPending), rel.Lte("run_at", today), rel.InString("name", names)).
+			SortAsc("run_at").SortAsc("id").Limit(1).Lock("FOR UPDATE SKIP LOCKED")).ConnectionClosed()
+	})
+
+	err := service.Claim(ctx, &jobs, names, 1, time.Minute)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type complete struct {
+	repository rel.Repository
+}
+
+// Complete job, it's kept for record and never runs again.
+func (c complete) Complete(ctx context.Context, job *Job) error {
+	t := now()
+
+	return dberr.Classify(c.repository.Update(ctx, job,
+		rel.Set("status", StatusDone),
+		rel.Set("finished_at", &t),
+		rel.Set("last_error", ""),
+	))
+}
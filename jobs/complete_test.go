@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		job        = Job{ID: 1, Name: "todos.purge", Status: StatusPending, Attempts: 2, LastError: "timeout"}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectUpdate(rel.Set("status", StatusDone), rel.Set("finished_at", &today), rel.Set("last_error", "")).ForType("jobs.Job")
+
+	assert.Nil(t, service.Complete(ctx, &job))
+	assert.Equal(t, Job{ID: 1, Name: "todos.purge", Status: StatusDone, Attempts: 2, FinishedAt: &today}, job)
+
+	repository.AssertExpectations(t)
+}
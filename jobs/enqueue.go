@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	now = time.Now
+)
+
+type enqueue struct {
+	repository rel.Repository
+}
+
+// Enqueue job to run at its run at time, or as soon as possible when it's not set.
+// it should be called with the transaction of the change that needs the job, so the job is rolled back along with the change.
+// span context in ctx is kept, so the job continues the trace of the request that enqueues it.
+func (e enqueue) Enqueue(ctx context.Context, job *Job) error {
+	job.Status = StatusPending
+
+	if job.RunAt.IsZero() {
+		job.RunAt = now()
+	}
+
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		job.Traceparent = tracing.FormatTraceparent(sc)
+	}
+
+	if job.Key == nil {
+		return dberr.Classify(e.repository.Insert(ctx, job))
+	}
+
+	// job with key is inserted in a savepoint, so the transaction of the caller can go on when the key is taken.
+	err := e.repository.Transaction(ctx, func(ctx context.Context) error {
+		return e.repository.Insert(ctx, job)
+	})
+
+	var cerr rel.ConstraintError
+	if errors.As(err, &cerr) && cerr.Type == rel.UniqueConstraint && cerr.Key == "jobs_key" {
+		logger.Info("job already pending", zap.String("name", job.Name), zap.String("key", *job.Key), tracing.Field(ctx))
+		return nil
+	}
+
+	return dberr.Classify(err)
+}
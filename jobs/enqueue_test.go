@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueue(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		job        = Job{Name: "todos.purge"}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectInsert().For(&Job{Name: "todos.purge", Status: StatusPending, RunAt: today, MaxAttempts: DefaultMaxAttempts})
+
+	assert.Nil(t, service.Enqueue(ctx, &job))
+	assert.NotEmpty(t, job.ID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestEnqueue_traced(t *testing.T) {
+	var (
+		sc         = tracing.SpanContext{TraceID: tracing.TraceID{1}, SpanID: tracing.SpanID{2}, Sampled: true}
+		ctx        = tracing.ContextWithRemote(context.TODO(), sc)
+		repository = reltest.New()
+		service    = New(repository)
+		runAt      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		job        = Job{Name: "todos.remind", RunAt: runAt, MaxAttempts: 3}
+	)
+
+	repository.ExpectInsert().For(&Job{Name: "todos.remind", Status: StatusPending, RunAt: runAt, MaxAttempts: 3, Traceparent: tracing.FormatTraceparent(sc)})
+
+	assert.Nil(t, service.Enqueue(ctx, &job))
+
+	repository.AssertExpectations(t)
+}
+
+func TestEnqueue_keyTaken(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		key        = "todos.purge"
+		job        = Job{Name: "todos.purge", Key: &key}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("jobs.Job").Error(rel.ConstraintError{Key: "jobs_key", Type: rel.UniqueConstraint})
+	})
+
+	assert.Nil(t, service.Enqueue(ctx, &job))
+
+	repository.AssertExpectations(t)
+}
+
+func TestEnqueue_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		key        = "todos.purge"
+		job        = Job{Name: "todos.purge", Key: &key}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("jobs.Job").ConnectionClosed()
+	})
+
+	err := service.Enqueue(ctx, &job)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
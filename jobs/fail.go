@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+const (
+	// retryBackoff is the delay before the first retry, it's doubled on every following retry.
+	retryBackoff = 10 * time.Second
+	// maxRetryBackoff is the longest delay between retries.
+	maxRetryBackoff = time.Hour
+)
+
+type fail struct {
+	repository rel.Repository
+}
+
+// Fail records error of the last attempt, job is retried with exponential backoff until it runs out of attempts.
+func (f fail) Fail(ctx context.Context, job *Job, err error) error {
+	t := now()
+
+	if job.Attempts >= job.MaxAttempts {
+		logger.Warn("job dead", zap.Error(err), zap.Uint("id", job.ID), zap.String("name", job.Name), tracing.Field(ctx))
+		return dberr.Classify(f.repository.Update(ctx, job,
+			rel.Set("status", StatusDead),
+			rel.Set("finished_at", &t),
+			rel.Set("last_error", err.Error()),
+		))
+	}
+
+	logger.Info("job failed", zap.Error(err), zap.Uint("id", job.ID), zap.String("name", job.Name), zap.Int("attempts", job.Attempts), tracing.Field(ctx))
+	return dberr.Classify(f.repository.Update(ctx, job,
+		rel.Set("run_at", t.Add(backoff(job.Attempts))),
+		rel.Set("last_error", err.Error()),
+	))
+}
+
+// backoff before the next attempt, after the given number of failed attempts.
+func backoff(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFail(t *testing.T) {
+	var (
+		today = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		err   = errors.New("timeout")
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name     string
+		job      Job
+		mutators []rel.Mutator
+		result   Job
+	}{
+		{
+			name:     "retried",
+			job:      Job{ID: 1, Status: StatusPending, Attempts: 3, MaxAttempts: 10},
+			mutators: []rel.Mutator{rel.Set("run_at", today.Add(40*time.Second)), rel.Set("last_error", "timeout")},
+			result:   Job{ID: 1, Status: StatusPending, RunAt: today.Add(40 * time.Second), Attempts: 3, MaxAttempts: 10, LastError: "timeout"},
+		},
+		{
+			name:     "dead",
+			job:      Job{ID: 1, Status: StatusPending, Attempts: 10, MaxAttempts: 10},
+			mutators: []rel.Mutator{rel.Set("status", StatusDead), rel.Set("finished_at", &today), rel.Set("last_error", "timeout")},
+			result:   Job{ID: 1, Status: StatusDead, Attempts: 10, MaxAttempts: 10, LastError: "timeout", FinishedAt: &today},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = New(repository)
+				job        = test.job
+			)
+
+			repository.ExpectUpdate(test.mutators...).ForType("jobs.Job")
+
+			assert.Nil(t, service.Fail(ctx, &job, err))
+			assert.Equal(t, test.result, job)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, backoff(1))
+	assert.Equal(t, 20*time.Second, backoff(2))
+	assert.Equal(t, 320*time.Second, backoff(6))
+	assert.Equal(t, time.Hour, backoff(20))
+}
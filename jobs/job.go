@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status of job.
+type Status string
+
+const (
+	// StatusPending is waiting to run at its run at time, including job that is retried.
+	StatusPending Status = "pending"
+	// StatusDone completed successfully.
+	StatusDone Status = "done"
+	// StatusDead failed every attempt.
+	StatusDead Status = "dead"
+)
+
+var (
+	// DefaultMaxAttempts of job that doesn't specify its own.
+	DefaultMaxAttempts = 10
+)
+
+// Job respresent a record stored in jobs table.
+// job is enqueued in the transaction of the change that needs it, so it only runs when the change is committed.
+type Job struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Key is unique among pending jobs, enqueueing job with the key of a pending job is ignored.
+	Key         *string    `json:"key"`
+	Payload     string     `json:"payload"`
+	Status      Status     `json:"status"`
+	RunAt       time.Time  `json:"run_at"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	LastError   string     `json:"last_error"`
+	Traceparent string     `json:"-"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Decode payload of job into v.
+func (j Job) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
+
+// NewJob handled by handler of the name, payload is encoded as json and decoded by the handler.
+func NewJob(name string, payload interface{}) (Job, error) {
+	job := Job{Name: name}
+
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return job, err
+		}
+
+		job.Payload = string(raw)
+	}
+
+	return job, nil
+}
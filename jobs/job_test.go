@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJob(t *testing.T) {
+	var (
+		payload struct {
+			ID uint `json:"id"`
+		}
+	)
+
+	job, err := NewJob("todos.remind", struct {
+		ID uint `json:"id"`
+	}{ID: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, Job{Name: "todos.remind", Payload: `{"id":1}`}, job)
+
+	assert.Nil(t, job.Decode(&payload))
+	assert.Equal(t, uint(1), payload.ID)
+}
+
+func TestNewJob_withoutPayload(t *testing.T) {
+	job, err := NewJob("todos.purge", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, Job{Name: "todos.purge"}, job)
+}
+
+func TestNewJob_error(t *testing.T) {
+	_, err := NewJob("todos.purge", make(chan int))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,54 @@
+package jobstest
+
+import (
+	context "context"
+	time "time"
+
+	jobs "github.com/Fs02/go-todo-backend/jobs"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock job functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockEnqueue util, name is the name of enqueued job.
+func MockEnqueue(name string, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *jobs.Job) bool { return job.Name == name })).
+			Return(err).Once()
+	}
+}
+
+// MockClaim util.
+func MockClaim(result []jobs.Job, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]jobs.Job, names []string, limit int, lease time.Duration) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockComplete util.
+func MockComplete(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Complete", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockFail util.
+func MockFail(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(err)
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "jobs")))
+)
+
+//go:generate mockery --name=Service --case=underscore --output jobstest --outpkg jobstest
+
+// Service instance for job's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Enqueue(ctx context.Context, job *Job) error
+	Claim(ctx context.Context, jobs *[]Job, names []string, limit int, lease time.Duration) error
+	Complete(ctx context.Context, job *Job) error
+	Fail(ctx context.Context, job *Job, err error) error
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	enqueue
+	claim
+	complete
+	fail
+}
+
+var _ Service = (*service)(nil)
+
+// New jobs service.
+func New(repository rel.Repository) Service {
+	return service{
+		enqueue:  enqueue{repository: repository},
+		claim:    claim{repository: repository},
+		complete: complete{repository: repository},
+		fail:     fail{repository: repository},
+	}
+}
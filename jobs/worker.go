@@ -0,0 +1,223 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	// DefaultLease is how long a job may run, the job is canceled afterward and run again by any worker.
+	DefaultLease = 5 * time.Minute
+	// PollInterval is how often worker looks for due jobs while it has free slots.
+	PollInterval = time.Second
+)
+
+// Handler runs a job, returned error fails the attempt and the job is retried.
+type Handler func(ctx context.Context, job Job) error
+
+// Worker claims and runs due jobs, at most concurrency jobs run at the same time.
+type Worker struct {
+	repository rel.Repository
+	service    Service
+	handlers   map[string]Handler
+	schedules  map[string]time.Duration
+	lease      time.Duration
+	slots      chan struct{}
+	freed      chan struct{}
+	stop       chan struct{}
+	abort      chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+	abortOnce  sync.Once
+	wg         sync.WaitGroup
+}
+
+// Handle jobs of the name with handler, it must be called before Run.
+func (w *Worker) Handle(name string, handler Handler) {
+	w.handlers[name] = handler
+}
+
+// Every runs job of the name periodically, the next run is enqueued when the previous one completes or dies.
+// a single run is pending at a time no matter how many workers are running. it must be called before Run.
+func (w *Worker) Every(name string, interval time.Duration) {
+	w.schedules[name] = interval
+}
+
+// Run claims and runs jobs until Shutdown is called, it returns once running jobs are finished.
+func (w *Worker) Run(ctx context.Context) {
+	var (
+		names  = w.names()
+		ticker = time.NewTicker(PollInterval)
+	)
+
+	defer close(w.done)
+	defer ticker.Stop()
+
+	for name := range w.schedules {
+		w.schedule(ctx, name, now())
+	}
+
+	for {
+		if free := cap(w.slots) - len(w.slots); free > 0 {
+			var jobs []Job
+			if err := w.service.Claim(ctx, &jobs, names, free, w.lease); err != nil {
+				logger.Error("claim jobs error", zap.Error(err))
+			}
+
+			for i := range jobs {
+				w.slots <- struct{}{}
+				w.wg.Add(1)
+				go w.run(ctx, jobs[i])
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-w.freed:
+		case <-w.stop:
+			w.wg.Wait()
+			return
+		}
+	}
+}
+
+// Shutdown stops claiming jobs, and waits for running jobs to finish.
+// running jobs are canceled when ctx is done, their result is still recorded before Shutdown returns.
+func (w *Worker) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.abortOnce.Do(func() {
+			close(w.abort)
+		})
+
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run(ctx context.Context, job Job) {
+	defer func() {
+		<-w.slots
+		w.wg.Done()
+
+		select {
+		case w.freed <- struct{}{}:
+		default:
+		}
+	}()
+
+	if sc, ok := tracing.ParseTraceparent(job.Traceparent); ok {
+		ctx = tracing.ContextWithRemote(ctx, sc)
+	}
+
+	ctx, span := tracing.Start(ctx, "job "+job.Name)
+	defer span.End()
+
+	span.SetAttribute("job.id", strconv.FormatUint(uint64(job.ID), 10))
+	span.SetAttribute("job.attempts", strconv.Itoa(job.Attempts))
+
+	// job is canceled when its lease expires or shutdown can't wait any longer,
+	// ctx of the worker is used to record the result so it's recorded either way.
+	runCtx, cancel := context.WithTimeout(ctx, w.lease)
+	go func() {
+		select {
+		case <-w.abort:
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	err := w.call(runCtx, job)
+	cancel()
+
+	if err := w.record(ctx, &job, span.Record(err)); err != nil {
+		logger.Error("record job error", zap.Error(err), zap.Uint("id", job.ID), zap.String("name", job.Name), tracing.Field(ctx))
+	}
+}
+
+// call handler of the job, panic is returned as error so it's retried like any other failure.
+func (w *Worker) call(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("jobs: panic: %v", r)
+		}
+	}()
+
+	return w.handlers[job.Name](ctx, job)
+}
+
+// record result of the job, the next run of periodic job is enqueued in the same transaction once it's finished.
+func (w *Worker) record(ctx context.Context, job *Job, err error) error {
+	return w.repository.Transaction(ctx, func(ctx context.Context) error {
+		if err == nil {
+			if err := w.service.Complete(ctx, job); err != nil {
+				return err
+			}
+		} else if err := w.service.Fail(ctx, job, err); err != nil {
+			return err
+		}
+
+		interval, periodic := w.schedules[job.Name]
+		if !periodic || job.Key == nil || *job.Key != job.Name || job.Status == StatusPending {
+			return nil
+		}
+
+		return w.schedule(ctx, job.Name, now().Add(interval))
+	})
+}
+
+// schedule periodic job, the name is used as key so only one run is pending.
+func (w *Worker) schedule(ctx context.Context, name string, runAt time.Time) error {
+	job := Job{Name: name, Key: &name, RunAt: runAt}
+	if err := w.service.Enqueue(ctx, &job); err != nil {
+		logger.Error("schedule job error", zap.Error(err), zap.String("name", name), tracing.Field(ctx))
+		return err
+	}
+
+	return nil
+}
+
+// names of handled jobs, sorted so the claim query is stable.
+func (w *Worker) names() []string {
+	names := make([]string, 0, len(w.handlers))
+	for name := range w.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewWorker that runs at most concurrency jobs at the same time.
+func NewWorker(repository rel.Repository, service Service, concurrency int) *Worker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Worker{
+		repository: repository,
+		service:    service,
+		handlers:   make(map[string]Handler),
+		schedules:  make(map[string]time.Duration),
+		lease:      DefaultLease,
+		slots:      make(chan struct{}, concurrency),
+		freed:      make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		abort:      make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
@@ -0,0 +1,204 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/Fs02/go-todo-backend/jobs/jobstest"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// lockedRepository serializes transactions, reltest isn't safe to be used by concurrent jobs.
+type lockedRepository struct {
+	*reltest.Repository
+	mu sync.Mutex
+}
+
+func (r *lockedRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.Repository.Transaction(ctx, fn)
+}
+
+// claimOnce mocks claim that returns result the first time, and nothing afterward.
+func claimOnce(service *jobstest.Service, limit int, result ...jobs.Job) {
+	service.On("Claim", mock.Anything, mock.Anything, []string{"todos.purge"}, limit, jobs.DefaultLease).
+		Return(func(ctx context.Context, out *[]jobs.Job, names []string, limit int, lease time.Duration) error {
+			*out = result
+			return nil
+		}).Once()
+	jobstest.Mock(service, jobstest.MockClaim(nil, nil))
+}
+
+func TestWorker(t *testing.T) {
+	var (
+		failed = errors.New("failed")
+	)
+
+	tests := []struct {
+		name    string
+		handler jobs.Handler
+		mock    jobstest.MockFunc
+	}{
+		{
+			name:    "completed",
+			handler: func(ctx context.Context, job jobs.Job) error { return nil },
+			mock:    jobstest.MockComplete(nil),
+		},
+		{
+			name:    "failed",
+			handler: func(ctx context.Context, job jobs.Job) error { return failed },
+			mock: func(service *jobstest.Service) {
+				service.On("Fail", mock.Anything, mock.Anything, failed).Return(nil)
+			},
+		},
+		{
+			name:    "panic",
+			handler: func(ctx context.Context, job jobs.Job) error { panic("boom") },
+			mock: func(service *jobstest.Service) {
+				service.On("Fail", mock.Anything, mock.Anything, errors.New("jobs: panic: boom")).Return(nil)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				repository = reltest.New()
+				service    = &jobstest.Service{}
+				worker     = jobs.NewWorker(repository, service, 2)
+				ran        = make(chan jobs.Job, 1)
+			)
+
+			claimOnce(service, 2, jobs.Job{ID: 1, Name: "todos.purge", Attempts: 1})
+			jobstest.Mock(service, test.mock)
+			repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+			worker.Handle("todos.purge", func(ctx context.Context, job jobs.Job) error {
+				ran <- job
+				return test.handler(ctx, job)
+			})
+
+			go worker.Run(context.TODO())
+
+			assert.Equal(t, jobs.Job{ID: 1, Name: "todos.purge", Attempts: 1}, <-ran)
+			// result is recorded before shutdown returns.
+			assert.Nil(t, worker.Shutdown(context.TODO()))
+
+			repository.AssertExpectations(t)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorker_concurrency(t *testing.T) {
+	var (
+		repository = reltest.New()
+		service    = &jobstest.Service{}
+		worker     = jobs.NewWorker(&lockedRepository{Repository: repository}, service, 2)
+		started    = make(chan struct{}, 2)
+		release    = make(chan struct{})
+	)
+
+	claimOnce(service, 2, jobs.Job{ID: 1, Name: "todos.purge"}, jobs.Job{ID: 2, Name: "todos.purge"})
+	jobstest.Mock(service, jobstest.MockComplete(nil))
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	worker.Handle("todos.purge", func(ctx context.Context, job jobs.Job) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	go worker.Run(context.TODO())
+
+	// both jobs run at the same time.
+	<-started
+	<-started
+	close(release)
+
+	assert.Nil(t, worker.Shutdown(context.TODO()))
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestWorker_Every(t *testing.T) {
+	var (
+		repository = reltest.New()
+		service    = &jobstest.Service{}
+		worker     = jobs.NewWorker(repository, service, 1)
+		key        = "todos.purge"
+		ran        = make(chan struct{}, 1)
+		scheduled  = make(chan time.Time, 2)
+	)
+
+	service.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *jobs.Job) bool { return job.Name == key && *job.Key == key })).
+		Return(func(ctx context.Context, job *jobs.Job) error {
+			scheduled <- job.RunAt
+			return nil
+		}).Twice()
+	claimOnce(service, 1, jobs.Job{ID: 1, Name: "todos.purge", Key: &key, Status: jobs.StatusPending})
+	service.On("Complete", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, job *jobs.Job) error {
+			job.Status = jobs.StatusDone
+			return nil
+		})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	worker.Every("todos.purge", time.Hour)
+	worker.Handle("todos.purge", func(ctx context.Context, job jobs.Job) error {
+		ran <- struct{}{}
+		return nil
+	})
+
+	go worker.Run(context.TODO())
+
+	// first run is scheduled right away, and the next one an interval after the previous run.
+	assert.WithinDuration(t, time.Now(), <-scheduled, time.Minute)
+	<-ran
+	assert.WithinDuration(t, time.Now().Add(time.Hour), <-scheduled, time.Minute)
+
+	assert.Nil(t, worker.Shutdown(context.TODO()))
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestWorker_Shutdown(t *testing.T) {
+	var (
+		repository  = reltest.New()
+		service     = &jobstest.Service{}
+		worker      = jobs.NewWorker(repository, service, 1)
+		started     = make(chan struct{})
+		ctx, cancel = context.WithCancel(context.TODO())
+	)
+
+	claimOnce(service, 1, jobs.Job{ID: 1, Name: "todos.purge"})
+	service.On("Fail", mock.Anything, mock.Anything, context.Canceled).Return(nil)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	worker.Handle("todos.purge", func(ctx context.Context, job jobs.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	go worker.Run(context.TODO())
+	<-started
+
+	// running job is canceled once shutdown can't wait any longer, its result is still recorded.
+	cancel()
+	assert.Equal(t, context.Canceled, worker.Shutdown(ctx))
+
+	repository.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
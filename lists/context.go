@@ -0,0 +1,22 @@
+package lists
+
+import (
+	"context"
+)
+
+type ctxKey int
+
+const (
+	listKey ctxKey = 0
+)
+
+// NewContext returns context that scopes todos of the request to the list.
+func NewContext(ctx context.Context, list List) context.Context {
+	return context.WithValue(ctx, listKey, list)
+}
+
+// FromContext returns list that todos of the request are scoped to, false if the request isn't scoped to a list.
+func FromContext(ctx context.Context) (List, bool) {
+	list, ok := ctx.Value(listKey).(List)
+	return list, ok
+}
@@ -0,0 +1,27 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type create struct {
+	repository rel.Repository
+}
+
+// Create list, the requesting user becomes its owner.
+func (c create) Create(ctx context.Context, list *List) error {
+	if err := list.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	// owner is inserted together with the list as its has many association.
+	list.Members = []Member{{UserID: users.FromContext(ctx), Role: RoleOwner}}
+
+	return c.repository.Insert(ctx, list)
+}
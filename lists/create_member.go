@@ -0,0 +1,45 @@
+package lists
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type createMember struct {
+	repository rel.Repository
+	policy     Policy
+}
+
+// CreateMember shares the list with a user, only its owner is allowed to add members.
+func (cm createMember) CreateMember(ctx context.Context, list List, member *Member) error {
+	member.ListID = list.ID
+
+	if err := member.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	if err := cm.policy.Authorize(ctx, list.ID, ActionManage); err != nil {
+		return err
+	}
+
+	if err := cm.repository.Insert(ctx, member); err != nil {
+		var cerr rel.ConstraintError
+		if errors.As(err, &cerr) {
+			switch cerr.Type {
+			case rel.UniqueConstraint:
+				return ErrMemberTaken
+			case rel.ForeignKeyConstraint:
+				return ErrMemberUserNotFound
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		member   Member
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:   "ok",
+			member: Member{UserID: 2, Role: RoleEditor},
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectInsert().For(&Member{ListID: 1, UserID: 2, Role: RoleEditor})
+			},
+		},
+		{
+			name:   "not owner",
+			member: Member{UserID: 2, Role: RoleEditor},
+			err:    ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+		{
+			name:   "already a member",
+			member: Member{UserID: 2, Role: RoleEditor},
+			err:    ErrMemberTaken,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectInsert().For(&Member{ListID: 1, UserID: 2, Role: RoleEditor}).Error(rel.ConstraintError{Type: rel.UniqueConstraint})
+			},
+		},
+		{
+			name:   "user not found",
+			member: Member{UserID: 3, Role: RoleEditor},
+			err:    ErrMemberUserNotFound,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectInsert().For(&Member{ListID: 1, UserID: 3, Role: RoleEditor}).Error(rel.ConstraintError{Type: rel.ForeignKeyConstraint})
+			},
+		},
+		{
+			name:     "validation error",
+			member:   Member{UserID: 2, Role: "admin"},
+			err:      validation.Errors{ErrRoleInvalid},
+			mockRepo: func(repository *reltest.Repository) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil)
+				member     = test.member
+			)
+
+			test.mockRepo(repository)
+
+			assert.Equal(t, test.err, service.CreateMember(ctx, List{ID: 1}, &member))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		list       = List{Name: "Home", Color: "#ff8800"}
+	)
+
+	repository.ExpectInsert().ForType("lists.List")
+
+	assert.Nil(t, service.Create(ctx, &list))
+	assert.NotEmpty(t, list.ID)
+	assert.Len(t, list.Members, 1)
+	assert.Equal(t, uint(1), list.Members[0].UserID)
+	assert.Equal(t, RoleOwner, list.Members[0].Role)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_validateError(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		list       = List{Name: "Home", Color: "orange"}
+	)
+
+	assert.Equal(t, validation.Errors{ErrListColorInvalid}, service.Create(ctx, &list))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+package lists
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// Todos of a list that's going to be deleted, it's implemented by todos service,
+// so the todos are trashed and moved the same way as any other change of todos.
+type Todos interface {
+	TrashList(ctx context.Context, listID uint) error
+	MoveList(ctx context.Context, listID uint, moveTo uint) error
+}
+
+type delete struct {
+	repository rel.Repository
+	policy     Policy
+	todos      Todos
+}
+
+// Delete list, only its owner is allowed to delete it.
+// Todos of the list are moved to trash of their creators, unless moveTo is given,
+// then the todos, including the trashed ones, are moved to that list. The requesting user must be allowed to write to that list.
+func (d delete) Delete(ctx context.Context, list *List, moveTo *uint) error {
+	if err := d.policy.Authorize(ctx, list.ID, ActionManage); err != nil {
+		return err
+	}
+
+	if moveTo != nil {
+		if *moveTo == list.ID {
+			return ErrListMoveToInvalid
+		}
+
+		if err := d.policy.Authorize(ctx, *moveTo, ActionWrite); err != nil {
+			if errors.Is(err, rel.ErrNotFound) {
+				logger.Warn("validation error", zap.Error(ErrListMoveToNotFound), zap.Uint("list_id", *moveTo), tracing.Field(ctx))
+				return ErrListMoveToNotFound
+			}
+
+			return err
+		}
+	}
+
+	// events of the todos are only published once the list is deleted.
+	return events.Transaction(ctx, d.repository, func(ctx context.Context) error {
+		var err error
+		if moveTo == nil {
+			err = d.todos.TrashList(ctx, list.ID)
+		} else {
+			err = d.todos.MoveList(ctx, list.ID, *moveTo)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := d.repository.Delete(ctx, list); err != nil {
+			return dberr.Classify(err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,27 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+type deleteMember struct {
+	repository rel.Repository
+	policy     Policy
+}
+
+// DeleteMember stops sharing the list with the member, only its owner is allowed to remove members and the list must keep at least one owner.
+func (dm deleteMember) DeleteMember(ctx context.Context, member *Member) error {
+	if err := dm.policy.Authorize(ctx, member.ListID, ActionManage); err != nil {
+		return err
+	}
+
+	return dm.repository.Transaction(ctx, func(ctx context.Context) error {
+		if err := ownerRemains(ctx, dm.repository, *member); err != nil {
+			return err
+		}
+
+		return dm.repository.Delete(ctx, member)
+	})
+}
@@ -0,0 +1,70 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		member   Member
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:   "ok",
+			member: Member{ID: 2, ListID: 1, UserID: 2, Role: RoleEditor},
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", uint(1)), rel.ForUpdate()).Result(List{ID: 1, Name: "Work"})
+					repository.ExpectCount("list_members", rel.Eq("list_id", uint(1)), rel.Eq("role", "owner"), rel.Ne("user_id", uint(2))).Result(1)
+					repository.ExpectDelete().ForType("lists.Member")
+				})
+			},
+		},
+		{
+			name:   "last owner",
+			member: Member{ID: 1, ListID: 1, UserID: 1, Role: RoleOwner},
+			err:    ErrListOwnerRequired,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", uint(1)), rel.ForUpdate()).Result(List{ID: 1, Name: "Work"})
+					repository.ExpectCount("list_members", rel.Eq("list_id", uint(1)), rel.Eq("role", "owner"), rel.Ne("user_id", uint(1))).Result(0)
+				})
+			},
+		},
+		{
+			name:   "not owner",
+			member: Member{ID: 2, ListID: 1, UserID: 2, Role: RoleEditor},
+			err:    ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleViewer})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil)
+				member     = test.member
+			)
+
+			test.mockRepo(repository)
+
+			assert.Equal(t, test.err, service.DeleteMember(ctx, &member))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// todosMock mocks Todos, todos service can't be used by the tests because it depends on this package.
+type todosMock struct {
+	mock.Mock
+}
+
+func (t *todosMock) TrashList(ctx context.Context, listID uint) error {
+	return t.Called(ctx, listID).Error(0)
+}
+
+func (t *todosMock) MoveList(ctx context.Context, listID uint, moveTo uint) error {
+	return t.Called(ctx, listID, moveTo).Error(0)
+}
+
+func TestDelete(t *testing.T) {
+	var (
+		sameID  = uint(1)
+		otherID = uint(2)
+	)
+
+	tests := []struct {
+		name      string
+		moveTo    *uint
+		err       error
+		mockRepo  func(repository *reltest.Repository)
+		mockTodos func(todos *todosMock)
+	}{
+		{
+			name: "trash",
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectDelete().ForType("lists.List")
+				})
+			},
+			mockTodos: func(todos *todosMock) {
+				todos.On("TrashList", mock.Anything, uint(1)).Return(nil)
+			},
+		},
+		{
+			name:   "move",
+			moveTo: &otherID,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectFind(rel.Eq("list_id", uint(2)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 2, UserID: 1, Role: RoleEditor})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectDelete().ForType("lists.List")
+				})
+			},
+			mockTodos: func(todos *todosMock) {
+				todos.On("MoveList", mock.Anything, uint(1), uint(2)).Return(nil)
+			},
+		},
+		{
+			name: "todos can't be trashed",
+			err:  dberr.ErrUnavailable,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {})
+			},
+			mockTodos: func(todos *todosMock) {
+				todos.On("TrashList", mock.Anything, uint(1)).Return(dberr.ErrUnavailable)
+			},
+		},
+		{
+			name:   "move to itself",
+			moveTo: &sameID,
+			err:    ErrListMoveToInvalid,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+			},
+		},
+		{
+			name:   "move to inaccessible list",
+			moveTo: &otherID,
+			err:    ErrListMoveToNotFound,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectFind(rel.Eq("list_id", uint(2)), rel.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+		{
+			name:   "move to read only list",
+			moveTo: &otherID,
+			err:    ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectFind(rel.Eq("list_id", uint(2)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 2, UserID: 1, Role: RoleViewer})
+			},
+		},
+		{
+			name: "not owner",
+			err:  ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				todos      = &todosMock{}
+				service    = New(repository, todos)
+				list       = List{ID: 1, Name: "Home"}
+			)
+
+			test.mockRepo(repository)
+			if test.mockTodos != nil {
+				test.mockTodos(todos)
+			}
+
+			assert.True(t, errors.Is(service.Delete(ctx, &list, test.moveTo), test.err))
+
+			repository.AssertExpectations(t)
+			todos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete_error(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		todos      = &todosMock{}
+		service    = New(repository, todos)
+		list       = List{ID: 1, Name: "Home"}
+	)
+
+	repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDelete().ForType("lists.List").ConnectionClosed()
+	})
+	todos.On("TrashList", mock.Anything, uint(1)).Return(nil)
+
+	assert.True(t, errors.Is(service.Delete(ctx, &list, nil), dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+	todos.AssertExpectations(t)
+}
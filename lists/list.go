@@ -0,0 +1,80 @@
+package lists
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrListNameBlank validation error.
+	ErrListNameBlank = validation.New("name", "blank", "Name can't be blank")
+	// ErrListColorInvalid validation error.
+	ErrListColorInvalid = validation.New("color", "invalid", "Color must be a hex color such as #ff8800")
+	// ErrListMoveToInvalid returned when todos of deleted list are moved to the list itself.
+	ErrListMoveToInvalid = validation.New("list_id", "invalid", "Todos can't be moved to the deleted list")
+	// ErrListMoveToNotFound returned when list to move todos to doesn't exist or isn't accessible.
+	ErrListMoveToNotFound = validation.New("list_id", "not_found", "List to move todos to not found")
+	// ErrMemberUserNotFound validation error.
+	ErrMemberUserNotFound = validation.New("user_id", "not_found", "User not found")
+	// ErrMemberTaken validation error.
+	ErrMemberTaken = validation.New("user_id", "taken", "User is already a member of the list")
+	// ErrListOwnerRequired returned when the last owner of list would be removed or lose its role.
+	ErrListOwnerRequired = validation.New("role", "owner_required", "List must have at least one owner")
+
+	colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// List respresent a record stored in lists table, which groups todos that are shared with its members.
+type List struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color,omitempty"`
+	Order     int       `json:"order"`
+	Members   []Member  `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate list.
+func (l List) Validate() error {
+	var errs validation.Errors
+
+	if len(strings.TrimSpace(l.Name)) == 0 {
+		errs.Add(ErrListNameBlank)
+	}
+
+	if l.Color != "" && !colorPattern.MatchString(l.Color) {
+		errs.Add(ErrListColorInvalid)
+	}
+
+	return errs.Err()
+}
+
+// Member respresent a record stored in list_members table, which grants user access to a list.
+type Member struct {
+	ID        uint      `json:"-"`
+	ListID    uint      `json:"-"`
+	UserID    uint      `json:"user_id"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Table name of member.
+func (Member) Table() string {
+	return "list_members"
+}
+
+// Validate member.
+func (m Member) Validate() error {
+	var errs validation.Errors
+
+	if !m.Role.Valid() {
+		errs.Add(ErrRoleInvalid)
+	}
+
+	return errs.Err()
+}
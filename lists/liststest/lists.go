@@ -0,0 +1,116 @@
+package liststest
+
+import (
+	context "context"
+
+	lists "github.com/Fs02/go-todo-backend/lists"
+	rel "github.com/go-rel/rel"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock list functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockSearch util.
+func MockSearch(result []lists.List, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Search", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]lists.List) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreate util.
+func MockCreate(result lists.List, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Create", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *lists.List) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockUpdate util.
+func MockUpdate(result lists.List, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Update", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *lists.List, changeset rel.Changeset) error {
+				if result.ID != out.ID {
+					panic("inconsistent id")
+				}
+
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDelete util.
+func MockDelete(moveTo *uint, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Delete", mock.Anything, mock.Anything, moveTo).Return(err)
+	}
+}
+
+// MockSearchMembers util.
+func MockSearchMembers(result []lists.Member, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchMembers", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]lists.Member, list lists.List) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreateMember util.
+func MockCreateMember(result lists.Member, err error) MockFunc {
+	return func(service *Service) {
+		service.On("CreateMember", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, list lists.List, out *lists.Member) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockUpdateMember util.
+func MockUpdateMember(result lists.Member, err error) MockFunc {
+	return func(service *Service) {
+		service.On("UpdateMember", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *lists.Member, changeset rel.Changeset) error {
+				if result.ID != out.ID {
+					panic("inconsistent id")
+				}
+
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDeleteMember util.
+func MockDeleteMember(err error) MockFunc {
+	return func(service *Service) {
+		service.On("DeleteMember", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockAuthorize util.
+func MockAuthorize(listID uint, action lists.Action, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Authorize", mock.Anything, listID, action).Return(err)
+	}
+}
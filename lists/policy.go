@@ -0,0 +1,53 @@
+package lists
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "lists")))
+	// ErrForbidden returned when member's role doesn't allow the action.
+	ErrForbidden = errors.New("You are not allowed to do this on the list")
+)
+
+// Policy authorizes the requesting user to perform actions on lists.
+type Policy struct {
+	repository rel.Repository
+}
+
+// Authorize returns rel.ErrNotFound if the requesting user isn't a member of the list, so non members can't tell whether the list exists,
+// and ErrForbidden if the member's role doesn't allow the action.
+func (p Policy) Authorize(ctx context.Context, listID uint, action Action) error {
+	var (
+		userID = users.FromContext(ctx)
+		member Member
+	)
+
+	if err := p.repository.Find(ctx, &member, rel.Eq("list_id", listID), rel.Eq("user_id", userID)); err != nil {
+		return err
+	}
+
+	if !member.Role.Allows(action) {
+		logger.Warn("forbidden", zap.Uint("list_id", listID), zap.Uint("user_id", userID), zap.String("role", string(member.Role)), zap.String("action", string(action)), tracing.Field(ctx))
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// Accessible returns filter of lists which the requesting user is allowed to perform the action on, the lists are filtered by field.
+func Accessible(ctx context.Context, field string, action Action) rel.FilterQuery {
+	return rel.In(field, rel.Select("list_id").From("list_members").
+		Where(rel.Eq("user_id", users.FromContext(ctx)), rel.InString("role", Roles(action))))
+}
+
+// NewPolicy of lists.
+func NewPolicy(repository rel.Repository) Policy {
+	return Policy{repository: repository}
+}
@@ -0,0 +1,60 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Authorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   Action
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:   "editor writes",
+			action: ActionWrite,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+		{
+			name:   "editor manages",
+			action: ActionManage,
+			err:    ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+		{
+			name:   "not member",
+			action: ActionRead,
+			err:    rel.NotFoundError{},
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				policy     = NewPolicy(repository)
+			)
+
+			test.mockRepo(repository)
+
+			assert.Equal(t, test.err, policy.Authorize(ctx, 1, test.action))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
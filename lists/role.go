@@ -0,0 +1,70 @@
+package lists
+
+import (
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrRoleInvalid validation error.
+	ErrRoleInvalid = validation.New("role", "invalid", "Role must be one of owner, editor or viewer")
+)
+
+// Action performed on a list or its todos.
+type Action string
+
+const (
+	// ActionRead reads list and its todos.
+	ActionRead Action = "read"
+	// ActionWrite creates, updates and deletes todos of list.
+	ActionWrite Action = "write"
+	// ActionManage updates and deletes list itself, and manages its members.
+	ActionManage Action = "manage"
+)
+
+// Role of list member.
+type Role string
+
+const (
+	// RoleOwner can do anything to the list.
+	RoleOwner Role = "owner"
+	// RoleEditor can read and write todos of the list.
+	RoleEditor Role = "editor"
+	// RoleViewer can only read todos of the list.
+	RoleViewer Role = "viewer"
+)
+
+// Valid returns true if role is known.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleEditor, RoleViewer:
+		return true
+	}
+
+	return false
+}
+
+// Allows returns true if member with the role is allowed to perform the action.
+func (r Role) Allows(action Action) bool {
+	switch action {
+	case ActionRead:
+		return r.Valid()
+	case ActionWrite:
+		return r == RoleOwner || r == RoleEditor
+	case ActionManage:
+		return r == RoleOwner
+	}
+
+	return false
+}
+
+// Roles returns roles that are allowed to perform the action.
+func Roles(action Action) []string {
+	var roles []string
+	for _, role := range []Role{RoleOwner, RoleEditor, RoleViewer} {
+		if role.Allows(action) {
+			roles = append(roles, string(role))
+		}
+	}
+
+	return roles
+}
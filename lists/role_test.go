@@ -0,0 +1,39 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role   Role
+		action Action
+		allows bool
+	}{
+		{role: RoleOwner, action: ActionRead, allows: true},
+		{role: RoleOwner, action: ActionWrite, allows: true},
+		{role: RoleOwner, action: ActionManage, allows: true},
+		{role: RoleEditor, action: ActionRead, allows: true},
+		{role: RoleEditor, action: ActionWrite, allows: true},
+		{role: RoleEditor, action: ActionManage, allows: false},
+		{role: RoleViewer, action: ActionRead, allows: true},
+		{role: RoleViewer, action: ActionWrite, allows: false},
+		{role: RoleViewer, action: ActionManage, allows: false},
+		{role: Role("guest"), action: ActionRead, allows: false},
+		{role: RoleOwner, action: Action("destroy"), allows: false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.role)+" "+string(test.action), func(t *testing.T) {
+			assert.Equal(t, test.allows, test.role.Allows(test.action))
+		})
+	}
+}
+
+func TestRoles(t *testing.T) {
+	assert.Equal(t, []string{"owner", "editor", "viewer"}, Roles(ActionRead))
+	assert.Equal(t, []string{"owner", "editor"}, Roles(ActionWrite))
+	assert.Equal(t, []string{"owner"}, Roles(ActionManage))
+}
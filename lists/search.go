@@ -0,0 +1,16 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+type search struct {
+	repository rel.Repository
+}
+
+// Search lists which the requesting user is a member of, sorted by their order.
+func (s search) Search(ctx context.Context, lists *[]List) error {
+	return s.repository.FindAll(ctx, lists, rel.Where(Accessible(ctx, "id", ActionRead)).SortAsc("order").SortAsc("id"))
+}
@@ -0,0 +1,16 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+type searchMembers struct {
+	repository rel.Repository
+}
+
+// SearchMembers of the list in the order they joined, the list must be authorized before its members are searched.
+func (sm searchMembers) SearchMembers(ctx context.Context, members *[]Member, list List) error {
+	return sm.repository.FindAll(ctx, members, rel.Where(rel.Eq("list_id", list.ID)).SortAsc("id"))
+}
@@ -0,0 +1,28 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchMembers(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		members    []Member
+		result     = []Member{{ID: 1, ListID: 1, UserID: 1, Role: RoleOwner}, {ID: 2, ListID: 1, UserID: 2, Role: RoleViewer}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("list_id", uint(1))).SortAsc("id")).Result(result)
+
+	assert.Nil(t, service.SearchMembers(ctx, &members, List{ID: 1}))
+	assert.Equal(t, result, members)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,28 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		lists      []List
+		result     = []List{{ID: 1, Name: "Home", Color: "#ff8800"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(Accessible(ctx, "id", ActionRead)).SortAsc("order").SortAsc("id")).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &lists))
+	assert.Equal(t, result, lists)
+
+	repository.AssertExpectations(t)
+}
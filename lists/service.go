@@ -0,0 +1,58 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+//go:generate mockery --name=Service --case=underscore --output liststest --outpkg liststest
+
+// Service instance for list's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Search(ctx context.Context, lists *[]List) error
+	Create(ctx context.Context, list *List) error
+	Update(ctx context.Context, list *List, changes rel.Changeset) error
+	Delete(ctx context.Context, list *List, moveTo *uint) error
+	SearchMembers(ctx context.Context, members *[]Member, list List) error
+	CreateMember(ctx context.Context, list List, member *Member) error
+	UpdateMember(ctx context.Context, member *Member, changes rel.Changeset) error
+	DeleteMember(ctx context.Context, member *Member) error
+	Authorize(ctx context.Context, listID uint, action Action) error
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	search
+	create
+	update
+	delete
+	searchMembers
+	createMember
+	updateMember
+	deleteMember
+	Policy
+}
+
+var _ Service = (*service)(nil)
+
+// New Lists service, todos of deleted list are trashed or moved by todos.
+func New(repository rel.Repository, todos Todos) Service {
+	var (
+		policy = NewPolicy(repository)
+	)
+
+	return service{
+		search:        search{repository: repository},
+		create:        create{repository: repository},
+		update:        update{repository: repository, policy: policy},
+		delete:        delete{repository: repository, policy: policy, todos: todos},
+		searchMembers: searchMembers{repository: repository},
+		createMember:  createMember{repository: repository, policy: policy},
+		updateMember:  updateMember{repository: repository, policy: policy},
+		deleteMember:  deleteMember{repository: repository, policy: policy},
+		Policy:        policy,
+	}
+}
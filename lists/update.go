@@ -0,0 +1,28 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type update struct {
+	repository rel.Repository
+	policy     Policy
+}
+
+// Update list, only its owner is allowed to update it.
+func (u update) Update(ctx context.Context, list *List, changes rel.Changeset) error {
+	if err := list.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	if err := u.policy.Authorize(ctx, list.ID, ActionManage); err != nil {
+		return err
+	}
+
+	return u.repository.Update(ctx, list, changes)
+}
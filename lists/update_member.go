@@ -0,0 +1,60 @@
+package lists
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type updateMember struct {
+	repository rel.Repository
+	policy     Policy
+}
+
+// UpdateMember changes role of the member, only its owner is allowed to change it and the list must keep at least one owner.
+func (um updateMember) UpdateMember(ctx context.Context, member *Member, changes rel.Changeset) error {
+	if err := member.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	if err := um.policy.Authorize(ctx, member.ListID, ActionManage); err != nil {
+		return err
+	}
+
+	return um.repository.Transaction(ctx, func(ctx context.Context) error {
+		if member.Role != RoleOwner {
+			if err := ownerRemains(ctx, um.repository, *member); err != nil {
+				return err
+			}
+		}
+
+		return um.repository.Update(ctx, member, changes)
+	})
+}
+
+// ownerRemains returns ErrListOwnerRequired if the list has no other owner than the member.
+// the list is locked, so owners that demote or remove each other at the same time can't leave the list without owner.
+func ownerRemains(ctx context.Context, repository rel.Repository, member Member) error {
+	var (
+		list List
+	)
+
+	if err := repository.Find(ctx, &list, rel.Eq("id", member.ListID), rel.ForUpdate()); err != nil {
+		return err
+	}
+
+	owners, err := repository.Count(ctx, "list_members", rel.Eq("list_id", member.ListID), rel.Eq("role", string(RoleOwner)), rel.Ne("user_id", member.UserID))
+	if err != nil {
+		return err
+	}
+
+	if owners == 0 {
+		logger.Warn("validation error", zap.Error(ErrListOwnerRequired), zap.Uint("list_id", member.ListID), zap.Uint("user_id", member.UserID), tracing.Field(ctx))
+		return ErrListOwnerRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		member   Member
+		role     Role
+		err      error
+		mockRepo func(repository *reltest.Repository, changes rel.Changeset)
+	}{
+		{
+			name:   "promote",
+			member: Member{ID: 2, ListID: 1, UserID: 2, Role: RoleViewer},
+			role:   RoleOwner,
+			mockRepo: func(repository *reltest.Repository, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectUpdate(changes).ForType("lists.Member")
+				})
+			},
+		},
+		{
+			name:   "demote",
+			member: Member{ID: 1, ListID: 1, UserID: 1, Role: RoleOwner},
+			role:   RoleEditor,
+			mockRepo: func(repository *reltest.Repository, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", uint(1)), rel.ForUpdate()).Result(List{ID: 1, Name: "Work"})
+					repository.ExpectCount("list_members", rel.Eq("list_id", uint(1)), rel.Eq("role", "owner"), rel.Ne("user_id", uint(1))).Result(1)
+					repository.ExpectUpdate(changes).ForType("lists.Member")
+				})
+			},
+		},
+		{
+			name:   "demote last owner",
+			member: Member{ID: 1, ListID: 1, UserID: 1, Role: RoleOwner},
+			role:   RoleEditor,
+			err:    ErrListOwnerRequired,
+			mockRepo: func(repository *reltest.Repository, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", uint(1)), rel.ForUpdate()).Result(List{ID: 1, Name: "Work"})
+					repository.ExpectCount("list_members", rel.Eq("list_id", uint(1)), rel.Eq("role", "owner"), rel.Ne("user_id", uint(1))).Result(0)
+				})
+			},
+		},
+		{
+			name:   "not owner",
+			member: Member{ID: 2, ListID: 1, UserID: 2, Role: RoleViewer},
+			role:   RoleOwner,
+			err:    ErrForbidden,
+			mockRepo: func(repository *reltest.Repository, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+		{
+			name:     "validation error",
+			member:   Member{ID: 2, ListID: 1, UserID: 2, Role: RoleViewer},
+			role:     "admin",
+			err:      validation.Errors{ErrRoleInvalid},
+			mockRepo: func(repository *reltest.Repository, changes rel.Changeset) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil)
+				member     = test.member
+				changes    = rel.NewChangeset(&member)
+			)
+
+			member.Role = test.role
+			test.mockRepo(repository, changes)
+
+			assert.Equal(t, test.err, service.UpdateMember(ctx, &member, changes))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
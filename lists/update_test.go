@@ -0,0 +1,62 @@
+package lists
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     List
+		err      error
+		mockRepo func(repository *reltest.Repository, list *List, changes rel.Changeset)
+	}{
+		{
+			name: "ok",
+			list: List{ID: 1, Name: "Work"},
+			mockRepo: func(repository *reltest.Repository, list *List, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleOwner})
+				repository.ExpectUpdate(changes).ForType("lists.List")
+			},
+		},
+		{
+			name: "not owner",
+			list: List{ID: 1, Name: "Work"},
+			err:  ErrForbidden,
+			mockRepo: func(repository *reltest.Repository, list *List, changes rel.Changeset) {
+				repository.ExpectFind(rel.Eq("list_id", uint(1)), rel.Eq("user_id", uint(1))).Result(Member{ListID: 1, UserID: 1, Role: RoleEditor})
+			},
+		},
+		{
+			name:     "validation error",
+			list:     List{ID: 1, Name: " "},
+			err:      validation.Errors{ErrListNameBlank},
+			mockRepo: func(repository *reltest.Repository, list *List, changes rel.Changeset) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil)
+				list       = test.list
+				changes    = rel.NewChangeset(&list)
+			)
+
+			test.mockRepo(repository, &list, changes)
+
+			assert.Equal(t, test.err, service.Update(ctx, &list, changes))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// Default registry, metrics of every package are registered here and exposed by /metrics endpoint.
+	Default = NewRegistry()
+
+	// DefaultBuckets of histogram in seconds, suitable for latency of http request and database query.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// metric is a family of series that shares the same name and label names.
+type metric interface {
+	write(w io.Writer) error
+}
+
+// Registry of metrics, it writes every registered metric in prometheus text exposition format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// Counter registers a counter, panics if the name is already registered.
+func (r *Registry) Counter(name string, help string, labels ...string) *Counter {
+	c := &Counter{family: newFamily(name, help, "counter", labels)}
+	r.register(name, c)
+	return c
+}
+
+// Gauge registers a gauge, panics if the name is already registered.
+func (r *Registry) Gauge(name string, help string, labels ...string) *Gauge {
+	g := &Gauge{family: newFamily(name, help, "gauge", labels)}
+	r.register(name, g)
+	return g
+}
+
+// Histogram registers a histogram with upper bounds of the buckets in increasing order, panics if the name is already registered.
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{family: newFamily(name, help, "histogram", labels), buckets: buckets}
+	r.register(name, h)
+	return h
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic("metrics: " + name + " is already registered")
+	}
+
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo writes every metric in prometheus text exposition format, metrics are written in the order they're registered.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	cw := &countWriter{w: w}
+	for _, m := range metrics {
+		if err := m.write(cw); err != nil {
+			return cw.n, err
+		}
+	}
+
+	return cw.n, nil
+}
+
+// NewRegistry returns empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Counter is a value that only goes up, such as number of served requests.
+type Counter struct {
+	family
+}
+
+// Inc increments the counter of the label values by one.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds value to the counter of the label values, panics if value is negative.
+func (c *Counter) Add(value float64, values ...string) {
+	if value < 0 {
+		panic("metrics: counter " + c.name + " can't decrease")
+	}
+
+	c.update(values, func(s *series) { s.value += value })
+}
+
+func (c *Counter) write(w io.Writer) error {
+	return c.writeEach(w, func(w io.Writer, labels string, s *series) error {
+		return sample(w, c.name, labels, s.value)
+	})
+}
+
+// Gauge is a value that can go up and down, such as result of the last check.
+type Gauge struct {
+	family
+}
+
+// Set sets the gauge of the label values.
+func (g *Gauge) Set(value float64, values ...string) {
+	g.update(values, func(s *series) { s.value = value })
+}
+
+// Add adds value to the gauge of the label values, value can be negative.
+func (g *Gauge) Add(value float64, values ...string) {
+	g.update(values, func(s *series) { s.value += value })
+}
+
+func (g *Gauge) write(w io.Writer) error {
+	return g.writeEach(w, func(w io.Writer, labels string, s *series) error {
+		return sample(w, g.name, labels, s.value)
+	})
+}
+
+// Histogram counts observations in configurable buckets, such as duration of requests.
+type Histogram struct {
+	family
+	buckets []float64
+}
+
+// Observe adds an observation to the histogram of the label values.
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.update(values, func(s *series) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.buckets))
+		}
+
+		for i, bound := range h.buckets {
+			if value <= bound {
+				s.counts[i]++
+			}
+		}
+
+		s.value += value
+		s.count++
+	})
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	return h.writeEach(w, func(w io.Writer, labels string, s *series) error {
+		for i, bound := range h.buckets {
+			if err := sample(w, h.name+"_bucket", join(labels, `le="`+formatFloat(bound)+`"`), float64(s.counts[i])); err != nil {
+				return err
+			}
+		}
+
+		if err := sample(w, h.name+"_bucket", join(labels, `le="+Inf"`), float64(s.count)); err != nil {
+			return err
+		}
+
+		if err := sample(w, h.name+"_sum", labels, s.value); err != nil {
+			return err
+		}
+
+		return sample(w, h.name+"_count", labels, float64(s.count))
+	})
+}
+
+// series of a metric identified by its label values.
+type series struct {
+	values []string
+	value  float64
+	count  uint64
+	counts []uint64
+}
+
+// family holds every series of a metric.
+type family struct {
+	mu     sync.Mutex
+	name   string
+	help   string
+	typ    string
+	labels []string
+	series map[string]*series
+}
+
+func (f *family) update(values []string, fn func(s *series)) {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\x00")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		f.series[key] = s
+	}
+
+	fn(s)
+}
+
+// writeEach writes header of the metric followed by every series sorted by its label values, so the output is stable.
+func (f *family) writeEach(w io.Writer, fn func(w io.Writer, labels string, s *series) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.typ); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+
+		pairs := make([]string, len(f.labels))
+		for i := range f.labels {
+			pairs[i] = f.labels[i] + `="` + escapeLabel(s.values[i]) + `"`
+		}
+
+		if err := fn(w, strings.Join(pairs, ","), s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newFamily(name string, help string, typ string, labels []string) family {
+	return family{name: name, help: help, typ: typ, labels: labels, series: make(map[string]*series)}
+}
+
+// sample writes a single line of the exposition.
+func sample(w io.Writer, name string, labels string, value float64) error {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+
+	_, err := io.WriteString(w, name+" "+formatFloat(value)+"\n")
+	return err
+}
+
+func join(labels string, label string) string {
+	if labels == "" {
+		return label
+	}
+
+	return labels + "," + label
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelReplacer.Replace(value)
+}
+
+// countWriter counts written bytes, so WriteTo can report it.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
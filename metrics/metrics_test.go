@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	var (
+		buf       bytes.Buffer
+		registry  = NewRegistry()
+		requests  = registry.Counter("http_requests_total", "Total number of http requests.", "route", "status")
+		up        = registry.Gauge("healthz_up", "Whether the service is up.", "service")
+		duration  = registry.Histogram("http_request_duration_seconds", "Duration of http requests.", []float64{0.1, 1}, "route")
+		unlabeled = registry.Counter("purged_todos_total", "Total number of purged todos.\nIncludes \\ trash.")
+	)
+
+	requests.Inc("/todos/{ID}", "200")
+	requests.Add(2, "/todos/", "201")
+	requests.Inc("/todos/{ID}", "200")
+	up.Set(1, "database")
+	up.Set(0, `cache "primary"`)
+	duration.Observe(0.05, "/todos/")
+	duration.Observe(0.5, "/todos/")
+	duration.Observe(2, "/todos/")
+	unlabeled.Add(3)
+
+	n, err := registry.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, `# HELP http_requests_total Total number of http requests.
+# TYPE http_requests_total counter
+http_requests_total{route="/todos/",status="201"} 2
+http_requests_total{route="/todos/{ID}",status="200"} 2
+# HELP healthz_up Whether the service is up.
+# TYPE healthz_up gauge
+healthz_up{service="cache \"primary\""} 0
+healthz_up{service="database"} 1
+# HELP http_request_duration_seconds Duration of http requests.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{route="/todos/",le="0.1"} 1
+http_request_duration_seconds_bucket{route="/todos/",le="1"} 2
+http_request_duration_seconds_bucket{route="/todos/",le="+Inf"} 3
+http_request_duration_seconds_sum{route="/todos/"} 2.55
+http_request_duration_seconds_count{route="/todos/"} 3
+# HELP purged_todos_total Total number of purged todos.\nIncludes \\ trash.
+# TYPE purged_todos_total counter
+purged_todos_total 3
+`, buf.String())
+}
+
+func TestRegistry_duplicate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("http_requests_total", "Total number of http requests.")
+
+	assert.Panics(t, func() {
+		registry.Gauge("http_requests_total", "Total number of http requests.")
+	})
+}
+
+func TestCounter_invalid(t *testing.T) {
+	var (
+		registry = NewRegistry()
+		counter  = registry.Counter("http_requests_total", "Total number of http requests.", "route")
+	)
+
+	assert.Panics(t, func() {
+		counter.Add(-1, "/todos/")
+	})
+
+	assert.Panics(t, func() {
+		counter.Inc("/todos/", "200")
+	})
+}
+
+func TestGauge_Add(t *testing.T) {
+	var (
+		buf      bytes.Buffer
+		registry = NewRegistry()
+		points   = registry.Gauge("scores_points_earned", "Points earned.", "name")
+	)
+
+	points.Add(1, "todo completed")
+	points.Add(-2, "todo uncompleted")
+	points.Add(1, "todo completed")
+
+	registry.WriteTo(&buf)
+	assert.Equal(t, `# HELP scores_points_earned Points earned.
+# TYPE scores_points_earned gauge
+scores_points_earned{name="todo completed"} 2
+scores_points_earned{name="todo uncompleted"} -2
+`, buf.String())
+}
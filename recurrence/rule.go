@@ -0,0 +1,219 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRuleInvalid returned when rule can't be parsed.
+	ErrRuleInvalid = errors.New("recurrence: invalid rule")
+)
+
+// Frequency of a rule.
+type Frequency string
+
+const (
+	// Daily repeats every N days.
+	Daily Frequency = "DAILY"
+	// Weekly repeats every N weeks, optionally only on given weekdays.
+	Weekly Frequency = "WEEKLY"
+	// Monthly repeats every N months on given day of month.
+	Monthly Frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is a subset of RFC 5545 RRULE, for example:
+//
+//	FREQ=DAILY
+//	FREQ=DAILY;INTERVAL=3
+//	FREQ=WEEKLY;BYDAY=MO,WE,FR
+//	FREQ=MONTHLY;BYMONTHDAY=15
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	Weekdays  []time.Weekday
+	MonthDay  int
+}
+
+// Parse a rule from its string representation.
+func Parse(str string) (Rule, error) {
+	var (
+		rule = Rule{Interval: 1}
+	)
+
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(str)), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("%w: %q is not a key value pair", ErrRuleInvalid, part)
+		}
+
+		switch key {
+		case "FREQ":
+			rule.Frequency = Frequency(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Rule{}, fmt.Errorf("%w: interval must be a positive number", ErrRuleInvalid)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return Rule{}, fmt.Errorf("%w: unknown weekday %q", ErrRuleInvalid, day)
+				}
+				rule.Weekdays = append(rule.Weekdays, weekday)
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day < 1 || day > 31 {
+				return Rule{}, fmt.Errorf("%w: month day must be between 1 and 31", ErrRuleInvalid)
+			}
+			rule.MonthDay = day
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported key %q", ErrRuleInvalid, key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+
+	sort.Slice(rule.Weekdays, func(i, j int) bool { return rule.Weekdays[i] < rule.Weekdays[j] })
+	return rule, nil
+}
+
+// Validate rule.
+func (r Rule) Validate() error {
+	switch {
+	case r.Frequency != Daily && r.Frequency != Weekly && r.Frequency != Monthly:
+		return fmt.Errorf("%w: frequency must be one of DAILY, WEEKLY or MONTHLY", ErrRuleInvalid)
+	case r.Interval < 1:
+		return fmt.Errorf("%w: interval must be a positive number", ErrRuleInvalid)
+	case len(r.Weekdays) > 0 && r.Frequency != Weekly:
+		return fmt.Errorf("%w: BYDAY is only supported for WEEKLY frequency", ErrRuleInvalid)
+	case r.MonthDay != 0 && r.Frequency != Monthly:
+		return fmt.Errorf("%w: BYMONTHDAY is only supported for MONTHLY frequency", ErrRuleInvalid)
+	}
+
+	return nil
+}
+
+// String returns canonical representation of the rule.
+func (r Rule) String() string {
+	var (
+		parts = []string{"FREQ=" + string(r.Frequency)}
+	)
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, weekday := range r.Weekdays {
+			days[i] = strings.ToUpper(weekday.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after the given time, keeping its time of day.
+// The given time is treated as the start of the series, so intervals are counted from it.
+func (r Rule) Next(after time.Time) time.Time {
+	switch r.Frequency {
+	case Weekly:
+		return r.nextWeekly(after)
+	case Monthly:
+		return r.nextMonthly(after)
+	default:
+		return after.AddDate(0, 0, r.Interval)
+	}
+}
+
+func (r Rule) nextWeekly(after time.Time) time.Time {
+	if len(r.Weekdays) == 0 {
+		return after.AddDate(0, 0, 7*r.Interval)
+	}
+
+	var (
+		start = startOfWeek(after)
+	)
+
+	for i := 1; ; i++ {
+		var (
+			candidate = after.AddDate(0, 0, i)
+			weeks     = int(startOfWeek(candidate).Sub(start).Hours()+12) / (24 * 7)
+		)
+
+		if weeks%r.Interval == 0 && r.hasWeekday(candidate.Weekday()) {
+			return candidate
+		}
+	}
+}
+
+func (r Rule) nextMonthly(after time.Time) time.Time {
+	var (
+		day = r.MonthDay
+	)
+
+	if day == 0 {
+		day = after.Day()
+	}
+
+	for months := 0; ; months += r.Interval {
+		var (
+			first     = time.Date(after.Year(), after.Month()+time.Month(months), 1, after.Hour(), after.Minute(), after.Second(), after.Nanosecond(), after.Location())
+			lastDay   = first.AddDate(0, 1, -1).Day()
+			candidate = first.AddDate(0, 0, day-1)
+		)
+
+		// clamp to the last day for shorter month.
+		if day > lastDay {
+			candidate = first.AddDate(0, 0, lastDay-1)
+		}
+
+		if candidate.After(after) {
+			return candidate
+		}
+	}
+}
+
+func (r Rule) hasWeekday(weekday time.Weekday) bool {
+	for _, w := range r.Weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startOfWeek returns midnight of the monday of the given time's week.
+func startOfWeek(t time.Time) time.Time {
+	var (
+		offset = (int(t.Weekday()) + 6) % 7
+	)
+
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
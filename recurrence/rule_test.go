@@ -0,0 +1,230 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		rule Rule
+		err  bool
+	}{
+		{
+			name: "daily",
+			str:  "FREQ=DAILY",
+			rule: Rule{Frequency: Daily, Interval: 1},
+		},
+		{
+			name: "every 3 days",
+			str:  "freq=daily;interval=3",
+			rule: Rule{Frequency: Daily, Interval: 3},
+		},
+		{
+			name: "weekly on given weekdays",
+			str:  "FREQ=WEEKLY;BYDAY=FR,MO,WE",
+			rule: Rule{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}},
+		},
+		{
+			name: "monthly on day 15",
+			str:  "FREQ=MONTHLY;BYMONTHDAY=15",
+			rule: Rule{Frequency: Monthly, Interval: 1, MonthDay: 15},
+		},
+		{
+			name: "empty",
+			str:  "",
+			err:  true,
+		},
+		{
+			name: "unknown frequency",
+			str:  "FREQ=YEARLY",
+			err:  true,
+		},
+		{
+			name: "invalid interval",
+			str:  "FREQ=DAILY;INTERVAL=0",
+			err:  true,
+		},
+		{
+			name: "unknown weekday",
+			str:  "FREQ=WEEKLY;BYDAY=XX",
+			err:  true,
+		},
+		{
+			name: "weekday on daily",
+			str:  "FREQ=DAILY;BYDAY=MO",
+			err:  true,
+		},
+		{
+			name: "invalid month day",
+			str:  "FREQ=MONTHLY;BYMONTHDAY=32",
+			err:  true,
+		},
+		{
+			name: "month day on weekly",
+			str:  "FREQ=WEEKLY;BYMONTHDAY=1",
+			err:  true,
+		},
+		{
+			name: "unsupported key",
+			str:  "FREQ=DAILY;COUNT=3",
+			err:  true,
+		},
+		{
+			name: "malformed",
+			str:  "FREQ",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.str)
+			if test.err {
+				assert.ErrorIs(t, err, ErrRuleInvalid)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.rule, rule)
+			}
+		})
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		str  string
+	}{
+		{rule: Rule{Frequency: Daily, Interval: 1}, str: "FREQ=DAILY"},
+		{rule: Rule{Frequency: Daily, Interval: 3}, str: "FREQ=DAILY;INTERVAL=3"},
+		{rule: Rule{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Friday}}, str: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{rule: Rule{Frequency: Monthly, Interval: 1, MonthDay: 31}, str: "FREQ=MONTHLY;BYMONTHDAY=31"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.str, func(t *testing.T) {
+			assert.Equal(t, test.str, test.rule.String())
+
+			rule, err := Parse(test.str)
+			assert.Nil(t, err)
+			assert.Equal(t, test.rule, rule)
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	var (
+		// 2020-06-24 is a wednesday.
+		wednesday = time.Date(2020, 6, 24, 9, 30, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		next  time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			after: wednesday,
+			next:  time.Date(2020, 6, 25, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "every 3 days",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			after: wednesday,
+			next:  time.Date(2020, 6, 27, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "every 3 days across month",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			after: time.Date(2020, 6, 29, 9, 30, 0, 0, time.UTC),
+			next:  time.Date(2020, 7, 2, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekly",
+			rule:  "FREQ=WEEKLY",
+			after: wednesday,
+			next:  time.Date(2020, 7, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekly on later weekday in same week",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR",
+			after: wednesday,
+			next:  time.Date(2020, 6, 26, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekly on earlier weekday wraps to next week",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,TU",
+			after: wednesday,
+			next:  time.Date(2020, 6, 29, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekly on same weekday",
+			rule:  "FREQ=WEEKLY;BYDAY=WE",
+			after: wednesday,
+			next:  time.Date(2020, 7, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekly on sunday belongs to the same week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU",
+			after: wednesday,
+			next:  time.Date(2020, 6, 28, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "every 2 weeks skips the next week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			after: wednesday,
+			next:  time.Date(2020, 7, 6, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly later in same month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=28",
+			after: wednesday,
+			next:  time.Date(2020, 6, 28, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly earlier day goes to next month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15",
+			after: wednesday,
+			next:  time.Date(2020, 7, 15, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly clamps to last day of shorter month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			after: time.Date(2021, 1, 31, 9, 30, 0, 0, time.UTC),
+			next:  time.Date(2021, 2, 28, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly in leap year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=30",
+			after: time.Date(2020, 1, 30, 9, 30, 0, 0, time.UTC),
+			next:  time.Date(2020, 2, 29, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "every 3 months across year",
+			rule:  "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1",
+			after: time.Date(2020, 11, 1, 9, 30, 0, 0, time.UTC),
+			next:  time.Date(2021, 2, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly without day keeps day of month",
+			rule:  "FREQ=MONTHLY",
+			after: wednesday,
+			next:  time.Date(2020, 7, 24, 9, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.rule)
+			assert.Nil(t, err)
+			assert.Equal(t, test.next, rule.Next(test.after))
+		})
+	}
+}
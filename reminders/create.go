@@ -0,0 +1,43 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type create struct {
+	repository rel.Repository
+}
+
+// Create reminder of the todo for the user, it's scheduled by the current due date of the todo.
+// id is always assigned by the database, so reminder can't take over id of other reminder.
+func (c create) Create(ctx context.Context, reminder *Reminder, todo todos.Todo) error {
+	reminder.ID = 0
+	reminder.TodoID = todo.ID
+	reminder.UserID = users.FromContext(ctx)
+
+	if err := reminder.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	reminder.schedule(todo.DueAt)
+
+	if err := c.repository.Insert(ctx, reminder); err != nil {
+		var cerr rel.ConstraintError
+		if errors.As(err, &cerr) && cerr.Type == rel.UniqueConstraint && cerr.Key == "reminders_todo_id_user_id_offset_minutes" {
+			return ErrReminderOffsetTaken
+		}
+
+		return dberr.Classify(err)
+	}
+
+	return nil
+}
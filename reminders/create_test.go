@@ -0,0 +1,101 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		due        = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		remindAt   = time.Date(2020, 6, 28, 8, 0, 0, 0, time.UTC)
+		todo       = todos.Todo{ID: 2, UserID: 1, Title: "Sleep", DueAt: &due}
+		reminder   = Reminder{Offset: 60}
+	)
+
+	repository.ExpectInsert().For(&Reminder{TodoID: 2, UserID: 1, Offset: 60, DueAt: &due, RemindAt: &remindAt})
+
+	assert.Nil(t, service.Create(ctx, &reminder, todo))
+	assert.NotEmpty(t, reminder.ID)
+	assert.Equal(t, &remindAt, reminder.RemindAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_withoutDueDate(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		todo       = todos.Todo{ID: 2, UserID: 1, Title: "Sleep"}
+		reminder   = Reminder{Offset: 60}
+	)
+
+	repository.ExpectInsert().For(&Reminder{TodoID: 2, UserID: 1, Offset: 60})
+
+	assert.Nil(t, service.Create(ctx, &reminder, todo))
+	assert.Nil(t, reminder.RemindAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_validateError(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		todo       = todos.Todo{ID: 2, UserID: 1, Title: "Sleep"}
+		reminder   = Reminder{Offset: -5}
+	)
+
+	assert.Equal(t, validation.Errors{ErrReminderOffsetOutOfRange}, service.Create(ctx, &reminder, todo))
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_id(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		todo       = todos.Todo{ID: 2, UserID: 1, Title: "Sleep"}
+		reminder   = Reminder{ID: 5, Offset: 60}
+	)
+
+	// id from the client is dropped, and violation of other constraint isn't reported as taken offset.
+	repository.ExpectInsert().For(&Reminder{TodoID: 2, UserID: 1, Offset: 60}).Error(rel.ConstraintError{Key: "reminders_pkey", Type: rel.UniqueConstraint})
+
+	err := service.Create(ctx, &reminder, todo)
+	assert.True(t, errors.Is(err, dberr.ErrConflict))
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_offsetTaken(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		todo       = todos.Todo{ID: 2, UserID: 1, Title: "Sleep"}
+		reminder   = Reminder{Offset: 60}
+	)
+
+	repository.ExpectInsert().ForType("reminders.Reminder").Error(rel.ConstraintError{Key: "reminders_todo_id_user_id_offset_minutes", Type: rel.UniqueConstraint})
+
+	assert.Equal(t, ErrReminderOffsetTaken, service.Create(ctx, &reminder, todo))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package reminders
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type delete struct {
+	repository rel.Repository
+}
+
+// Delete reminder, notifications sent by it are deleted along with it by the foreign key cascade.
+func (d delete) Delete(ctx context.Context, reminder *Reminder) error {
+	return dberr.Classify(d.repository.Delete(ctx, reminder))
+}
@@ -0,0 +1,24 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelete(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		reminder   = Reminder{ID: 1, TodoID: 2, UserID: 1}
+	)
+
+	repository.ExpectDelete().For(&reminder)
+
+	assert.Nil(t, service.Delete(ctx, &reminder))
+
+	repository.AssertExpectations(t)
+}
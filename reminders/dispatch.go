@@ -0,0 +1,172 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+const (
+	// DispatchJob is name of the job that sends due reminders.
+	DispatchJob = "reminders.dispatch"
+	// dispatchBatchSize is maximum number of reminders sent by a single Dispatch.
+	dispatchBatchSize = 50
+)
+
+var (
+	now = time.Now
+)
+
+type dispatch struct {
+	repository rel.Repository
+	notifier   Notifier
+}
+
+// Dispatch sends due reminders, it returns number of reminders that are due whether they're sent or not.
+// notifications are recorded and reminders marked as sent before sending, so a reminder is never sent twice,
+// even when sending fails or multiple workers run at the same time.
+func (d dispatch) Dispatch(ctx context.Context) (int, error) {
+	var (
+		notifications []Notification
+	)
+
+	count, err := d.record(ctx, &notifications)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range notifications {
+		if err := d.notifier.Notify(ctx, notifications[i]); err != nil {
+			logger.Warn("notify error", zap.Error(err), zap.Uint("reminder_id", notifications[i].ReminderID), tracing.Field(ctx))
+
+			if err := d.repository.Update(ctx, &notifications[i], rel.Set("error", err.Error())); err != nil {
+				logger.Error("record notify error", zap.Error(err), zap.Uint("id", notifications[i].ID), tracing.Field(ctx))
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// record claims due reminders, and records notification of reminders whose todo is still pending.
+func (d dispatch) record(ctx context.Context, notifications *[]Notification) (int, error) {
+	var (
+		t         = now()
+		reminders []Reminder
+	)
+
+	err := d.repository.Transaction(ctx, func(ctx context.Context) error {
+		query := rel.Where(rel.Nil("sent_at"), rel.Lte("remind_at", t)).
+			SortAsc("remind_at").Limit(dispatchBatchSize).Lock("FOR UPDATE SKIP LOCKED")
+
+		if err := d.repository.FindAll(ctx, &reminders, query); err != nil {
+			return err
+		}
+
+		if len(reminders) == 0 {
+			return nil
+		}
+
+		var (
+			ids     = make([]interface{}, len(reminders))
+			todoIDs = make([]interface{}, len(reminders))
+			userIDs = make([]interface{}, len(reminders))
+		)
+
+		for i := range reminders {
+			ids[i] = reminders[i].ID
+			todoIDs[i] = reminders[i].TodoID
+			userIDs[i] = reminders[i].UserID
+		}
+
+		if _, err := d.repository.UpdateAny(ctx, rel.From("reminders").Where(rel.In("id", ids...)), rel.Set("sent_at", t)); err != nil {
+			return err
+		}
+
+		var (
+			pending    []todos.Todo
+			recipients []users.User
+		)
+
+		// trashed todos are excluded, so their reminders are skipped.
+		if err := d.repository.FindAll(ctx, &pending, rel.Where(rel.In("id", todoIDs...))); err != nil {
+			return err
+		}
+
+		if err := d.repository.FindAll(ctx, &recipients, rel.Where(rel.In("id", userIDs...))); err != nil {
+			return err
+		}
+
+		for _, reminder := range reminders {
+			todo, ok := findTodo(pending, reminder.TodoID)
+			if !ok || todo.Completed {
+				continue
+			}
+
+			notification := Notification{
+				ReminderID: reminder.ID,
+				TodoID:     reminder.TodoID,
+				UserID:     reminder.UserID,
+				Title:      todo.Title,
+				DueAt:      todo.DueAt,
+				RemindAt:   *reminder.RemindAt,
+				Email:      findEmail(recipients, reminder.UserID),
+			}
+
+			if err := d.insert(ctx, &notification); err != nil {
+				return err
+			}
+
+			if notification.ID != 0 {
+				*notifications = append(*notifications, notification)
+			}
+		}
+
+		return nil
+	})
+
+	return len(reminders), dberr.Classify(err)
+}
+
+// insert notification in a savepoint, notification that's already recorded for the same todo and time is skipped,
+// it happens when reminders of a todo are snoozed to the same time.
+func (d dispatch) insert(ctx context.Context, notification *Notification) error {
+	err := d.repository.Transaction(ctx, func(ctx context.Context) error {
+		return d.repository.Insert(ctx, notification)
+	})
+
+	var cerr rel.ConstraintError
+	if errors.As(err, &cerr) && cerr.Type == rel.UniqueConstraint {
+		notification.ID = 0
+		return nil
+	}
+
+	return err
+}
+
+func findTodo(result []todos.Todo, id uint) (todos.Todo, bool) {
+	for i := range result {
+		if result[i].ID == id {
+			return result[i], true
+		}
+	}
+
+	return todos.Todo{}, false
+}
+
+func findEmail(result []users.User, id uint) string {
+	for i := range result {
+		if result[i].ID == id {
+			return result[i].Email
+		}
+	}
+
+	return ""
+}
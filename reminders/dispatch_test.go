@@ -0,0 +1,147 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, notification Notification) error {
+	return errors.New("unavailable")
+}
+
+func TestDispatch(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		notifier   = NewLog()
+		service    = New(repository, notifier)
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		due        = today.Add(15 * time.Minute)
+		claim      = rel.Where(rel.Nil("sent_at"), rel.Lte("remind_at", today)).SortAsc("remind_at").Limit(50).Lock("FOR UPDATE SKIP LOCKED")
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(claim).Result([]Reminder{
+			{ID: 1, TodoID: 2, UserID: 1, Offset: 15, RemindAt: &today},
+			{ID: 2, TodoID: 3, UserID: 1, Offset: 15, RemindAt: &today},
+			{ID: 3, TodoID: 4, UserID: 1, Offset: 15, RemindAt: &today},
+			{ID: 4, TodoID: 2, UserID: 1, Offset: 30, RemindAt: &today},
+		})
+		repository.ExpectUpdateAny(rel.From("reminders").Where(rel.In("id", uint(1), uint(2), uint(3), uint(4))), rel.Set("sent_at", today)).UpdatedCount(4)
+		repository.ExpectFindAll(rel.Where(rel.In("id", uint(2), uint(3), uint(4), uint(2)))).Result([]todos.Todo{
+			{ID: 2, UserID: 1, Title: "Sleep", DueAt: &due},
+			{ID: 3, UserID: 1, Title: "Wake up", Completed: true},
+		})
+		repository.ExpectFindAll(rel.Where(rel.In("id", uint(1), uint(1), uint(1), uint(1)))).Result([]users.User{
+			{ID: 1, Email: "user@example.com"},
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectInsert().For(&Notification{ReminderID: 1, TodoID: 2, UserID: 1, Title: "Sleep", DueAt: &due, RemindAt: today, Email: "user@example.com"})
+		})
+		// the second reminder of the same todo is snoozed to the same time, it's already notified by the first one.
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectInsert().ForType("reminders.Notification").Error(rel.ConstraintError{Type: rel.UniqueConstraint})
+		})
+	})
+
+	count, err := service.Dispatch(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, count)
+
+	if assert.Len(t, notifier.Notifications(), 1) {
+		notification := notifier.Notifications()[0]
+		assert.Equal(t, uint(1), notification.ReminderID)
+		assert.Equal(t, "Sleep", notification.Title)
+		assert.Equal(t, "user@example.com", notification.Email)
+	}
+
+	repository.AssertExpectations(t)
+}
+
+func TestDispatch_notifyError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, failingNotifier{})
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		claim      = rel.Where(rel.Nil("sent_at"), rel.Lte("remind_at", today)).SortAsc("remind_at").Limit(50).Lock("FOR UPDATE SKIP LOCKED")
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(claim).Result([]Reminder{{ID: 1, TodoID: 2, UserID: 1, RemindAt: &today}})
+		repository.ExpectUpdateAny(rel.From("reminders").Where(rel.In("id", uint(1))), rel.Set("sent_at", today)).UpdatedCount(1)
+		repository.ExpectFindAll(rel.Where(rel.In("id", uint(2)))).Result([]todos.Todo{{ID: 2, UserID: 1, Title: "Sleep"}})
+		repository.ExpectFindAll(rel.Where(rel.In("id", uint(1)))).Result([]users.User{{ID: 1, Email: "user@example.com"}})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectInsert().ForType("reminders.Notification")
+		})
+	})
+	// failed notification isn't retried, the error is only recorded.
+	repository.ExpectUpdate(rel.Set("error", "unavailable")).ForType("reminders.Notification")
+
+	count, err := service.Dispatch(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDispatch_empty(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, NewLog())
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Nil("sent_at"), rel.Lte("remind_at", today)).SortAsc("remind_at").Limit(50).Lock("FOR UPDATE SKIP LOCKED")).Result([]Reminder{})
+	})
+
+	count, err := service.Dispatch(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDispatch_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, NewLog())
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Nil("sent_at"), rel.Lte("remind_at", today)).SortAsc("remind_at").Limit(50).Lock("FOR UPDATE SKIP LOCKED")).ConnectionClosed()
+	})
+
+	_, err := service.Dispatch(ctx)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,65 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"go.uber.org/zap"
+)
+
+// Notifier sends notification of a reminder to its user.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// Notifiers sends notification through every notifier, one failing notifier doesn't stop the others.
+type Notifiers []Notifier
+
+// Notify through every notifier, the returned error contains error of every failing notifier.
+func (n Notifiers) Notify(ctx context.Context, notification Notification) error {
+	var msgs []string
+
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// Log notifier only logs notifications and keeps them in memory, it's used when no other notifier is configured and in tests.
+type Log struct {
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+// Notify logs the notification.
+func (l *Log) Notify(ctx context.Context, notification Notification) error {
+	l.mu.Lock()
+	l.notifications = append(l.notifications, notification)
+	l.mu.Unlock()
+
+	logger.Info("reminder", zap.Uint("todo_id", notification.TodoID), zap.Uint("user_id", notification.UserID), zap.String("title", notification.Title), tracing.Field(ctx))
+	return nil
+}
+
+// Notifications returns notifications sent so far.
+func (l *Log) Notifications() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Notification(nil), l.notifications...)
+}
+
+// NewLog notifier.
+func NewLog() *Log {
+	return &Log{}
+}
@@ -0,0 +1,25 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifiers(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		log          = NewLog()
+		notification = Notification{ReminderID: 1, TodoID: 2, UserID: 1, Title: "Sleep"}
+	)
+
+	assert.Nil(t, Notifiers{log}.Notify(ctx, notification))
+	assert.Equal(t, []Notification{notification}, log.Notifications())
+
+	// failing notifier doesn't stop the others.
+	err := Notifiers{failingNotifier{}, log, failingNotifier{}}.Notify(ctx, notification)
+	assert.Equal(t, errors.New("unavailable; unavailable"), err)
+	assert.Equal(t, []Notification{notification, notification}, log.Notifications())
+}
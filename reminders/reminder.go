@@ -0,0 +1,79 @@
+package reminders
+
+import (
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrReminderOffsetOutOfRange validation error.
+	ErrReminderOffsetOutOfRange = validation.New("offset", "out_of_range", "Offset must be between 0 and 40320 minutes")
+	// ErrReminderOffsetTaken validation error.
+	ErrReminderOffsetTaken = validation.New("offset", "taken", "Todo already has a reminder with the same offset")
+	// ErrSnoozeUntilPast validation error.
+	ErrSnoozeUntilPast = validation.New("until", "past", "Snooze time must be in the future")
+
+	// MaxOffset of reminder in minutes, which is four weeks before the todo is due.
+	MaxOffset = 4 * 7 * 24 * 60
+)
+
+// Reminder respresent a record stored in reminders table.
+// it notifies its user offset minutes before the todo is due, reminder of todo without due date waits until the todo has one.
+type Reminder struct {
+	ID     uint `json:"id"`
+	TodoID uint `json:"todo_id"`
+	UserID uint `json:"-"`
+	// Offset is number of minutes before the todo is due.
+	Offset int `json:"offset" db:"offset_minutes"`
+	// DueAt of the todo when the reminder is scheduled, the reminder is scheduled again when the todo is due at another time.
+	DueAt     *time.Time `json:"-"`
+	RemindAt  *time.Time `json:"remind_at"`
+	SentAt    *time.Time `json:"sent_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate reminder.
+func (r Reminder) Validate() error {
+	var errs validation.Errors
+
+	if r.Offset < 0 || r.Offset > MaxOffset {
+		errs.Add(ErrReminderOffsetOutOfRange)
+	}
+
+	return errs.Err()
+}
+
+// schedule reminder for todo that is due at due, it's sent again even if it's been sent for the previous due date.
+func (r *Reminder) schedule(due *time.Time) {
+	r.DueAt = due
+	r.RemindAt = nil
+	r.SentAt = nil
+
+	if due != nil {
+		remindAt := due.Add(-time.Duration(r.Offset) * time.Minute)
+		r.RemindAt = &remindAt
+	}
+}
+
+// Notification of a reminder, it's recorded before it's sent, so a reminder is sent at most once even if sending fails.
+type Notification struct {
+	ID         uint       `json:"id"`
+	ReminderID uint       `json:"reminder_id"`
+	TodoID     uint       `json:"todo_id"`
+	UserID     uint       `json:"user_id"`
+	Title      string     `json:"title"`
+	DueAt      *time.Time `json:"due_at"`
+	RemindAt   time.Time  `json:"remind_at"`
+	// Email of the user, it's only available to notifiers.
+	Email     string    `json:"-" db:"-"`
+	Error     string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Table name of notification.
+func (Notification) Table() string {
+	return "reminder_notifications"
+}
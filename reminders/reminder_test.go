@@ -0,0 +1,57 @@
+package reminders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminder_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		reminder Reminder
+		err      error
+	}{
+		{
+			name:     "at due time",
+			reminder: Reminder{Offset: 0},
+		},
+		{
+			name:     "four weeks before",
+			reminder: Reminder{Offset: MaxOffset},
+		},
+		{
+			name:     "after due time",
+			reminder: Reminder{Offset: -1},
+			err:      validation.Errors{ErrReminderOffsetOutOfRange},
+		},
+		{
+			name:     "too early",
+			reminder: Reminder{Offset: MaxOffset + 1},
+			err:      validation.Errors{ErrReminderOffsetOutOfRange},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.reminder.Validate())
+		})
+	}
+}
+
+func TestReminder_schedule(t *testing.T) {
+	var (
+		due      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		remindAt = time.Date(2020, 6, 28, 8, 45, 0, 0, time.UTC)
+		sentAt   = time.Date(2020, 6, 27, 8, 45, 0, 0, time.UTC)
+		reminder = Reminder{Offset: 15, SentAt: &sentAt}
+	)
+
+	reminder.schedule(&due)
+	assert.Equal(t, Reminder{Offset: 15, DueAt: &due, RemindAt: &remindAt}, reminder)
+
+	reminder.schedule(nil)
+	assert.Equal(t, Reminder{Offset: 15}, reminder)
+}
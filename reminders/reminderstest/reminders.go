@@ -0,0 +1,58 @@
+package reminderstest
+
+import (
+	context "context"
+	time "time"
+
+	reminders "github.com/Fs02/go-todo-backend/reminders"
+	todos "github.com/Fs02/go-todo-backend/todos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock reminder functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockSearch util.
+func MockSearch(result []reminders.Reminder, todo todos.Todo, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Search", mock.Anything, mock.Anything, todo).
+			Return(func(ctx context.Context, out *[]reminders.Reminder, todo todos.Todo) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreate util.
+func MockCreate(result reminders.Reminder, todo todos.Todo, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Create", mock.Anything, mock.Anything, todo).
+			Return(func(ctx context.Context, out *reminders.Reminder, todo todos.Todo) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDelete util.
+func MockDelete(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Delete", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockSnooze util.
+func MockSnooze(todo todos.Todo, until time.Time, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Snooze", mock.Anything, todo, mock.MatchedBy(func(t time.Time) bool { return t.Equal(until) })).Return(err)
+	}
+}
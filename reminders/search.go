@@ -0,0 +1,21 @@
+package reminders
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+)
+
+type search struct {
+	repository rel.Repository
+}
+
+// Search reminders of the todo, reminders are personal so members of a list only see their own.
+func (s search) Search(ctx context.Context, reminders *[]Reminder, todo todos.Todo) error {
+	return dberr.Classify(s.repository.FindAll(ctx, reminders,
+		rel.Where(rel.Eq("todo_id", todo.ID), rel.Eq("user_id", users.FromContext(ctx))).SortDesc("offset_minutes"),
+	))
+}
@@ -0,0 +1,30 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		todo       = todos.Todo{ID: 2, UserID: 1}
+		result     []Reminder
+		reminders  = []Reminder{{ID: 1, TodoID: 2, UserID: 1, Offset: 60}, {ID: 2, TodoID: 2, UserID: 1, Offset: 15}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("todo_id", uint(2)), rel.Eq("user_id", uint(1))).SortDesc("offset_minutes")).Result(reminders)
+
+	assert.Nil(t, service.Search(ctx, &result, todo))
+	assert.Equal(t, reminders, result)
+
+	repository.AssertExpectations(t)
+}
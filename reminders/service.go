@@ -0,0 +1,55 @@
+package reminders
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "reminders")))
+)
+
+//go:generate mockery --name=Service --case=underscore --output reminderstest --outpkg reminderstest
+
+// Service instance for reminder's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Search(ctx context.Context, reminders *[]Reminder, todo todos.Todo) error
+	Create(ctx context.Context, reminder *Reminder, todo todos.Todo) error
+	Delete(ctx context.Context, reminder *Reminder) error
+	Snooze(ctx context.Context, todo todos.Todo, until time.Time) error
+	Store(ctx context.Context, events []events.Event) error
+	Dispatch(ctx context.Context) (int, error)
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	search
+	create
+	delete
+	snooze
+	store
+	dispatch
+}
+
+var _ Service = (*service)(nil)
+var _ events.Outbox = (*service)(nil)
+
+// New reminders service, it's also the outbox that reschedules reminders of todos whose due date changes.
+// notifier is only used by Dispatch, it can be nil when the service doesn't dispatch reminders.
+func New(repository rel.Repository, notifier Notifier) Service {
+	return service{
+		search:   search{repository: repository},
+		create:   create{repository: repository},
+		delete:   delete{repository: repository},
+		snooze:   snooze{repository: repository},
+		store:    store{repository: repository},
+		dispatch: dispatch{repository: repository, notifier: notifier},
+	}
+}
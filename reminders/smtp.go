@@ -0,0 +1,79 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+)
+
+// SMTP notifier sends notification by email to the user.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+	// sendMail is smtp.SendMail, overridable for testing.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Notify sends email to the user, user without email isn't notified.
+func (s SMTP) Notify(ctx context.Context, notification Notification) error {
+	_, span := tracing.Start(ctx, "reminders.SMTP")
+	defer span.End()
+
+	span.SetKind(tracing.KindClient)
+
+	if notification.Email == "" {
+		return span.Record(errors.New("reminders: user doesn't have email"))
+	}
+
+	return span.Record(s.sendMail(s.addr, s.auth, s.from, []string{notification.Email}, s.message(notification)))
+}
+
+// message of the notification, header values are sanitized so the title can't inject another header.
+func (s SMTP) message(notification Notification) []byte {
+	var (
+		buf     bytes.Buffer
+		title   = strings.NewReplacer("\r", " ", "\n", " ").Replace(notification.Title)
+		subject = mime.QEncoding.Encode("utf-8", "Reminder: "+title)
+	)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", notification.Email)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", now().UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+
+	if notification.DueAt != nil {
+		fmt.Fprintf(&buf, "%s is due at %s.\r\n", title, notification.DueAt.UTC().Format(time.RFC1123))
+	} else {
+		fmt.Fprintf(&buf, "%s\r\n", title)
+	}
+
+	return buf.Bytes()
+}
+
+// NewSMTP notifier that sends email through the server at addr as from, it authenticates with plain auth when username is given.
+func NewSMTP(addr string, username string, password string, from string) SMTP {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return SMTP{
+		addr:     addr,
+		auth:     auth,
+		from:     from,
+		sendMail: smtp.SendMail,
+	}
+}
@@ -0,0 +1,59 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTP(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		notifier     = NewSMTP("smtp.example.com:587", "user", "secret", "reminders@example.com")
+		today        = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		due          = today.Add(15 * time.Minute)
+		notification = Notification{ReminderID: 1, TodoID: 2, UserID: 1, Title: "Sleep\r\nBcc: attacker@example.com", DueAt: &due, Email: "user@example.com"}
+		sent         bool
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent = true
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, a)
+		assert.Equal(t, "reminders@example.com", from)
+		assert.Equal(t, []string{"user@example.com"}, to)
+		assert.Equal(t, "From: reminders@example.com\r\n"+
+			"To: user@example.com\r\n"+
+			"Subject: Reminder: Sleep  Bcc: attacker@example.com\r\n"+
+			"Date: Sun, 28 Jun 2020 09:00:00 +0000\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=utf-8\r\n"+
+			"\r\n"+
+			"Sleep  Bcc: attacker@example.com is due at Sun, 28 Jun 2020 09:15:00 UTC.\r\n", string(msg))
+		return nil
+	}
+
+	assert.Nil(t, notifier.Notify(ctx, notification))
+	assert.True(t, sent)
+}
+
+func TestSMTP_withoutEmail(t *testing.T) {
+	var (
+		ctx      = context.TODO()
+		notifier = NewSMTP("localhost:25", "", "", "reminders@example.com")
+	)
+
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		t.Error("email shouldn't be sent")
+		return nil
+	}
+
+	assert.Equal(t, errors.New("reminders: user doesn't have email"), notifier.Notify(ctx, Notification{Title: "Sleep"}))
+}
@@ -0,0 +1,34 @@
+package reminders
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type snooze struct {
+	repository rel.Repository
+}
+
+// Snooze reminders of the todo until the given time, every reminder of the user that is due before then,
+// including the ones already sent, is sent again at that time. they're sent as a single notification.
+func (s snooze) Snooze(ctx context.Context, todo todos.Todo, until time.Time) error {
+	if !until.After(now()) {
+		logger.Warn("validation error", zap.Error(ErrSnoozeUntilPast), tracing.Field(ctx))
+		return ErrSnoozeUntilPast
+	}
+
+	_, err := s.repository.UpdateAny(ctx,
+		rel.From("reminders").Where(rel.Eq("todo_id", todo.ID), rel.Eq("user_id", users.FromContext(ctx)), rel.Lt("remind_at", until)),
+		rel.Set("remind_at", until),
+		rel.Set("sent_at", nil),
+	)
+
+	return dberr.Classify(err)
+}
@@ -0,0 +1,54 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/todos"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnooze(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		until      = today.Add(10 * time.Minute)
+		todo       = todos.Todo{ID: 2, UserID: 1}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectUpdateAny(
+		rel.From("reminders").Where(rel.Eq("todo_id", uint(2)), rel.Eq("user_id", uint(1)), rel.Lt("remind_at", until)),
+		rel.Set("remind_at", until),
+		rel.Set("sent_at", nil),
+	).UpdatedCount(2)
+
+	assert.Nil(t, service.Snooze(ctx, todo, until))
+
+	repository.AssertExpectations(t)
+}
+
+func TestSnooze_past(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		today      = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		todo       = todos.Todo{ID: 2, UserID: 1}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	assert.Equal(t, ErrSnoozeUntilPast, service.Snooze(ctx, todo, today))
+
+	repository.AssertExpectations(t)
+}
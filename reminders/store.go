@@ -0,0 +1,87 @@
+package reminders
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type store struct {
+	repository rel.Repository
+}
+
+// Store reschedules reminders of created or updated todos whose due date changes, it's an outbox of events bus.
+// it runs in the transaction of the change, so reminders are never scheduled by a rolled back due date.
+func (s store) Store(ctx context.Context, evs []events.Event) error {
+	for _, event := range evs {
+		if event.Type != events.TodoCreated && event.Type != events.TodoUpdated {
+			continue
+		}
+
+		var todo struct {
+			ID    uint       `json:"id"`
+			DueAt *time.Time `json:"due_at"`
+		}
+
+		if err := json.Unmarshal(event.Data, &todo); err != nil {
+			logger.Error("decode event error", zap.Error(err), zap.String("event", string(event.Type)), tracing.Field(ctx))
+			continue
+		}
+
+		if err := s.reschedule(ctx, todo.ID, todo.DueAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s store) reschedule(ctx context.Context, todoID uint, due *time.Time) error {
+	var (
+		reminders []Reminder
+	)
+
+	if err := s.repository.FindAll(ctx, &reminders, rel.Where(rel.Eq("todo_id", todoID))); err != nil {
+		return dberr.Classify(err)
+	}
+
+	for i := range reminders {
+		if sameTime(reminders[i].DueAt, due) {
+			continue
+		}
+
+		reminders[i].schedule(due)
+		if err := s.repository.Update(ctx, &reminders[i],
+			rel.Set("due_at", nullable(reminders[i].DueAt)),
+			rel.Set("remind_at", nullable(reminders[i].RemindAt)),
+			rel.Set("sent_at", nil),
+		); err != nil {
+			return dberr.Classify(err)
+		}
+	}
+
+	return nil
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// nullable returns value of t, or untyped nil so rel sets the field to NULL.
+func nullable(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return *t
+}
@@ -0,0 +1,87 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		due        = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		previous   = time.Date(2020, 6, 27, 9, 0, 0, 0, time.UTC)
+		remindAt   = time.Date(2020, 6, 28, 8, 45, 0, 0, time.UTC)
+		evs        = []events.Event{
+			events.New(events.TodoUpdated, 1, nil, struct {
+				ID    uint       `json:"id"`
+				DueAt *time.Time `json:"due_at"`
+			}{ID: 2, DueAt: &due}),
+			events.New(events.TodoDeleted, 1, nil, struct {
+				ID uint `json:"id"`
+			}{ID: 3}),
+		}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("todo_id", uint(2)))).Result([]Reminder{
+		{ID: 1, TodoID: 2, UserID: 1, Offset: 15, DueAt: &previous},
+		{ID: 2, TodoID: 2, UserID: 1, Offset: 60, DueAt: &due},
+	})
+	repository.ExpectUpdate(rel.Set("due_at", due), rel.Set("remind_at", remindAt), rel.Set("sent_at", nil)).ForType("reminders.Reminder")
+
+	assert.Nil(t, service.Store(ctx, evs))
+
+	repository.AssertExpectations(t)
+}
+
+func TestStore_dueDateRemoved(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		previous   = time.Date(2020, 6, 27, 9, 0, 0, 0, time.UTC)
+		evs        = []events.Event{
+			events.New(events.TodoUpdated, 1, nil, struct {
+				ID uint `json:"id"`
+			}{ID: 2}),
+		}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("todo_id", uint(2)))).Result([]Reminder{
+		{ID: 1, TodoID: 2, UserID: 1, Offset: 15, DueAt: &previous},
+	})
+	repository.ExpectUpdate(rel.Set("due_at", nil), rel.Set("remind_at", nil), rel.Set("sent_at", nil)).ForType("reminders.Reminder")
+
+	assert.Nil(t, service.Store(ctx, evs))
+
+	repository.AssertExpectations(t)
+}
+
+func TestStore_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		evs        = []events.Event{
+			events.New(events.TodoCreated, 1, nil, struct {
+				ID uint `json:"id"`
+			}{ID: 2}),
+		}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("todo_id", uint(2)))).ConnectionClosed()
+
+	err := service.Store(ctx, evs)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,74 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/webhooks"
+)
+
+// Webhook notifier posts notification as json to a url, such as a chat or push notification service.
+// request is signed the same way as webhook deliveries when secret is given.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// Notify posts the notification, response other than 2xx is an error.
+func (wh Webhook) Notify(ctx context.Context, notification Notification) error {
+	ctx, span := tracing.Start(ctx, "reminders.Webhook")
+	defer span.End()
+
+	span.SetKind(tracing.KindClient)
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return span.Record(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.url, bytes.NewReader(body))
+	if err != nil {
+		return span.Record(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if wh.secret != "" {
+		timestamp := strconv.FormatInt(now().Unix(), 10)
+		req.Header.Set(webhooks.TimestampHeader, timestamp)
+		req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(wh.secret, timestamp, body))
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return span.Record(err)
+	}
+
+	// response body is drained so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode/100 != 2 {
+		return span.Record(fmt.Errorf("reminders: unexpected status %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// NewWebhook notifier that posts to url, secret is optional.
+func NewWebhook(url string, secret string) Webhook {
+	return Webhook{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
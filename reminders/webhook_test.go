@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/webhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		today        = time.Date(2020, 6, 28, 9, 0, 0, 0, time.UTC)
+		notification = Notification{ID: 1, ReminderID: 1, TodoID: 2, UserID: 1, Title: "Sleep", RemindAt: today, Email: "user@example.com"}
+		status       = http.StatusNoContent
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "1593334800", r.Header.Get(webhooks.TimestampHeader))
+		assert.Equal(t, webhooks.Sign("secret", "1593334800", body), r.Header.Get(webhooks.SignatureHeader))
+		assert.JSONEq(t, `{"id":1, "reminder_id":1, "todo_id":2, "user_id":1, "title":"Sleep", "due_at":null, "remind_at":"2020-06-28T09:00:00Z", "created_at":"0001-01-01T00:00:00Z"}`, string(body))
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhook(server.URL, "secret")
+	assert.Nil(t, notifier.Notify(ctx, notification))
+
+	status = http.StatusInternalServerError
+	assert.Equal(t, errors.New("reminders: unexpected status 500"), notifier.Notify(ctx, notification))
+}
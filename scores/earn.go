@@ -4,36 +4,65 @@ import (
 	"context"
 	"errors"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-rel/rel"
 )
 
+var (
+	pointsEarned = metrics.Default.Gauge("scores_points_earned", "Net points earned by name, uncompleting todo earns negative points.", "name")
+)
+
 type earn struct {
 	repository rel.Repository
+	bus        *events.Bus
+}
+
+// changed is data of score.changed event.
+type changed struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	TotalPoint int    `json:"total_point"`
 }
 
 func (e earn) Earn(ctx context.Context, name string, count int) error {
 	var (
-		score Score
+		userID = users.FromContext(ctx)
+		score  Score
 	)
 
-	return e.repository.Transaction(ctx, func(ctx context.Context) error {
-		// for simplicity, assumes only one user, so there's only one score and always retrieve the first one.
-		// this will probably lock the entire table since there's no where clause provided, but it's find since we assume only one user.
-		if err := e.repository.Find(ctx, &score, rel.ForUpdate()); err != nil {
+	err := events.Transaction(ctx, e.repository, func(ctx context.Context) error {
+		// each user has one score, lock it so concurrent earn of the same user doesn't lose any point.
+		if err := e.repository.Find(ctx, &score, rel.Eq("user_id", userID), rel.ForUpdate()); err != nil {
 			if !errors.Is(err, rel.ErrNotFound) {
 				// unexpected error.
 				return err
 			}
 
+			score.UserID = userID
 			score.TotalPoint = count
-			e.repository.MustInsert(ctx, &score)
+			if err := e.repository.Insert(ctx, &score); err != nil {
+				return err
+			}
 		} else {
 			score.TotalPoint += count
-			e.repository.Update(ctx, &score)
+			if err := e.repository.Update(ctx, &score); err != nil {
+				return err
+			}
 		}
 
 		// insert point history.
-		e.repository.MustInsert(ctx, &Point{Name: name, Count: count, ScoreID: score.ID})
-		return nil
+		if err := e.repository.Insert(ctx, &Point{UserID: userID, Name: name, Count: count, ScoreID: score.ID}); err != nil {
+			return err
+		}
+
+		// earn is usually part of the caller's transaction, so the points are only counted once the outermost transaction commits.
+		events.AfterCommit(ctx, func() { pointsEarned.Add(float64(count), name) })
+
+		return e.bus.Publish(ctx, events.New(events.ScoreChanged, userID, nil, changed{Name: name, Count: count, TotalPoint: score.TotalPoint}))
 	})
+
+	return dberr.Classify(err)
 }
@@ -1,9 +1,13 @@
 package scores
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/metrics"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
@@ -11,36 +15,40 @@ import (
 
 func TestEarn(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
-		service    = New(repository)
+		service    = New(repository, nil)
 		name       = "todo completed"
 		count      = 1
 	)
 
 	repository.ExpectTransaction(func(repository *reltest.Repository) {
-		repository.ExpectFind(rel.ForUpdate()).Result(Score{ID: 1, TotalPoint: 10})
+		repository.ExpectFind(rel.Eq("user_id", uint(1)), rel.ForUpdate()).Result(Score{ID: 1, TotalPoint: 10})
 		repository.ExpectUpdate().For(&Score{ID: 1, TotalPoint: 11})
-		repository.ExpectInsert().For(&Point{Name: name, Count: count, ScoreID: 1})
+		repository.ExpectInsert().For(&Point{UserID: 1, Name: name, Count: count, ScoreID: 1})
 	})
 
 	assert.Nil(t, service.Earn(ctx, name, count))
 	repository.AssertExpectations(t)
+
+	var buf bytes.Buffer
+	metrics.Default.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `scores_points_earned{name="todo completed"}`)
 }
 
 func TestEarn_insertScore(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
-		service    = New(repository)
+		service    = New(repository, nil)
 		name       = "todo completed"
 		count      = 1
 	)
 
 	repository.ExpectTransaction(func(repository *reltest.Repository) {
-		repository.ExpectFind(rel.ForUpdate()).NotFound()
-		repository.ExpectInsert().For(&Score{TotalPoint: 1})
-		repository.ExpectInsert().For(&Point{Name: name, Count: count, ScoreID: 1})
+		repository.ExpectFind(rel.Eq("user_id", uint(1)), rel.ForUpdate()).NotFound()
+		repository.ExpectInsert().For(&Score{UserID: 1, TotalPoint: 1})
+		repository.ExpectInsert().For(&Point{UserID: 1, Name: name, Count: count, ScoreID: 1})
 	})
 
 	assert.Nil(t, service.Earn(ctx, name, count))
@@ -49,18 +57,18 @@ func TestEarn_insertScore(t *testing.T) {
 
 func TestEarn_findError(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
-		service    = New(repository)
+		service    = New(repository, nil)
 		name       = "todo completed"
 		count      = 1
 	)
 
 	repository.ExpectTransaction(func(repository *reltest.Repository) {
-		repository.ExpectFind(rel.ForUpdate()).ConnectionClosed()
+		repository.ExpectFind(rel.Eq("user_id", uint(1)), rel.ForUpdate()).ConnectionClosed()
 	})
 
-	assert.Equal(t, reltest.ErrConnectionClosed, service.Earn(ctx, name, count))
+	assert.Equal(t, dberr.Classify(reltest.ErrConnectionClosed), service.Earn(ctx, name, count))
 
 	repository.AssertExpectations(t)
 }
@@ -7,6 +7,7 @@ import (
 // Point component for score.
 type Point struct {
 	ID        int       `json:"id"`
+	UserID    uint      `json:"-"`
 	Name      string    `json:"name"`
 	Count     int       `json:"count"`
 	ScoreID   int       `json:"score_id"`
@@ -7,6 +7,7 @@ import (
 // Score stores total points.
 type Score struct {
 	ID         int       `json:"id"`
+	UserID     uint      `json:"-"`
 	TotalPoint int       `json:"total_point"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
@@ -0,0 +1,31 @@
+package scorestest
+
+import (
+	context "context"
+
+	scores "github.com/Fs02/go-todo-backend/scores"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock score functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockSearchPoints util.
+func MockSearchPoints(result []scores.Point, filter scores.PointFilter, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchPoints", mock.Anything, mock.Anything, filter).
+			Return(func(ctx context.Context, out *[]scores.Point, filter scores.PointFilter) error {
+				*out = result
+				return err
+			})
+	}
+}
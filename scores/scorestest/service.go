@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	scores "github.com/Fs02/go-todo-backend/scores"
 )
 
 // Service is an autogenerated mock type for the Service type
@@ -26,3 +28,17 @@ func (_m *Service) Earn(ctx context.Context, name string, count int) error {
 
 	return r0
 }
+
+// SearchPoints provides a mock function with given fields: ctx, points, filter
+func (_m *Service) SearchPoints(ctx context.Context, points *[]scores.Point, filter scores.PointFilter) error {
+	ret := _m.Called(ctx, points, filter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]scores.Point, scores.PointFilter) error); ok {
+		r0 = rf(ctx, points, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,70 @@
+package scores
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/cursor"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+)
+
+var (
+	// ErrPointLimitInvalid validation error.
+	ErrPointLimitInvalid = validation.New("limit", "out_of_range", "Limit must be between 1 and 100")
+	// ErrPointCursorInvalid validation error.
+	ErrPointCursorInvalid = validation.New("after", "invalid", "Cursor is invalid")
+	// DefaultPointLimit is used when filter doesn't specify any limit.
+	DefaultPointLimit = 50
+	// MaxPointLimit of points in a single page.
+	MaxPointLimit = 100
+)
+
+// PointFilter for search points.
+type PointFilter struct {
+	// Limit the number of points returned, zero means default limit.
+	Limit int
+	// After is the cursor of the last point from previous page.
+	After string
+}
+
+// pointCursor is the position of the last point of a page.
+type pointCursor struct {
+	ID int `json:"id"`
+}
+
+// Cursor returns an opaque cursor pointing after the given point, to be used as After of the next page.
+func (f PointFilter) Cursor(last Point) string {
+	return cursor.Encode(pointCursor{ID: last.ID})
+}
+
+type searchPoints struct {
+	repository rel.Repository
+}
+
+// SearchPoints returns point history in the order it's earned.
+func (sp searchPoints) SearchPoints(ctx context.Context, points *[]Point, filter PointFilter) error {
+	var (
+		limit = filter.Limit
+		query = rel.Select().Where(rel.Eq("user_id", users.FromContext(ctx))).SortAsc("id")
+	)
+
+	if limit == 0 {
+		limit = DefaultPointLimit
+	}
+
+	if limit < 0 || limit > MaxPointLimit {
+		return ErrPointLimitInvalid
+	}
+
+	if filter.After != "" {
+		var page pointCursor
+		if err := cursor.Decode(filter.After, &page); err != nil {
+			return ErrPointCursorInvalid
+		}
+
+		query = query.Where(rel.Gt("id", page.ID))
+	}
+
+	return sp.repository.FindAll(ctx, points, query.Limit(limit))
+}
@@ -0,0 +1,86 @@
+package scores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPoints(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		points     []Point
+		result     = []Point{{ID: 1, Name: "todo completed", Count: 1}}
+	)
+
+	repository.ExpectFindAll(rel.Select().Where(rel.Eq("user_id", uint(1))).SortAsc("id").Limit(DefaultPointLimit)).Result(result)
+
+	assert.Nil(t, service.SearchPoints(ctx, &points, PointFilter{}))
+	assert.Equal(t, result, points)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearchPoints_after(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		points     []Point
+		filter     = PointFilter{Limit: 10}
+		result     = []Point{{ID: 6, Name: "todo completed", Count: 1}}
+	)
+
+	filter.After = filter.Cursor(Point{ID: 5})
+
+	repository.ExpectFindAll(rel.Select().Where(rel.Eq("user_id", uint(1))).SortAsc("id").Where(rel.Gt("id", 5)).Limit(10)).Result(result)
+
+	assert.Nil(t, service.SearchPoints(ctx, &points, filter))
+	assert.Equal(t, result, points)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearchPoints_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter PointFilter
+		err    error
+	}{
+		{
+			name:   "negative limit",
+			filter: PointFilter{Limit: -1},
+			err:    ErrPointLimitInvalid,
+		},
+		{
+			name:   "limit too large",
+			filter: PointFilter{Limit: MaxPointLimit + 1},
+			err:    ErrPointLimitInvalid,
+		},
+		{
+			name:   "invalid cursor",
+			filter: PointFilter{After: "invalid"},
+			err:    ErrPointCursorInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil)
+				points     []Point
+			)
+
+			assert.Equal(t, test.err, service.SearchPoints(ctx, &points, test.filter))
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package scores
 import (
 	"context"
 
+	"github.com/Fs02/go-todo-backend/events"
 	"github.com/go-rel/rel"
 )
 
@@ -12,19 +13,25 @@ import (
 // Any operation done to any of object within this domain should use this service.
 type Service interface {
 	Earn(ctx context.Context, name string, count int) error
+	SearchPoints(ctx context.Context, points *[]Point, filter PointFilter) error
 }
 
 // beside embeding the struct, you can also declare the function directly on this struct.
 // the advantage of embedding the struct is it allows spreading the implementation across multiple files.
 type service struct {
 	earn
+	searchPoints
 }
 
-var _ Service = (*service)(nil)
+var (
+	_ Service = (*service)(nil)
+	_ Service = (*traced)(nil)
+)
 
-// New Scores service.
-func New(repository rel.Repository) Service {
-	return service{
-		earn: earn{repository: repository},
-	}
+// New Scores service, every operation is traced and score changes are published to bus, bus can be nil.
+func New(repository rel.Repository, bus *events.Bus) Service {
+	return traced{service{
+		earn:         earn{repository: repository, bus: bus},
+		searchPoints: searchPoints{repository: repository},
+	}}
 }
@@ -0,0 +1,27 @@
+package scores
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+)
+
+// traced records earning points as a span, with name of the earned points as attribute.
+type traced struct {
+	Service
+}
+
+func (t traced) Earn(ctx context.Context, name string, count int) error {
+	ctx, span := tracing.Start(ctx, "scores.Earn")
+	defer span.End()
+
+	span.SetAttribute("name", name)
+	return span.Record(t.Service.Earn(ctx, name, count))
+}
+
+func (t traced) SearchPoints(ctx context.Context, points *[]Point, filter PointFilter) error {
+	ctx, span := tracing.Start(ctx, "scores.SearchPoints")
+	defer span.End()
+
+	return span.Record(t.Service.SearchPoints(ctx, points, filter))
+}
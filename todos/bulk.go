@@ -0,0 +1,239 @@
+package todos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// BulkAction of bulk operation.
+type BulkAction string
+
+const (
+	// BulkCreate creates a new todo.
+	BulkCreate BulkAction = "create"
+	// BulkUpdate updates todo by id.
+	BulkUpdate BulkAction = "update"
+	// BulkDelete moves todo to trash by id.
+	BulkDelete BulkAction = "delete"
+	// BulkComplete marks todo as completed by id.
+	BulkComplete BulkAction = "complete"
+	// BulkUncomplete marks todo as not completed by id.
+	BulkUncomplete BulkAction = "uncomplete"
+)
+
+var (
+	// ErrBulkOperationsInvalid validation error.
+	ErrBulkOperationsInvalid = validation.New("operations", "out_of_range", "Operations must contain between 1 and 100 operations")
+	// ErrBulkActionInvalid validation error.
+	ErrBulkActionInvalid = validation.New("action", "invalid", "Action must be one of create, update, delete, complete or uncomplete")
+	// ErrBulkTodoInvalid validation error.
+	ErrBulkTodoInvalid = validation.New("todo", "invalid", "Todo must be a valid todo object")
+	// ErrBulkTodoNotFound validation error.
+	ErrBulkTodoNotFound = validation.New("id", "not_found", "Todo not found")
+	// ErrBulkAborted returned when an operation of atomic bulk fails, the whole bulk is rolled back.
+	ErrBulkAborted = errors.New("Bulk is rolled back because one of the operations failed")
+	// ErrBulkRolledBack is the error of successful operations that are rolled back by an atomic bulk.
+	ErrBulkRolledBack = errors.New("Operation is rolled back because other operation failed")
+	// ErrBulkConflict is the error of operation that conflicts with data stored in database.
+	ErrBulkConflict = errors.New("Operation conflicts with existing data")
+	// ErrBulkFailed is the error of operation that fails for reason that isn't caused by the operation itself.
+	ErrBulkFailed = errors.New("Operation failed, try again later")
+	// MaxBulkOperations in a single bulk.
+	MaxBulkOperations = 100
+)
+
+// Valid returns true if action is known.
+func (ba BulkAction) Valid() bool {
+	switch ba {
+	case BulkCreate, BulkUpdate, BulkDelete, BulkComplete, BulkUncomplete:
+		return true
+	}
+
+	return false
+}
+
+// BulkOperation to be applied on a single todo.
+type BulkOperation struct {
+	Action BulkAction `json:"action"`
+	// ID of the todo, required by every action except create.
+	ID uint `json:"id,omitempty"`
+	// Todo fields to create or update, formatted just like request body of create and update.
+	Todo json.RawMessage `json:"todo,omitempty"`
+}
+
+// BulkResult of a single bulk operation.
+type BulkResult struct {
+	Action BulkAction `json:"action"`
+	ID     uint       `json:"id,omitempty"`
+	Todo   *Todo      `json:"todo,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type bulk struct {
+	repository rel.Repository
+	policy     Policy
+	create     create
+	update     update
+	delete     delete
+}
+
+// Bulk applies operations in a single transaction using the same path as single todo operations, so scores are earned the same way.
+// each operation runs in its own savepoint, when atomic is false a failed operation is rolled back alone and the rest is committed.
+func (b bulk) Bulk(ctx context.Context, results *[]BulkResult, operations []BulkOperation, atomic bool) error {
+	if len(operations) == 0 || len(operations) > MaxBulkOperations {
+		logger.Warn("validation error", zap.Error(ErrBulkOperationsInvalid), zap.Int("operations", len(operations)), tracing.Field(ctx))
+		return ErrBulkOperationsInvalid
+	}
+
+	for i := range operations {
+		if !operations[i].Action.Valid() {
+			logger.Warn("validation error", zap.Error(ErrBulkActionInvalid), zap.String("action", string(operations[i].Action)), tracing.Field(ctx))
+			return ErrBulkActionInvalid
+		}
+	}
+
+	*results = make([]BulkResult, len(operations))
+	for i := range operations {
+		(*results)[i] = BulkResult{Action: operations[i].Action, ID: operations[i].ID}
+	}
+
+	err := events.Transaction(ctx, b.repository, func(ctx context.Context) error {
+		for i := range operations {
+			var (
+				result = &(*results)[i]
+			)
+
+			err := events.Transaction(ctx, b.repository, func(ctx context.Context) error {
+				return b.apply(ctx, result, operations[i])
+			})
+
+			if err != nil {
+				result.Todo = nil
+				result.Error = bulkError(ctx, err)
+
+				if atomic {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil && atomic {
+		for i := range *results {
+			if result := &(*results)[i]; result.Error == "" {
+				result.Todo = nil
+				result.Error = ErrBulkRolledBack.Error()
+			}
+		}
+
+		logger.Warn("bulk aborted", zap.Error(err), tracing.Field(ctx))
+		return ErrBulkAborted
+	}
+
+	return dberr.Classify(err)
+}
+
+func (b bulk) apply(ctx context.Context, result *BulkResult, operation BulkOperation) error {
+	var (
+		todo Todo
+	)
+
+	if operation.Action == BulkCreate {
+		if err := decodeBulkTodo(ctx, operation.Todo, &todo); err != nil {
+			return err
+		}
+
+		if err := b.create.Create(ctx, &todo); err != nil {
+			return err
+		}
+
+		result.ID = todo.ID
+		result.Todo = &todo
+		return nil
+	}
+
+	if err := b.repository.Find(ctx, &todo, rel.Eq("id", operation.ID), rel.ForUpdate()); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrBulkTodoNotFound
+		}
+
+		return err
+	}
+
+	if err := b.policy.Authorize(ctx, todo, lists.ActionWrite); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrBulkTodoNotFound
+		}
+
+		return err
+	}
+
+	changes := rel.NewChangeset(&todo)
+
+	switch operation.Action {
+	case BulkDelete:
+		return b.delete.Delete(ctx, &todo)
+	case BulkUpdate:
+		if err := decodeBulkTodo(ctx, operation.Todo, &todo); err != nil {
+			return err
+		}
+	case BulkComplete:
+		todo.Completed = true
+	case BulkUncomplete:
+		todo.Completed = false
+	}
+
+	if err := b.update.Update(ctx, &todo, changes); err != nil {
+		return err
+	}
+
+	result.Todo = &todo
+	return nil
+}
+
+// bulkError returns message of failed operation that is shown to client, errors caused by the operation itself are shown as is.
+// anything else may contain sql or constraint names of the adapter, so it's logged and replaced by generic message.
+func bulkError(ctx context.Context, err error) string {
+	err = dberr.Classify(err)
+
+	switch {
+	case len(validation.Fields(err)) > 0, errors.Is(err, ErrTodoConflict), errors.Is(err, ErrTagNameTaken), errors.Is(err, lists.ErrForbidden):
+		return err.Error()
+	case errors.Is(err, dberr.ErrNotFound):
+		return ErrBulkTodoNotFound.Error()
+	case errors.Is(err, dberr.ErrConflict):
+		logger.Warn("bulk operation conflict", zap.Error(err), tracing.Field(ctx))
+		return ErrBulkConflict.Error()
+	}
+
+	logger.Error("bulk operation error", zap.Error(err), tracing.Field(ctx))
+	return ErrBulkFailed.Error()
+}
+
+func decodeBulkTodo(ctx context.Context, raw json.RawMessage, todo *Todo) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	if err := todo.Decode(raw); err != nil {
+		if errors.Is(err, ErrTodoPriorityInvalid) {
+			return err
+		}
+
+		logger.Warn("decode error", zap.Error(err), tracing.Field(ctx))
+		return ErrBulkTodoInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package todos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/scores/scorestest"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBulk(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		results    []BulkResult
+		operations = []BulkOperation{
+			{Action: BulkCreate, Todo: json.RawMessage(`{"title": "Run", "completed": true}`)},
+			{Action: BulkComplete, ID: 2},
+			{Action: BulkUncomplete, ID: 3},
+			{Action: BulkUpdate, ID: 4, Todo: json.RawMessage(`{"id": 10, "title": "Eat"}`)},
+			{Action: BulkDelete, ID: 5},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectInsert().For(&Todo{UserID: 1, Title: "Run", Completed: true})
+				scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil).Once()
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(2)), rel.ForUpdate()).Result(Todo{ID: 2, UserID: 1, Title: "Sleep"})
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectUpdate().ForType("todos.Todo")
+				scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil).Once()
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(3)), rel.ForUpdate()).Result(Todo{ID: 3, UserID: 1, Title: "Wake", Completed: true})
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectUpdate().ForType("todos.Todo")
+				scores.On("Earn", mock.Anything, "todo uncompleted", -2).Return(nil).Once()
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(4)), rel.ForUpdate()).Result(Todo{ID: 4, UserID: 1, Title: "Sleep"})
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectUpdate().ForType("todos.Todo")
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(5)), rel.ForUpdate()).NotFound()
+		})
+	})
+
+	assert.Nil(t, service.Bulk(ctx, &results, operations, false))
+	assert.Len(t, results, 5)
+	assert.Equal(t, uint(1), results[0].ID)
+	assert.Equal(t, "Run", results[0].Todo.Title)
+	assert.True(t, results[1].Todo.Completed)
+	assert.False(t, results[2].Todo.Completed)
+	assert.Equal(t, uint(4), results[3].Todo.ID)
+	assert.Equal(t, "Eat", results[3].Todo.Title)
+	assert.Equal(t, BulkResult{Action: BulkDelete, ID: 5, Error: ErrBulkTodoNotFound.Error()}, results[4])
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestBulk_errors(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		results    []BulkResult
+		operations = []BulkOperation{
+			{Action: BulkCreate, Todo: json.RawMessage(`{"title": "Run"}`)},
+			{Action: BulkComplete, ID: 2},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectInsert().For(&Todo{UserID: 1, Title: "Run"}).Error(rel.ConstraintError{Key: "todos_user_id_fkey", Type: rel.ForeignKeyConstraint})
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(2)), rel.ForUpdate()).Error(errors.New(`pq: relation "todos" does not exist`))
+		})
+	})
+
+	assert.Nil(t, service.Bulk(ctx, &results, operations, false))
+	assert.Equal(t, []BulkResult{
+		{Action: BulkCreate, Error: ErrBulkConflict.Error()},
+		{Action: BulkComplete, ID: 2, Error: ErrBulkFailed.Error()},
+	}, results)
+
+	repository.AssertExpectations(t)
+}
+
+func TestBulk_atomic(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		results    []BulkResult
+		operations = []BulkOperation{
+			{Action: BulkComplete, ID: 2},
+			{Action: BulkCreate, Todo: json.RawMessage(`{"title": ""}`)},
+			{Action: BulkDelete, ID: 3},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(2)), rel.ForUpdate()).Result(Todo{ID: 2, UserID: 1, Title: "Sleep"})
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectUpdate().ForType("todos.Todo")
+				scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil).Once()
+			})
+		})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {})
+	})
+
+	assert.Equal(t, ErrBulkAborted, service.Bulk(ctx, &results, operations, true))
+	assert.Equal(t, []BulkResult{
+		{Action: BulkComplete, ID: 2, Error: ErrBulkRolledBack.Error()},
+		{Action: BulkCreate, Error: ErrTodoTitleBlank.Error()},
+		{Action: BulkDelete, ID: 3, Error: ErrBulkRolledBack.Error()},
+	}, results)
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestBulk_invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []BulkOperation
+		err        error
+	}{
+		{
+			name:       "empty",
+			operations: nil,
+			err:        ErrBulkOperationsInvalid,
+		},
+		{
+			name:       "too many",
+			operations: make([]BulkOperation, MaxBulkOperations+1),
+			err:        ErrBulkOperationsInvalid,
+		},
+		{
+			name:       "invalid action",
+			operations: []BulkOperation{{Action: BulkCreate}, {Action: "archive", ID: 1}},
+			err:        ErrBulkActionInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil, nil)
+				results    []BulkResult
+			)
+
+			assert.Equal(t, test.err, service.Bulk(ctx, &results, test.operations, false))
+			assert.Nil(t, results)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBulk_todoInvalid(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		results    []BulkResult
+		operations = []BulkOperation{
+			{Action: BulkCreate, Todo: json.RawMessage(`{"title": "Run", "priority": "someday"}`)},
+			{Action: BulkUpdate, ID: 2, Todo: json.RawMessage(`{"title": 1}`)},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectTransaction(func(repository *reltest.Repository) {})
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			repository.ExpectFind(rel.Eq("id", uint(2)), rel.ForUpdate()).Result(Todo{ID: 2, UserID: 1, Title: "Sleep"})
+		})
+	})
+
+	assert.Nil(t, service.Bulk(ctx, &results, operations, false))
+	assert.Equal(t, []BulkResult{
+		{Action: BulkCreate, Error: ErrTodoPriorityInvalid.Error()},
+		{Action: BulkUpdate, ID: 2, Error: ErrBulkTodoInvalid.Error()},
+	}, results)
+
+	repository.AssertExpectations(t)
+}
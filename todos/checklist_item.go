@@ -0,0 +1,40 @@
+package todos
+
+import (
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrChecklistItemTitleBlank validation error.
+	ErrChecklistItemTitleBlank = validation.New("title", "blank", "Item title can't be blank")
+)
+
+// ChecklistItem respresent a record stored in checklist_items table, which is an ordered subtask of a todo.
+type ChecklistItem struct {
+	ID        uint      `json:"id"`
+	TodoID    uint      `json:"todo_id"`
+	Title     string    `json:"title"`
+	Order     int       `json:"order"`
+	Completed bool      `json:"completed"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checklist item.
+func (ci ChecklistItem) Validate() error {
+	var errs validation.Errors
+
+	if len(ci.Title) == 0 {
+		errs.Add(ErrChecklistItemTitleBlank)
+	}
+
+	return errs.Err()
+}
+
+// Progress summary of todo's checklist items.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
@@ -0,0 +1,21 @@
+package todos
+
+import (
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistItem_Validate(t *testing.T) {
+	var item ChecklistItem
+
+	t.Run("title is blank", func(t *testing.T) {
+		assert.Equal(t, validation.Errors{ErrChecklistItemTitleBlank}, item.Validate())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		item.Title = "Brush teeth"
+		assert.Nil(t, item.Validate())
+	})
+}
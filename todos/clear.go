@@ -3,13 +3,53 @@ package todos
 import (
 	"context"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-rel/rel"
 )
 
 type clear struct {
 	repository rel.Repository
+	bus        *events.Bus
 }
 
-func (c clear) Clear(ctx context.Context) {
-	c.repository.MustDeleteAny(ctx, rel.From("todos"))
+// Clear moves todos of the list in context to trash, or todos created by the user when there's no list in context.
+// cleared event is published for every list the cleared todos belong to, so members of each list are notified.
+func (c clear) Clear(ctx context.Context) error {
+	return events.Transaction(ctx, c.repository, func(ctx context.Context) error {
+		var (
+			todos []Todo
+			query = rel.Select("id", "user_id", "list_id").Where(clearable(ctx), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+		)
+
+		if err := c.repository.FindAll(ctx, &todos, query); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if len(todos) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, len(todos))
+		for i := range todos {
+			ids[i] = todos[i].ID
+		}
+
+		if _, err := c.repository.UpdateAny(ctx, rel.From("todos").Where(rel.In("id", ids...)), rel.Set("deleted_at", now())); err != nil {
+			return dberr.Classify(err)
+		}
+
+		return c.bus.Publish(ctx, clearedEvents(users.FromContext(ctx), todos)...)
+	})
+}
+
+// clearable filters todos that are cleared, todos in shared lists that are created by other members are only cleared from the list itself.
+func clearable(ctx context.Context) rel.FilterQuery {
+	if _, ok := lists.FromContext(ctx); ok {
+		return accessible(ctx, lists.ActionWrite)
+	}
+
+	return rel.And(rel.Eq("user_id", users.FromContext(ctx)), accessible(ctx, lists.ActionWrite))
 }
@@ -2,8 +2,13 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
@@ -11,16 +16,68 @@ import (
 
 func TestClear(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		listID     = uint(3)
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
-		service    = New(repository, nil)
+		bus        = events.NewBus(events.DefaultBufferSize)
+		service    = New(repository, nil, bus)
+		sub, _, _  = bus.Subscribe(func(events.Event) bool { return true }, 0)
+		query      = rel.Select("id", "user_id", "list_id").Where(rel.And(rel.Eq("user_id", uint(1)), accessible(ctx, lists.ActionWrite)), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
 	)
 
-	repository.ExpectDeleteAny(rel.From("todos")).Unsafe()
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{{ID: 1, UserID: 1}, {ID: 2, UserID: 1, ListID: &listID}, {ID: 4, UserID: 1}})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.In("id", uint(1), uint(2), uint(4))), rel.Set("deleted_at", today)).UpdatedCount(3)
+	})
+
+	assert.Nil(t, service.Clear(ctx))
+
+	event := <-sub.Events()
+	assert.Equal(t, events.TodosCleared, event.Type)
+	assert.Nil(t, event.ListID)
+	assert.JSONEq(t, `{"ids":[1,4]}`, string(event.Data))
+
+	event = <-sub.Events()
+	assert.Equal(t, events.TodosCleared, event.Type)
+	assert.Equal(t, &listID, event.ListID)
+	assert.JSONEq(t, `{"ids":[2]}`, string(event.Data))
+
+	repository.AssertExpectations(t)
+}
+
+func TestClear_list(t *testing.T) {
+	var (
+		ctx        = lists.NewContext(users.NewContext(context.TODO(), 1), lists.List{ID: 2})
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		query      = rel.Select("id", "user_id", "list_id").Where(accessible(ctx, lists.ActionWrite), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+	)
 
-	assert.NotPanics(t, func() {
-		service.Clear(ctx)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{})
 	})
 
+	assert.Nil(t, service.Clear(ctx))
+
+	repository.AssertExpectations(t)
+}
+
+func TestClear_error(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		query      = rel.Select("id", "user_id", "list_id").Where(rel.And(rel.Eq("user_id", uint(1)), accessible(ctx, lists.ActionWrite)), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{{ID: 1, UserID: 1}})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.In("id", uint(1))), rel.Set("deleted_at", today)).ConnectionClosed()
+	})
+
+	err := service.Clear(ctx)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+	assert.True(t, errors.Is(err, reltest.ErrConnectionClosed))
+
 	repository.AssertExpectations(t)
 }
@@ -3,7 +3,12 @@ package todos
 import (
 	"context"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
 	"github.com/Fs02/go-todo-backend/scores"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
 	"github.com/go-rel/rel"
 	"go.uber.org/zap"
 )
@@ -11,22 +16,41 @@ import (
 type create struct {
 	repository rel.Repository
 	scores     scores.Service
+	policy     Policy
+	bus        *events.Bus
 }
 
 func (c create) Create(ctx context.Context, todo *Todo) error {
+	todo.UserID = users.FromContext(ctx)
+
+	// todo created in a list scoped request belongs to that list.
+	if list, ok := lists.FromContext(ctx); ok {
+		todo.ListID = &list.ID
+	}
+
 	if err := todo.Validate(); err != nil {
-		logger.Warn("validation error", zap.Error(err))
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
 		return err
 	}
 
-	// if completed, then earn a point.
-	if todo.Completed {
-		return c.repository.Transaction(ctx, func(ctx context.Context) error {
-			c.repository.MustInsert(ctx, todo)
-			return c.scores.Earn(ctx, "todo completed", 1)
-		})
+	if err := c.policy.authorizeList(ctx, *todo); err != nil {
+		return err
 	}
 
-	c.repository.MustInsert(ctx, todo)
-	return nil
+	return events.Transaction(ctx, c.repository, func(ctx context.Context) error {
+		if err := c.repository.Insert(ctx, todo); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if err := c.bus.Publish(ctx, todoEvent(events.TodoCreated, *todo)); err != nil {
+			return err
+		}
+
+		// if completed, then earn a point.
+		if todo.Completed {
+			return c.scores.Earn(ctx, "todo completed", 1)
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,38 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type createItem struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+func (ci createItem) CreateItem(ctx context.Context, todo *Todo, item *ChecklistItem) error {
+	item.TodoID = todo.ID
+
+	if err := item.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	return events.Transaction(ctx, ci.repository, func(ctx context.Context) error {
+		if err := ci.repository.Insert(ctx, item); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if err := touch(ctx, ci.repository, todo); err != nil {
+			return err
+		}
+
+		todo.Items = append(todo.Items, *item)
+		return ci.bus.Publish(ctx, todoEvent(events.TodoUpdated, *todo))
+	})
+}
@@ -0,0 +1,47 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateItem(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+		item       = ChecklistItem{Title: "Brush teeth"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().For(&ChecklistItem{TodoID: 1, Title: "Brush teeth"})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.CreateItem(ctx, &todo, &item))
+	assert.NotEmpty(t, item.ID)
+	assert.Equal(t, &Progress{Done: 0, Total: 1}, todo.Progress())
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateItem_validateError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+		item       = ChecklistItem{}
+	)
+
+	assert.Equal(t, validation.Errors{ErrChecklistItemTitleBlank}, service.CreateItem(ctx, &todo, &item))
+	assert.Nil(t, todo.Progress())
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package todos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type createTag struct {
+	repository rel.Repository
+}
+
+func (ct createTag) CreateTag(ctx context.Context, tag *Tag) error {
+	tag.UserID = users.FromContext(ctx)
+
+	if err := tag.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	if err := ct.repository.Insert(ctx, tag); err != nil {
+		var cerr rel.ConstraintError
+		if errors.As(err, &cerr) && cerr.Type == rel.UniqueConstraint {
+			return ErrTagNameTaken
+		}
+
+		return dberr.Classify(err)
+	}
+
+	return nil
+}
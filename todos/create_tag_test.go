@@ -0,0 +1,55 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTag(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		tag        = Tag{Name: "work"}
+	)
+
+	repository.ExpectInsert().For(&tag)
+
+	assert.Nil(t, service.CreateTag(ctx, &tag))
+	assert.NotEmpty(t, tag.ID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTag_validateError(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		tag        = Tag{}
+	)
+
+	assert.Equal(t, validation.Errors{ErrTagNameBlank}, service.CreateTag(ctx, &tag))
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTag_nameTaken(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		tag        = Tag{Name: "work"}
+	)
+
+	repository.ExpectInsert().For(&tag).NotUnique("tags_user_id_name")
+
+	assert.Equal(t, ErrTagNameTaken, service.CreateTag(ctx, &tag))
+
+	repository.AssertExpectations(t)
+}
@@ -2,9 +2,16 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/lists"
 	"github.com/Fs02/go-todo-backend/scores/scorestest"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -12,17 +19,20 @@ import (
 
 func TestCreate(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{Title: "Sleep"}
 	)
 
-	repository.ExpectInsert().For(&todo)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().For(&todo)
+	})
 
 	assert.Nil(t, service.Create(ctx, &todo))
 	assert.NotEmpty(t, todo.ID)
+	assert.Equal(t, uint(1), todo.UserID)
 
 	repository.AssertExpectations(t)
 	scores.AssertExpectations(t)
@@ -33,7 +43,7 @@ func TestCreate_completed(t *testing.T) {
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{Title: "Sleep", Completed: true}
 	)
 
@@ -49,16 +59,138 @@ func TestCreate_completed(t *testing.T) {
 	scores.AssertExpectations(t)
 }
 
+func TestCreate_publish(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		bus        = events.NewBus(events.DefaultBufferSize)
+		service    = New(repository, scores, bus)
+		todo       = Todo{Title: "Sleep", Completed: true}
+		sub, _, _  = bus.Subscribe(func(events.Event) bool { return true }, 0)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil).Run(func(mock.Arguments) {
+			// held until the transaction commits.
+			assert.Len(t, sub.Events(), 0)
+		})
+		repository.ExpectInsert().For(&todo)
+	})
+
+	assert.Nil(t, service.Create(ctx, &todo))
+
+	event := <-sub.Events()
+	assert.Equal(t, events.TodoCreated, event.Type)
+	assert.Equal(t, uint(1), event.UserID)
+	assert.Contains(t, string(event.Data), `"title":"Sleep"`)
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
 func TestCreate_validateError(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{Title: ""}
 	)
 
-	assert.Equal(t, ErrTodoTitleBlank, service.Create(ctx, &todo))
+	assert.Equal(t, validation.Errors{ErrTodoTitleBlank}, service.Create(ctx, &todo))
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestCreate_list(t *testing.T) {
+	var (
+		listID = uint(1)
+	)
+
+	tests := []struct {
+		name     string
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name: "editor",
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleEditor})
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectInsert().ForType("todos.Todo")
+				})
+			},
+		},
+		{
+			name: "viewer",
+			err:  lists.ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleViewer})
+			},
+		},
+		{
+			name: "not member",
+			err:  ErrTodoListNotFound,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil, nil)
+				todo       = Todo{Title: "Sleep", ListID: &listID}
+			)
+
+			test.mockRepo(repository)
+
+			assert.Equal(t, test.err, service.Create(ctx, &todo))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreate_scopedList(t *testing.T) {
+	var (
+		ctx        = lists.NewContext(users.NewContext(context.TODO(), 1), lists.List{ID: 2})
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		listID     = uint(1)
+		todo       = Todo{Title: "Sleep", ListID: &listID}
+	)
+
+	repository.ExpectFind(rel.Eq("list_id", uint(2)), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 2, UserID: 1, Role: lists.RoleOwner})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("todos.Todo")
+	})
+
+	assert.Nil(t, service.Create(ctx, &todo))
+	assert.Equal(t, uint(2), *todo.ListID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_error(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		todo       = Todo{Title: "Sleep"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().For(&todo).Error(rel.ConstraintError{Key: "todos_list_id_fkey", Type: rel.ForeignKeyConstraint})
+	})
+
+	assert.True(t, errors.Is(service.Create(ctx, &todo), dberr.ErrConflict))
 
 	repository.AssertExpectations(t)
 	scores.AssertExpectations(t)
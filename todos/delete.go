@@ -2,14 +2,33 @@ package todos
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
 	"github.com/go-rel/rel"
+	"go.uber.org/zap"
 )
 
 type delete struct {
 	repository rel.Repository
+	bus        *events.Bus
 }
 
-func (d delete) Delete(ctx context.Context, todo *Todo) {
-	d.repository.MustDelete(ctx, todo)
+// Delete moves todo to trash, rel soft deletes the todo by setting deleted_at because Todo has DeletedAt field.
+// Todo is only deleted if it's still at the version it's loaded.
+func (d delete) Delete(ctx context.Context, todo *Todo) error {
+	return events.Transaction(ctx, d.repository, func(ctx context.Context) error {
+		if err := d.repository.Delete(ctx, todo); err != nil {
+			if errors.Is(err, rel.ErrNotFound) {
+				logger.Warn("conflict error", zap.Error(ErrTodoConflict), zap.Uint("id", todo.ID), zap.Int("version", todo.LockVersion), tracing.Field(ctx))
+				return ErrTodoConflict
+			}
+
+			return dberr.Classify(err)
+		}
+
+		return d.bus.Publish(ctx, deletedEvent(*todo))
+	})
 }
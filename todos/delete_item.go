@@ -0,0 +1,35 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/go-rel/rel"
+)
+
+type deleteItem struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+func (di deleteItem) DeleteItem(ctx context.Context, todo *Todo, item *ChecklistItem) error {
+	return events.Transaction(ctx, di.repository, func(ctx context.Context) error {
+		if err := di.repository.Delete(ctx, item); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if err := touch(ctx, di.repository, todo); err != nil {
+			return err
+		}
+
+		for i := range todo.Items {
+			if todo.Items[i].ID == item.ID {
+				todo.Items = append(todo.Items[:i], todo.Items[i+1:]...)
+				break
+			}
+		}
+
+		return di.bus.Publish(ctx, todoEvent(events.TodoUpdated, *todo))
+	})
+}
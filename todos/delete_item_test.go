@@ -0,0 +1,30 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteItem(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		item       = ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"}
+		todo       = Todo{ID: 1, Title: "Sleep", Items: []ChecklistItem{item}}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDelete().ForType("todos.ChecklistItem")
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.DeleteItem(ctx, &todo, &item))
+	assert.Empty(t, todo.Items)
+
+	repository.AssertExpectations(t)
+}
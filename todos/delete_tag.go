@@ -0,0 +1,17 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type deleteTag struct {
+	repository rel.Repository
+}
+
+// DeleteTag deletes the tag, links to todos are removed by the foreign key cascade.
+func (dt deleteTag) DeleteTag(ctx context.Context, tag *Tag) error {
+	return dberr.Classify(dt.repository.Delete(ctx, tag))
+}
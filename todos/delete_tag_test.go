@@ -0,0 +1,24 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteTag(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		tag        = Tag{ID: 1, Name: "work"}
+	)
+
+	repository.ExpectDelete().ForType("todos.Tag")
+
+	assert.Nil(t, service.DeleteTag(ctx, &tag))
+
+	repository.AssertExpectations(t)
+}
@@ -2,8 +2,11 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
 )
@@ -12,15 +15,51 @@ func TestDelete(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository, nil)
+		service    = New(repository, nil, nil)
 		todo       = Todo{ID: 1, Title: "Sleep"}
 	)
 
-	repository.ExpectDelete().ForType("todos.Todo")
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDelete().ForType("todos.Todo")
+	})
+
+	assert.Nil(t, service.Delete(ctx, &todo))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDelete_conflict(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", LockVersion: 2}
+	)
 
-	assert.NotPanics(t, func() {
-		service.Delete(ctx, &todo)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDelete().ForType("todos.Todo").Error(rel.NotFoundError{})
 	})
 
+	assert.Equal(t, ErrTodoConflict, service.Delete(ctx, &todo))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDelete_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDelete().ForType("todos.Todo").ConnectionClosed()
+	})
+
+	err := service.Delete(ctx, &todo)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+	assert.True(t, errors.Is(err, reltest.ErrConnectionClosed))
+
 	repository.AssertExpectations(t)
 }
@@ -0,0 +1,87 @@
+package todos
+
+import (
+	"github.com/Fs02/go-todo-backend/events"
+)
+
+// todoEvent of a change to the todo, it's delivered to members of the todo's list, or its owner when it doesn't belong to any list.
+func todoEvent(typ events.Type, todo Todo) events.Event {
+	return events.New(typ, todo.UserID, todo.ListID, todo)
+}
+
+// deletedEvent only carries id of the deleted todo.
+func deletedEvent(todo Todo) events.Event {
+	return events.New(events.TodoDeleted, todo.UserID, todo.ListID, struct {
+		ID uint `json:"id"`
+	}{
+		ID: todo.ID,
+	})
+}
+
+// clearedEvents groups cleared todos by their list, each event carries ids of the todos that are cleared from the list.
+func clearedEvents(userID uint, todos []Todo) []events.Event {
+	type cleared struct {
+		ListID *uint  `json:"-"`
+		IDs    []uint `json:"ids"`
+	}
+
+	var (
+		groups []cleared
+		index  = make(map[uint]int)
+	)
+
+	for _, todo := range todos {
+		var key uint
+		if todo.ListID != nil {
+			key = *todo.ListID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, cleared{ListID: todo.ListID})
+		}
+
+		groups[i].IDs = append(groups[i].IDs, todo.ID)
+	}
+
+	evs := make([]events.Event, len(groups))
+	for i, group := range groups {
+		evs[i] = events.New(events.TodosCleared, userID, group.ListID, group)
+	}
+
+	return evs
+}
+
+// detachedEvents groups todos that are trashed along with their list by the creator, each event carries ids of the creator's todos,
+// the todos no longer belong to any list so the event is only sent to the creator.
+func detachedEvents(todos []Todo) []events.Event {
+	type cleared struct {
+		UserID uint   `json:"-"`
+		IDs    []uint `json:"ids"`
+	}
+
+	var (
+		groups []cleared
+		index  = make(map[uint]int)
+	)
+
+	for _, todo := range todos {
+		i, ok := index[todo.UserID]
+		if !ok {
+			i = len(groups)
+			index[todo.UserID] = i
+			groups = append(groups, cleared{UserID: todo.UserID})
+		}
+
+		groups[i].IDs = append(groups[i].IDs, todo.ID)
+	}
+
+	evs := make([]events.Event, len(groups))
+	for i, group := range groups {
+		evs[i] = events.New(events.TodosCleared, group.UserID, nil, group)
+	}
+
+	return evs
+}
@@ -0,0 +1,219 @@
+package todos
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTodoPositionInvalid validation error.
+	ErrTodoPositionInvalid = validation.New("position", "invalid", "Position must refer to other todos, and before must come after the after todo")
+	// ErrTodoNeighbourNotFound validation error.
+	ErrTodoNeighbourNotFound = validation.New("position", "not_found", "Neighbour todo not found")
+	// OrderGap between order of adjacent todos after rebalancing, leaving room to move todos in between.
+	OrderGap = 1024
+)
+
+// Position of moved todo relative to its new neighbours, at least one of them must be set.
+type Position struct {
+	// Before is id of the todo that will come right after the moved todo.
+	Before *uint `json:"before"`
+	// After is id of the todo that will come right before the moved todo.
+	After *uint `json:"after"`
+}
+
+func (p Position) validate(todo Todo) error {
+	var err error
+	switch {
+	case p.Before == nil && p.After == nil:
+		err = ErrTodoPositionInvalid
+	case p.Before != nil && *p.Before == todo.ID:
+		err = ErrTodoPositionInvalid
+	case p.After != nil && *p.After == todo.ID:
+		err = ErrTodoPositionInvalid
+	}
+
+	return err
+}
+
+type move struct {
+	repository rel.Repository
+	update     update
+}
+
+// Move todo between its new neighbours, its new order is taken from the middle of the gap between the neighbours.
+// when there's no gap left, the order of every todo is renumbered to restore the gaps.
+func (m move) Move(ctx context.Context, todo *Todo, position Position) error {
+	if err := position.validate(*todo); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	return events.Transaction(ctx, m.repository, func(ctx context.Context) error {
+		prev, next, err := m.neighbours(ctx, *todo, position)
+		if err != nil {
+			return err
+		}
+
+		lower, upper := bounds(prev, next)
+		if upper-lower < 2 {
+			orders, err := m.rebalance(ctx, *todo)
+			if err != nil {
+				return err
+			}
+
+			if prev != nil {
+				prev.Order = orders[prev.ID]
+			}
+
+			if next != nil {
+				next.Order = orders[next.ID]
+			}
+
+			lower, upper = bounds(prev, next)
+		}
+
+		changes := rel.NewChangeset(todo)
+		todo.Order = lower + (upper-lower)/2
+
+		return m.update.save(ctx, todo, changes)
+	})
+}
+
+// neighbours locks and returns todos right before and after the new position, nil when the position is at the start or end of the list.
+func (m move) neighbours(ctx context.Context, todo Todo, position Position) (*Todo, *Todo, error) {
+	var (
+		prev, next *Todo
+		err        error
+	)
+
+	if position.After != nil {
+		if prev, err = m.find(ctx, rel.Eq("id", *position.After), siblings(todo), rel.ForUpdate()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if position.Before != nil {
+		if next, err = m.find(ctx, rel.Eq("id", *position.Before), siblings(todo), rel.ForUpdate()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case prev != nil && next != nil:
+		if !sortedBefore(*prev, *next) {
+			logger.Warn("validation error", zap.Error(ErrTodoPositionInvalid), tracing.Field(ctx))
+			return nil, nil, ErrTodoPositionInvalid
+		}
+	case prev != nil:
+		next, err = m.find(ctx, siblings(todo), rel.Ne("id", todo.ID), rel.Or(
+			rel.Gt("order", prev.Order),
+			rel.And(rel.Eq("order", prev.Order), rel.Gt("id", prev.ID)),
+		), rel.SortAsc("order"), rel.SortAsc("id"), rel.ForUpdate())
+	case next != nil:
+		prev, err = m.find(ctx, siblings(todo), rel.Ne("id", todo.ID), rel.Or(
+			rel.Lt("order", next.Order),
+			rel.And(rel.Eq("order", next.Order), rel.Lt("id", next.ID)),
+		), rel.SortDesc("order"), rel.SortDesc("id"), rel.ForUpdate())
+	}
+
+	// neighbour that is looked up instead of specified is allowed to be missing.
+	if errors.Is(err, ErrTodoNeighbourNotFound) {
+		err = nil
+	}
+
+	return prev, next, err
+}
+
+func (m move) find(ctx context.Context, queriers ...rel.Querier) (*Todo, error) {
+	var (
+		todo Todo
+	)
+
+	if err := m.repository.Find(ctx, &todo, queriers...); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return nil, ErrTodoNeighbourNotFound
+		}
+
+		return nil, dberr.Classify(err)
+	}
+
+	return &todo, nil
+}
+
+// rebalance renumbers order of every todo in the same list except the moved todo, and returns the new order by id.
+// lock version is incremented as well, so clients holding the previous order have to reload it.
+func (m move) rebalance(ctx context.Context, todo Todo) (map[uint]int, error) {
+	var (
+		todos  []Todo
+		orders map[uint]int
+	)
+
+	if err := m.repository.FindAll(ctx, &todos, rel.Select("id", "order").Where(siblings(todo), rel.Ne("id", todo.ID)).SortAsc("order").SortAsc("id").Lock("FOR UPDATE")); err != nil {
+		return nil, dberr.Classify(err)
+	}
+
+	logger.Info("rebalancing todos order", zap.Int("count", len(todos)), tracing.Field(ctx))
+
+	orders = make(map[uint]int, len(todos))
+	for i := range todos {
+		orders[todos[i].ID] = (i + 1) * OrderGap
+		if todos[i].Order == orders[todos[i].ID] {
+			continue
+		}
+
+		if _, err := m.repository.UpdateAny(ctx, rel.From("todos").Where(rel.Eq("id", todos[i].ID)),
+			rel.Set("order", orders[todos[i].ID]), rel.Inc("lock_version")); err != nil {
+			return nil, dberr.Classify(err)
+		}
+	}
+
+	return orders, nil
+}
+
+// siblings returns filter of todos that are ordered together with the todo, which are todos in the same list,
+// or todos of the same user without list.
+func siblings(todo Todo) rel.FilterQuery {
+	if todo.ListID != nil {
+		return rel.Eq("list_id", *todo.ListID)
+	}
+
+	return rel.And(rel.Eq("user_id", todo.UserID), rel.Nil("list_id"))
+}
+
+// bounds returns the order range available between neighbours, both ends are excluded from the range.
+// the list is open ended when neighbour is missing, but the range never goes past the order that todo accepts,
+// so the list is rebalanced instead when there's no room left before the first or after the last todo.
+func bounds(prev *Todo, next *Todo) (int, int) {
+	switch {
+	case prev == nil:
+		lower := next.Order - 2*OrderGap
+		if lower < -1 {
+			lower = -1
+		}
+
+		return lower, next.Order
+	case next == nil:
+		upper := prev.Order + 2*OrderGap
+		if upper > math.MaxInt32 {
+			upper = math.MaxInt32
+		}
+
+		return prev.Order, upper
+	default:
+		return prev.Order, next.Order
+	}
+}
+
+// sortedBefore reports whether a comes before b when todos are sorted by order.
+func sortedBefore(a Todo, b Todo) bool {
+	return a.Order < b.Order || (a.Order == b.Order && a.ID < b.ID)
+}
@@ -0,0 +1,47 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/go-rel/rel"
+)
+
+type moveList struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+// MoveList moves todos of the list that's going to be deleted to another list, including the ones that are trashed.
+// version of the moved todos is bumped, so changes made against the todos in the old list are rejected,
+// and updated event is published for the todos that aren't trashed.
+func (m moveList) MoveList(ctx context.Context, listID uint, moveTo uint) error {
+	return events.Transaction(ctx, m.repository, func(ctx context.Context) error {
+		var (
+			todos []Todo
+			query = rel.Select().Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE").
+				Preload("tags").Preload("tags.tag").Preload("items")
+			updatedAt = now()
+		)
+
+		if err := m.repository.FindAll(ctx, &todos, query); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if _, err := m.repository.UpdateAny(ctx, rel.From("todos").Where(rel.Eq("list_id", listID)),
+			rel.Set("list_id", moveTo), rel.Set("updated_at", updatedAt), rel.Inc("lock_version")); err != nil {
+			return dberr.Classify(err)
+		}
+
+		evs := make([]events.Event, len(todos))
+		for i := range todos {
+			todos[i].ListID = &moveTo
+			todos[i].UpdatedAt = updatedAt
+			todos[i].LockVersion++
+			evs[i] = todoEvent(events.TodoUpdated, todos[i])
+		}
+
+		return m.bus.Publish(ctx, evs...)
+	})
+}
@@ -0,0 +1,63 @@
+package todos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveList(t *testing.T) {
+	var (
+		listID     = uint(3)
+		moveTo     = uint(4)
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		bus        = events.NewBus(events.DefaultBufferSize)
+		service    = New(repository, nil, bus)
+		sub, _, _  = bus.Subscribe(func(events.Event) bool { return true }, 0)
+		query      = rel.Select().Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE").
+				Preload("tags").Preload("tags.tag").Preload("items")
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{{ID: 1, UserID: 1, ListID: &listID, Title: "Sleep", LockVersion: 2}})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("list_id", listID)), rel.Set("list_id", moveTo), rel.Set("updated_at", today), rel.Inc("lock_version")).UpdatedCount(2)
+	})
+
+	assert.Nil(t, service.MoveList(ctx, listID, moveTo))
+
+	// the todo is published with its new list and version, members of that list are notified.
+	event := <-sub.Events()
+	assert.Equal(t, events.TodoUpdated, event.Type)
+	assert.Equal(t, &moveTo, event.ListID)
+	assert.JSONEq(t, `{"id":1, "list_id":4, "title":"Sleep", "completed":false, "order":0, "priority":"normal", "version":3, "url":"http://localhost:3000/1", "created_at":"0001-01-01T00:00:00Z", "updated_at":"2020-06-28T00:00:00Z"}`, string(event.Data))
+
+	repository.AssertExpectations(t)
+}
+
+func TestMoveList_error(t *testing.T) {
+	var (
+		listID     = uint(3)
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		query      = rel.Select().Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE").
+				Preload("tags").Preload("tags.tag").Preload("items")
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("list_id", listID)), rel.Set("list_id", uint(4)), rel.Set("updated_at", today), rel.Inc("lock_version")).ConnectionClosed()
+	})
+
+	assert.True(t, errors.Is(service.MoveList(ctx, listID, 4), dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package todos
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMove(t *testing.T) {
+	var (
+		two   = uint(2)
+		three = uint(3)
+	)
+
+	tests := []struct {
+		name     string
+		todo     Todo
+		position Position
+		order    int
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:     "after",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{After: &two},
+			order:    1536,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 2, Order: 1024})
+				repository.ExpectFind(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1)), rel.Or(
+					rel.Gt("order", 1024),
+					rel.And(rel.Eq("order", 1024), rel.Gt("id", uint(2))),
+				), rel.SortAsc("order"), rel.SortAsc("id"), rel.ForUpdate()).Result(Todo{ID: 3, Order: 2048})
+			},
+		},
+		{
+			name:     "after last",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{After: &two},
+			order:    2048,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 2, Order: 1024})
+				repository.ExpectFind(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1)), rel.Or(
+					rel.Gt("order", 1024),
+					rel.And(rel.Eq("order", 1024), rel.Gt("id", uint(2))),
+				), rel.SortAsc("order"), rel.SortAsc("id"), rel.ForUpdate()).NotFound()
+			},
+		},
+		{
+			name:     "before first",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{Before: &three},
+			order:    511,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", three), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 3, Order: 1024})
+				repository.ExpectFind(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1)), rel.Or(
+					rel.Lt("order", 1024),
+					rel.And(rel.Eq("order", 1024), rel.Lt("id", uint(3))),
+				), rel.SortDesc("order"), rel.SortDesc("id"), rel.ForUpdate()).NotFound()
+			},
+		},
+		{
+			name:     "before first at zero",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{Before: &three},
+			order:    511,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", three), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 3, Order: 0})
+				repository.ExpectFind(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1)), rel.Or(
+					rel.Lt("order", 0),
+					rel.And(rel.Eq("order", 0), rel.Lt("id", uint(3))),
+				), rel.SortDesc("order"), rel.SortDesc("id"), rel.ForUpdate()).NotFound()
+				repository.ExpectFindAll(rel.Select("id", "order").Where(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1))).SortAsc("order").SortAsc("id").Lock("FOR UPDATE")).
+					Result([]Todo{{ID: 3, Order: 0}, {ID: 4, Order: 0}})
+				repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(3))), rel.Set("order", 1024), rel.Inc("lock_version")).UpdatedCount(1)
+				repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(4))), rel.Set("order", 2048), rel.Inc("lock_version")).UpdatedCount(1)
+			},
+		},
+		{
+			name:     "after last at max order",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{After: &two},
+			order:    2048,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 2, Order: math.MaxInt32})
+				repository.ExpectFind(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1)), rel.Or(
+					rel.Gt("order", math.MaxInt32),
+					rel.And(rel.Eq("order", math.MaxInt32), rel.Gt("id", uint(2))),
+				), rel.SortAsc("order"), rel.SortAsc("id"), rel.ForUpdate()).NotFound()
+				repository.ExpectFindAll(rel.Select("id", "order").Where(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1))).SortAsc("order").SortAsc("id").Lock("FOR UPDATE")).
+					Result([]Todo{{ID: 2, Order: math.MaxInt32}})
+				repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(2))), rel.Set("order", 1024), rel.Inc("lock_version")).UpdatedCount(1)
+			},
+		},
+		{
+			name:     "between with rebalance",
+			todo:     Todo{ID: 1, UserID: 1, Title: "Sleep"},
+			position: Position{After: &two, Before: &three},
+			order:    1536,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 2, Order: 5})
+				repository.ExpectFind(rel.Eq("id", three), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 3, Order: 6})
+				repository.ExpectFindAll(rel.Select("id", "order").Where(rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.Ne("id", uint(1))).SortAsc("order").SortAsc("id").Lock("FOR UPDATE")).
+					Result([]Todo{{ID: 2, Order: 5}, {ID: 3, Order: 6}, {ID: 4, Order: 3072}})
+				repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(2))), rel.Set("order", 1024), rel.Inc("lock_version")).UpdatedCount(1)
+				repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(3))), rel.Set("order", 2048), rel.Inc("lock_version")).UpdatedCount(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = New(repository, nil, nil)
+				todo       = test.todo
+			)
+
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				test.mockRepo(repository)
+				repository.ExpectUpdate().ForType("todos.Todo")
+			})
+
+			assert.Nil(t, service.Move(ctx, &todo, test.position))
+			assert.Equal(t, test.order, todo.Order)
+			assert.Nil(t, todo.Validate())
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMove_invalid(t *testing.T) {
+	var (
+		one   = uint(1)
+		two   = uint(2)
+		three = uint(3)
+	)
+
+	tests := []struct {
+		name     string
+		position Position
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:     "without neighbour",
+			position: Position{},
+			err:      ErrTodoPositionInvalid,
+		},
+		{
+			name:     "after itself",
+			position: Position{After: &one},
+			err:      ErrTodoPositionInvalid,
+		},
+		{
+			name:     "before itself",
+			position: Position{Before: &one},
+			err:      ErrTodoPositionInvalid,
+		},
+		{
+			name:     "neighbour not found",
+			position: Position{After: &two},
+			err:      ErrTodoNeighbourNotFound,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).NotFound()
+				})
+			},
+		},
+		{
+			name:     "neighbours reversed",
+			position: Position{After: &three, Before: &two},
+			err:      ErrTodoPositionInvalid,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectFind(rel.Eq("id", three), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 3, Order: 2048})
+					repository.ExpectFind(rel.Eq("id", two), rel.And(rel.Eq("user_id", uint(1)), rel.Nil("list_id")), rel.ForUpdate()).Result(Todo{ID: 2, Order: 1024})
+				})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = New(repository, nil, nil)
+				todo       = Todo{ID: 1, UserID: 1, Title: "Sleep"}
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			assert.Equal(t, test.err, service.Move(ctx, &todo, test.position))
+			assert.Equal(t, 0, todo.Order)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
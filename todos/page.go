@@ -0,0 +1,170 @@
+package todos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/cursor"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTodoCursorInvalid validation error.
+	ErrTodoCursorInvalid = validation.New("after", "invalid", "Cursor is invalid or doesn't match the sort")
+)
+
+// sortField describes how a sortable field is read from a todo and decoded back from a cursor.
+type sortField struct {
+	value    func(t Todo) interface{}
+	decode   func(raw json.RawMessage) (interface{}, error)
+	nullable bool
+}
+
+// pageCursor is the position of the last todo of a page, encoded as opaque string for the client.
+type pageCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	ID     uint              `json:"id"`
+	// Ranked is set if todos are ordered by relevance before the sort.
+	Ranked bool `json:"r,omitempty"`
+}
+
+// Cursor returns an opaque cursor pointing after the given todo in the sort of filter, relevance isn't taken into account.
+// empty cursor is returned if the sort is invalid.
+func (f Filter) Cursor(last Todo) string {
+	return f.cursor(last, false)
+}
+
+func (f Filter) cursor(last Todo, ranked bool) string {
+	keys, err := f.sortKeys()
+	if err != nil {
+		return ""
+	}
+
+	page := pageCursor{
+		Sort:   signature(keys),
+		Values: make([]json.RawMessage, len(keys)),
+		ID:     last.ID,
+		Ranked: ranked,
+	}
+
+	for i, key := range keys {
+		page.Values[i], _ = json.Marshal(sortFields[key.field].value(last))
+	}
+
+	return cursor.Encode(page)
+}
+
+// keyset builds filter that matches todos sorted after the cursor.
+// for sort a, b it's equivalent to: a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?),
+// taking into account that postgres sorts null last in ascending order and first in descending order.
+// todos ordered by rank expression are matched by rank first, rank of the last todo is computed again by database
+// instead of stored in the cursor, so it's compared exactly to the rank of the other todos.
+func keyset(ctx context.Context, keys []sortKey, after string, rank string) (rel.FilterQuery, error) {
+	var (
+		page   pageCursor
+		equals []rel.FilterQuery
+		ors    []rel.FilterQuery
+	)
+
+	if err := cursor.Decode(after, &page); err != nil || page.Sort != signature(keys) || len(page.Values) != len(keys) || page.Ranked != (rank != "") {
+		logger.Warn("validation error", zap.Error(ErrTodoCursorInvalid), zap.String("cursor", after), tracing.Field(ctx))
+		return rel.FilterQuery{}, ErrTodoCursorInvalid
+	}
+
+	for i, key := range keys {
+		var (
+			field      = sortFields[key.field]
+			value, err = field.decode(page.Values[i])
+		)
+
+		if err != nil {
+			logger.Warn("validation error", zap.Error(ErrTodoCursorInvalid), zap.String("cursor", after), tracing.Field(ctx))
+			return rel.FilterQuery{}, ErrTodoCursorInvalid
+		}
+
+		if next, ok := field.next(key, value); ok {
+			ors = append(ors, rel.And(append(append([]rel.FilterQuery{}, equals...), next)...))
+		}
+
+		if value == nil {
+			equals = append(equals, rel.Nil(key.field))
+		} else {
+			equals = append(equals, rel.Eq(key.field, value))
+		}
+	}
+
+	ors = append(ors, rel.And(append(equals, rel.Gt("id", page.ID))...))
+
+	if rank != "" {
+		last := fmt.Sprintf("(SELECT %s FROM todos WHERE id = %d)", rank, page.ID)
+		return rel.Or(rel.FilterFragment(rank+" < "+last), rel.And(rel.FilterFragment(rank+" = "+last), rel.Or(ors...))), nil
+	}
+
+	return rel.Or(ors...), nil
+}
+
+// next returns filter for values sorted after the given value, false if nothing can be sorted after it.
+func (sf sortField) next(key sortKey, value interface{}) (rel.FilterQuery, bool) {
+	switch {
+	case value == nil && key.desc:
+		return rel.NotNil(key.field), true
+	case value == nil:
+		return rel.FilterQuery{}, false
+	case key.desc:
+		return rel.Lt(key.field, value), true
+	case sf.nullable:
+		return rel.Or(rel.Gt(key.field, value), rel.Nil(key.field)), true
+	default:
+		return rel.Gt(key.field, value), true
+	}
+}
+
+func signature(keys []sortKey) string {
+	var (
+		fields = make([]string, len(keys))
+	)
+
+	for i, key := range keys {
+		if key.desc {
+			fields[i] = "-" + key.field
+		} else {
+			fields[i] = key.field
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+func decodeInt(raw json.RawMessage) (interface{}, error) {
+	var value int
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func decodeString(raw json.RawMessage) (interface{}, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func decodeTime(raw json.RawMessage) (interface{}, error) {
+	var value *time.Time
+	if err := json.Unmarshal(raw, &value); err != nil || value == nil {
+		return nil, err
+	}
+
+	return *value, nil
+}
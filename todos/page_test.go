@@ -0,0 +1,86 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyset(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		last   Todo
+		result rel.FilterQuery
+	}{
+		{
+			name:   "default sort",
+			filter: Filter{},
+			last:   Todo{ID: 3, Priority: PriorityHigh, DueAt: &today, Order: 2},
+			result: rel.Or(
+				rel.And(rel.Lt("priority", 1)),
+				rel.And(rel.Eq("priority", 1), rel.Or(rel.Gt("due_at", today), rel.Nil("due_at"))),
+				rel.And(rel.Eq("priority", 1), rel.Eq("due_at", today), rel.Gt("order", 2)),
+				rel.And(rel.Eq("priority", 1), rel.Eq("due_at", today), rel.Eq("order", 2), rel.Gt("id", uint(3))),
+			),
+		},
+		{
+			name:   "null ascending",
+			filter: Filter{Sort: []string{"due_at"}},
+			last:   Todo{ID: 3},
+			result: rel.Or(
+				rel.And(rel.Nil("due_at"), rel.Gt("id", uint(3))),
+			),
+		},
+		{
+			name:   "null descending",
+			filter: Filter{Sort: []string{"-due_at", "title"}},
+			last:   Todo{ID: 3, Title: "Sleep"},
+			result: rel.Or(
+				rel.And(rel.NotNil("due_at")),
+				rel.And(rel.Nil("due_at"), rel.Gt("title", "Sleep")),
+				rel.And(rel.Nil("due_at"), rel.Eq("title", "Sleep"), rel.Gt("id", uint(3))),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys, err := test.filter.sortKeys()
+			assert.Nil(t, err)
+
+			result, err := keyset(context.TODO(), keys, test.filter.Cursor(test.last), "")
+			assert.Nil(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestKeyset_invalid(t *testing.T) {
+	var (
+		keys, _ = Filter{}.sortKeys()
+	)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{
+			name:   "malformed",
+			cursor: "invalid",
+		},
+		{
+			name:   "different sort",
+			cursor: Filter{Sort: []string{"title"}}.Cursor(Todo{ID: 1, Title: "Sleep"}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := keyset(context.TODO(), keys, test.cursor, "")
+			assert.Equal(t, ErrTodoCursorInvalid, err)
+		})
+	}
+}
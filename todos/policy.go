@@ -0,0 +1,72 @@
+package todos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// Policy authorizes the requesting user to perform actions on todos.
+// todo in a list is authorized by the member's role, while todo without list is only accessible by its creator.
+type Policy struct {
+	lists lists.Policy
+}
+
+// Authorize returns rel.ErrNotFound if the todo isn't accessible by the requesting user, and lists.ErrForbidden if the member's role doesn't allow the action.
+// todo outside of the list that the request is scoped to is treated as not found.
+func (p Policy) Authorize(ctx context.Context, todo Todo, action lists.Action) error {
+	if list, ok := lists.FromContext(ctx); ok && (todo.ListID == nil || *todo.ListID != list.ID) {
+		return rel.ErrNotFound
+	}
+
+	if todo.ListID == nil {
+		if todo.UserID != users.FromContext(ctx) {
+			return rel.ErrNotFound
+		}
+
+		return nil
+	}
+
+	return p.lists.Authorize(ctx, *todo.ListID, action)
+}
+
+// authorizeList checks whether the requesting user is allowed to put todos into the todo's list.
+func (p Policy) authorizeList(ctx context.Context, todo Todo) error {
+	if todo.ListID == nil {
+		return nil
+	}
+
+	if err := p.lists.Authorize(ctx, *todo.ListID, lists.ActionWrite); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			logger.Warn("validation error", zap.Error(ErrTodoListNotFound), zap.Uint("list_id", *todo.ListID), tracing.Field(ctx))
+			return ErrTodoListNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// accessible returns filter of todos which the requesting user is allowed to perform the action on,
+// limited to todos of the list if the request is scoped to a list.
+func accessible(ctx context.Context, action lists.Action) rel.FilterQuery {
+	if list, ok := lists.FromContext(ctx); ok {
+		return rel.And(rel.Eq("list_id", list.ID), lists.Accessible(ctx, "list_id", action))
+	}
+
+	return rel.Or(
+		rel.And(rel.Eq("user_id", users.FromContext(ctx)), rel.Nil("list_id")),
+		lists.Accessible(ctx, "list_id", action),
+	)
+}
+
+// NewPolicy of todos.
+func NewPolicy(repository rel.Repository) Policy {
+	return Policy{lists: lists.NewPolicy(repository)}
+}
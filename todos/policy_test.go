@@ -0,0 +1,105 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Authorize(t *testing.T) {
+	var (
+		listID = uint(1)
+	)
+
+	tests := []struct {
+		name     string
+		todo     Todo
+		action   lists.Action
+		err      error
+		mockRepo func(repository *reltest.Repository)
+	}{
+		{
+			name:   "own todo",
+			todo:   Todo{ID: 1, UserID: 1},
+			action: lists.ActionWrite,
+		},
+		{
+			name:   "todo of other user",
+			todo:   Todo{ID: 1, UserID: 2},
+			action: lists.ActionRead,
+			err:    rel.ErrNotFound,
+		},
+		{
+			name:   "viewer reads",
+			todo:   Todo{ID: 1, UserID: 2, ListID: &listID},
+			action: lists.ActionRead,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleViewer})
+			},
+		},
+		{
+			name:   "viewer writes",
+			todo:   Todo{ID: 1, UserID: 2, ListID: &listID},
+			action: lists.ActionWrite,
+			err:    lists.ErrForbidden,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleViewer})
+			},
+		},
+		{
+			name:   "creator is no longer member",
+			todo:   Todo{ID: 1, UserID: 1, ListID: &listID},
+			action: lists.ActionRead,
+			err:    rel.ErrNotFound,
+			mockRepo: func(repository *reltest.Repository) {
+				repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).NotFound()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				policy     = NewPolicy(repository)
+			)
+
+			if test.mockRepo != nil {
+				test.mockRepo(repository)
+			}
+
+			err := policy.Authorize(ctx, test.todo, test.action)
+			if test.err == rel.ErrNotFound {
+				assert.ErrorIs(t, err, rel.ErrNotFound)
+			} else {
+				assert.Equal(t, test.err, err)
+			}
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPolicy_Authorize_scopedList(t *testing.T) {
+	var (
+		ctx        = lists.NewContext(users.NewContext(context.TODO(), 1), lists.List{ID: 1})
+		repository = reltest.New()
+		policy     = NewPolicy(repository)
+		listID     = uint(1)
+		otherID    = uint(2)
+	)
+
+	repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleEditor})
+
+	assert.Nil(t, policy.Authorize(ctx, Todo{ID: 1, ListID: &listID}, lists.ActionWrite))
+	assert.Equal(t, rel.ErrNotFound, policy.Authorize(ctx, Todo{ID: 2, ListID: &otherID}, lists.ActionRead))
+	assert.Equal(t, rel.ErrNotFound, policy.Authorize(ctx, Todo{ID: 3, UserID: 1}, lists.ActionRead))
+
+	repository.AssertExpectations(t)
+}
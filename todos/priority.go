@@ -0,0 +1,81 @@
+package todos
+
+import (
+	"encoding/json"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrTodoPriorityInvalid validation error.
+	ErrTodoPriorityInvalid = validation.New("priority", "invalid", "Priority must be one of low, normal, high or urgent")
+)
+
+// Priority of a todo, stored as integer so it can be sorted.
+// The zero value is normal priority.
+type Priority int
+
+const (
+	// PriorityLow for todo that can wait.
+	PriorityLow Priority = iota - 1
+	// PriorityNormal is the default priority.
+	PriorityNormal
+	// PriorityHigh for todo that should be done soon.
+	PriorityHigh
+	// PriorityUrgent for todo that should be done immediately.
+	PriorityUrgent
+)
+
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityNormal: "normal",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+// ParsePriority from its name.
+func ParsePriority(name string) (Priority, error) {
+	for p, n := range priorityNames {
+		if n == name {
+			return p, nil
+		}
+	}
+
+	return 0, ErrTodoPriorityInvalid
+}
+
+// Valid returns true if priority is one of known priority.
+func (p Priority) Valid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+// String returns name of priority.
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// MarshalJSON encodes priority as its name.
+func (p Priority) MarshalJSON() ([]byte, error) {
+	if !p.Valid() {
+		return nil, ErrTodoPriorityInvalid
+	}
+
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes priority from its name.
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return ErrTodoPriorityInvalid
+	}
+
+	priority, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+
+	*p = priority
+	return nil
+}
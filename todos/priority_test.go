@@ -0,0 +1,49 @@
+package todos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority Priority
+		err      error
+	}{
+		{name: "low", priority: PriorityLow},
+		{name: "normal", priority: PriorityNormal},
+		{name: "high", priority: PriorityHigh},
+		{name: "urgent", priority: PriorityUrgent},
+		{name: "critical", err: ErrTodoPriorityInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			priority, err := ParsePriority(test.name)
+			assert.Equal(t, test.priority, priority)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestPriority_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal(PriorityUrgent)
+	assert.Nil(t, err)
+	assert.Equal(t, `"urgent"`, string(encoded))
+
+	_, err = json.Marshal(Priority(10))
+	assert.NotNil(t, err)
+}
+
+func TestPriority_UnmarshalJSON(t *testing.T) {
+	var priority Priority
+
+	assert.Nil(t, json.Unmarshal([]byte(`"high"`), &priority))
+	assert.Equal(t, PriorityHigh, priority)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"critical"`), &priority), ErrTodoPriorityInvalid)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`1`), &priority), ErrTodoPriorityInvalid)
+}
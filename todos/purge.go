@@ -0,0 +1,37 @@
+package todos
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+const (
+	// PurgeJob is name of the job that purges trashed todos.
+	PurgeJob = "todos.purge"
+)
+
+var (
+	// DefaultRetention of trashed todos before it's purged.
+	DefaultRetention = 30 * 24 * time.Hour
+)
+
+type purge struct {
+	repository rel.Repository
+}
+
+// Purge permanently removes todos that has been in trash longer than retention, returns the number of purged todos.
+// Tags and checklist items of purged todos are removed by the database through cascading foreign keys.
+func (p purge) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	count, err := p.repository.DeleteAny(ctx, rel.From("todos").Where(rel.Lt("deleted_at", now().Add(-retention))))
+	if err != nil {
+		return 0, dberr.Classify(err)
+	}
+
+	logger.Info("purged todos", zap.Int("count", count), zap.Duration("retention", retention), tracing.Field(ctx))
+	return count, nil
+}
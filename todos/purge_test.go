@@ -0,0 +1,45 @@
+package todos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurge(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		retention  = 24 * time.Hour
+	)
+
+	repository.ExpectDeleteAny(rel.From("todos").Where(rel.Lt("deleted_at", yesterday))).DeletedCount(3)
+
+	count, err := service.Purge(ctx, retention)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPurge_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+	)
+
+	repository.ExpectDeleteAny(rel.From("todos").Where(rel.Lt("deleted_at", today.Add(-DefaultRetention)))).ConnectionClosed()
+
+	count, err := service.Purge(ctx, DefaultRetention)
+	assert.Equal(t, dberr.Classify(reltest.ErrConnectionClosed), err)
+	assert.Equal(t, 0, count)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,28 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/go-rel/rel"
+)
+
+type restore struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+// Restore moves trashed todo back to the list, it's published as created as the todo reappears to subscribers.
+func (r restore) Restore(ctx context.Context, todo *Todo) error {
+	todo.DeletedAt = nil
+
+	return events.Transaction(ctx, r.repository, func(ctx context.Context) error {
+		// unscoped, otherwise rel won't find the trashed todo to update.
+		if err := r.repository.Update(ctx, todo, rel.Unscoped(true)); err != nil {
+			return dberr.Classify(err)
+		}
+
+		return r.bus.Publish(ctx, todoEvent(events.TodoCreated, *todo))
+	})
+}
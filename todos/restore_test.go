@@ -0,0 +1,28 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestore(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", DeletedAt: &yesterday}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(rel.Unscoped(true)).ForType("todos.Todo")
+	})
+
+	assert.Nil(t, service.Restore(ctx, &todo))
+	assert.Nil(t, todo.DeletedAt)
+
+	repository.AssertExpectations(t)
+}
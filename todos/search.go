@@ -2,33 +2,312 @@ package todos
 
 import (
 	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/postgres"
 	"github.com/go-rel/rel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTodoSortInvalid validation error.
+	ErrTodoSortInvalid = validation.New("sort", "invalid", "Sort must be one of priority, due_at, order, title, created_at or updated_at")
+	// ErrTodoLimitInvalid validation error.
+	ErrTodoLimitInvalid = validation.New("limit", "out_of_range", "Limit must be between 1 and 100")
+	// DefaultSort is used when filter doesn't specify any sort.
+	DefaultSort = []string{"-priority", "due_at", "order"}
+	// DefaultLimit is used when filter doesn't specify any limit.
+	DefaultLimit = 50
+	// MaxLimit of todos in a single page.
+	MaxLimit = 100
+
+	// searchConfig is the text search configuration used to build search column of todos, queries must use the same configuration.
+	searchConfig = "english"
+
+	// startSel and stopSel mark matches in snippet, they're private use characters that html escaping leaves as is.
+	startSel = "\ue000"
+	stopSel  = "\ue001"
+
+	sortFields = map[string]sortField{
+		"priority":   {value: func(t Todo) interface{} { return int(t.Priority) }, decode: decodeInt},
+		"due_at":     {value: func(t Todo) interface{} { return t.DueAt }, decode: decodeTime, nullable: true},
+		"order":      {value: func(t Todo) interface{} { return t.Order }, decode: decodeInt},
+		"title":      {value: func(t Todo) interface{} { return t.Title }, decode: decodeString},
+		"created_at": {value: func(t Todo) interface{} { return t.CreatedAt }, decode: decodeTime},
+		"updated_at": {value: func(t Todo) interface{} { return t.UpdatedAt }, decode: decodeTime},
+	}
 )
 
 // Filter for search.
 type Filter struct {
-	Keyword   string
+	// ListID limits todos to the ones in the list, zero means todos of every accessible list and todos without list.
+	ListID uint
+	// Keyword to match against title, todos are ordered by relevance if Sort isn't specified and full text search is supported.
+	Keyword string
+	// Highlight sets snippet of each todo with the matched keyword highlighted.
+	Highlight bool
 	Completed *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Overdue   bool
+	// Tags to match by name, todo matches if it has any of the tags unless MatchAllTags is set.
+	Tags         []string
+	MatchAllTags bool
+	// Sort fields, prefix the field with - to sort descending.
+	Sort []string
+	// Limit the number of todos returned, zero means DefaultLimit.
+	Limit int
+	// After is the cursor of the last todo from previous page.
+	After string
+}
+
+// sortKey is a parsed sort field.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+func (f Filter) sortKeys() ([]sortKey, error) {
+	var (
+		sort = f.Sort
+	)
+
+	if len(sort) == 0 {
+		sort = DefaultSort
+	}
+
+	keys := make([]sortKey, len(sort))
+	for i, field := range sort {
+		keys[i].desc = strings.HasPrefix(field, "-")
+		keys[i].field = strings.TrimPrefix(field, "-")
+
+		if _, ok := sortFields[keys[i].field]; !ok {
+			return nil, ErrTodoSortInvalid
+		}
+	}
+
+	return keys, nil
 }
 
 type search struct {
 	repository rel.Repository
+	// fullText is set if the adapter supports postgres full text search, otherwise keyword is matched using LIKE.
+	fullText bool
 }
 
+// relevance reports whether todos are ordered by relevance to the keyword, keyword matched using LIKE isn't ranked.
+func (s search) relevance(filter Filter) bool {
+	return s.fullText && filter.Keyword != "" && len(filter.Sort) == 0
+}
+
+// rank returns expression of todo relevance to the keyword, empty if todos aren't ordered by relevance.
+func (s search) rank(filter Filter) string {
+	if !s.relevance(filter) {
+		return ""
+	}
+
+	return "ts_rank(search, " + tsquery(filter.Keyword) + ")"
+}
+
+// Cursor returns an opaque cursor pointing after the given todo, to be used as After of the next page.
+func (s search) Cursor(filter Filter, last Todo) string {
+	return filter.cursor(last, s.relevance(filter))
+}
+
+// Search todos, keyword is matched using full text search of title when supported by the adapter.
 func (s search) Search(ctx context.Context, todos *[]Todo, filter Filter) error {
 	var (
-		query = rel.Select().SortAsc("order")
+		// trashed todos are excluded by rel's soft delete scope.
+		query     = rel.Select().Preload("tags").Preload("tags.tag").Preload("items")
+		keys, err = filter.sortKeys()
 	)
 
+	if err != nil {
+		logger.Warn("validation error", zap.Error(err), zap.Strings("sort", filter.Sort), tracing.Field(ctx))
+		return err
+	}
+
+	query = query.Where(accessible(ctx, lists.ActionRead))
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	if limit < 0 || limit > MaxLimit {
+		logger.Warn("validation error", zap.Error(ErrTodoLimitInvalid), zap.Int("limit", limit), tracing.Field(ctx))
+		return ErrTodoLimitInvalid
+	}
+
+	// most relevant todos first, the rest of sort is used as tie breaker.
+	rank := s.rank(filter)
+	if rank != "" {
+		query = query.SortDesc("^" + rank)
+	}
+
+	for _, key := range keys {
+		if key.desc {
+			query = query.SortDesc(key.field)
+		} else {
+			query = query.SortAsc(key.field)
+		}
+	}
+
+	// id as tie breaker, so todos with the same sort values are always returned in the same order.
+	query = query.SortAsc("id")
+
+	query = query.Limit(limit)
+
+	if filter.After != "" {
+		after, err := keyset(ctx, keys, filter.After, rank)
+		if err != nil {
+			return err
+		}
+
+		query = query.Where(after)
+	}
+
+	if filter.ListID != 0 {
+		query = query.Where(rel.Eq("list_id", filter.ListID))
+	}
+
 	if filter.Keyword != "" {
-		query = query.Where(rel.Like("title", "%"+filter.Keyword+"%"))
+		if s.fullText {
+			query = query.Where(rel.FilterFragment("search @@ " + tsquery(filter.Keyword)))
+		} else {
+			// lowered on both side, so the keyword is matched case-insensitively on any adapter.
+			query = query.Where(rel.FilterFragment("LOWER(title) LIKE ?", "%"+strings.ToLower(filter.Keyword)+"%"))
+		}
 	}
 
 	if filter.Completed != nil {
 		query = query.Where(rel.Eq("completed", *filter.Completed))
 	}
 
-	s.repository.MustFindAll(ctx, todos, query)
+	if filter.DueBefore != nil {
+		query = query.Where(rel.Lt("due_at", *filter.DueBefore))
+	}
+
+	if filter.DueAfter != nil {
+		query = query.Where(rel.Gt("due_at", *filter.DueAfter))
+	}
+
+	// overdue todos are the one that past its due date and not yet completed.
+	if filter.Overdue {
+		query = query.Where(rel.Lt("due_at", now()), rel.Eq("completed", false))
+	}
+
+	if len(filter.Tags) > 0 {
+		query = query.Where(rel.In("id", tagged(filter.Tags, filter.MatchAllTags)))
+	}
+
+	if err := s.repository.FindAll(ctx, todos, query); err != nil {
+		return dberr.Classify(err)
+	}
+
+	if s.fullText && filter.Highlight && filter.Keyword != "" {
+		return s.highlight(ctx, *todos, filter.Keyword)
+	}
+
 	return nil
 }
+
+// tagged returns query that selects id of todos tagged by any of the names, or by all of them if matchAll is set.
+func tagged(names []string, matchAll bool) rel.Query {
+	// duplicate names are removed, otherwise todo never has as many distinct tags as the names to match all of them.
+	names = uniqueNames(names)
+	query := rel.Select("todo_tags.todo_id").From("todo_tags").
+		JoinOn("tags", "tags.id", "todo_tags.tag_id").
+		Where(rel.InString("tags.name", names))
+
+	if matchAll {
+		// fragment is written as is, so the count is inlined instead of using placeholder that postgres doesn't bind.
+		query = query.Group("todo_tags.todo_id").
+			Having(rel.FilterFragment(fmt.Sprintf("COUNT(DISTINCT tags.id) = %d", len(names))))
+	}
+
+	return query
+}
+
+// highlight sets snippet of todos, snippets are generated only for the returned page as ts_headline is expensive.
+// the matches are marked with private use characters, so the title is html escaped before the marks are turned into <mark> tags.
+func (s search) highlight(ctx context.Context, todos []Todo, keyword string) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	var (
+		snippets     []snippet
+		placeholders = make([]string, len(todos))
+		args         = make([]interface{}, len(todos))
+		index        = make(map[uint]int, len(todos))
+	)
+
+	for i := range todos {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = todos[i].ID
+		index[todos[i].ID] = i
+	}
+
+	if err := s.repository.FindAll(ctx, &snippets, rel.SQL(
+		"SELECT id, ts_headline('"+searchConfig+"', title, "+tsquery(keyword)+", 'StartSel="+startSel+", StopSel="+stopSel+"') AS snippet FROM todos WHERE id IN ("+strings.Join(placeholders, ", ")+")",
+		args...,
+	)); err != nil {
+		return dberr.Classify(err)
+	}
+
+	for _, snippet := range snippets {
+		todos[index[snippet.ID]].Snippet = markup(snippet.Snippet)
+	}
+
+	return nil
+}
+
+// markup escapes snippet as html, and replaces the marks of matches with <mark> tags.
+func markup(snippet string) string {
+	return strings.NewReplacer(startSel, "<mark>", stopSel, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// uniqueNames returns names without duplicates, in the order they first appear.
+func uniqueNames(names []string) []string {
+	var (
+		seen   = make(map[string]bool, len(names))
+		unique = make([]string, 0, len(names))
+	)
+
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
+
+// snippet of todo title with matched keyword highlighted.
+type snippet struct {
+	ID      uint
+	Snippet string
+}
+
+// tsquery returns expression that parses keyword as web search query.
+// keyword is inlined as quoted literal, because rel doesn't convert placeholders of sort and fragment to postgres placeholders.
+func tsquery(keyword string) string {
+	return "websearch_to_tsquery('" + searchConfig + "', " + pq.QuoteLiteral(keyword) + ")"
+}
+
+// supportsFullText reports whether the repository's adapter supports postgres full text search.
+func supportsFullText(repository rel.Repository) bool {
+	_, ok := repository.Adapter(context.Background()).(*postgres.Postgres)
+	return ok
+}
@@ -0,0 +1,16 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type searchItems struct {
+	repository rel.Repository
+}
+
+func (si searchItems) SearchItems(ctx context.Context, items *[]ChecklistItem, todo Todo) error {
+	return dberr.Classify(si.repository.FindAll(ctx, items, rel.Where(rel.Eq("todo_id", todo.ID)).SortAsc("order").SortAsc("id")))
+}
@@ -0,0 +1,28 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchItems(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+		items      []ChecklistItem
+		result     = []ChecklistItem{{ID: 1, TodoID: 1, Title: "Brush teeth"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("todo_id", uint(1))).SortAsc("order").SortAsc("id")).Result(result)
+
+	assert.Nil(t, service.SearchItems(ctx, &items, todo))
+	assert.Equal(t, result, items)
+
+	repository.AssertExpectations(t)
+}
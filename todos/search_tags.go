@@ -0,0 +1,17 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+)
+
+type searchTags struct {
+	repository rel.Repository
+}
+
+func (st searchTags) SearchTags(ctx context.Context, tags *[]Tag) error {
+	return dberr.Classify(st.repository.FindAll(ctx, tags, rel.Where(rel.Eq("user_id", users.FromContext(ctx))).SortAsc("name")))
+}
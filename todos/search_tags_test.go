@@ -0,0 +1,28 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTags(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		tags       []Tag
+		result     = []Tag{{ID: 1, Name: "home"}, {ID: 2, Name: "work"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1))).SortAsc("name")).Result(result)
+
+	assert.Nil(t, service.SearchTags(ctx, &tags))
+	assert.Equal(t, result, tags)
+
+	repository.AssertExpectations(t)
+}
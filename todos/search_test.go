@@ -2,18 +2,24 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/postgres"
 	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
+	"github.com/go-rel/sql/builder"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestSearch(t *testing.T) {
 	var (
-		ctx        = context.TODO()
+		ctx        = users.NewContext(context.TODO(), 1)
 		repository = reltest.New()
-		service    = New(repository, nil)
+		service    = New(repository, nil, nil)
 		todos      []Todo
 		completed  = false
 		filter     = Filter{Keyword: "Sleep", Completed: &completed}
@@ -21,7 +27,7 @@ func TestSearch(t *testing.T) {
 	)
 
 	repository.ExpectFindAll(
-		rel.Select().SortAsc("order").Where(rel.Like("title", "%Sleep%").AndEq("completed", false)),
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).Where(accessible(ctx, lists.ActionRead), rel.FilterFragment("LOWER(title) LIKE ?", "%sleep%"), rel.Eq("completed", false)),
 	).Result(result)
 
 	assert.NotPanics(t, func() {
@@ -31,3 +37,331 @@ func TestSearch(t *testing.T) {
 
 	repository.AssertExpectations(t)
 }
+
+func TestSearch_due(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{DueAfter: &yesterday, DueBefore: &tomorrow}
+		result     = []Todo{{ID: 1, Title: "Sleep", DueAt: &today}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).Where(accessible(ctx, lists.ActionRead), rel.Lt("due_at", tomorrow), rel.Gt("due_at", yesterday)),
+	).Result(result)
+
+	assert.NotPanics(t, func() {
+		service.Search(ctx, &todos, filter)
+		assert.Equal(t, result, todos)
+	})
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_overdue(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Overdue: true}
+		result     = []Todo{{ID: 1, Title: "Sleep", DueAt: &yesterday}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).Where(accessible(ctx, lists.ActionRead), rel.Lt("due_at", today), rel.Eq("completed", false)),
+	).Result(result)
+
+	assert.NotPanics(t, func() {
+		service.Search(ctx, &todos, filter)
+		assert.Equal(t, result, todos)
+	})
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_sort(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Sort: []string{"-due_at", "title"}}
+		result     = []Todo{{ID: 1, Title: "Sleep"}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("due_at").SortAsc("title").SortAsc("id").Limit(50).Where(accessible(ctx, lists.ActionRead)),
+	).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, result, todos)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_sortInvalid(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Sort: []string{"password"}}
+	)
+
+	assert.Equal(t, ErrTodoSortInvalid, service.Search(ctx, &todos, filter))
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_tags(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Tags: []string{"work", "home"}}
+		tagged     = rel.Select("todo_tags.todo_id").From("todo_tags").
+				JoinOn("tags", "tags.id", "todo_tags.tag_id").
+				Where(rel.InString("tags.name", []string{"work", "home"}))
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).
+			Where(accessible(ctx, lists.ActionRead), rel.In("id", tagged)),
+	).Result([]Todo{})
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_allTags(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Tags: []string{"work", "home", "work"}, MatchAllTags: true}
+		tagged     = rel.Select("todo_tags.todo_id").From("todo_tags").
+				JoinOn("tags", "tags.id", "todo_tags.tag_id").
+				Where(rel.InString("tags.name", []string{"work", "home"})).
+				Group("todo_tags.todo_id").
+				Having(rel.FilterFragment("COUNT(DISTINCT tags.id) = 2"))
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).
+			Where(accessible(ctx, lists.ActionRead), rel.In("id", tagged)),
+	).Result([]Todo{})
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+
+	repository.AssertExpectations(t)
+}
+
+func TestTagged_sql(t *testing.T) {
+	var (
+		// same builder as postgres adapter, fragments must be written in its $n placeholder.
+		buffer    = builder.BufferFactory{ArgumentPlaceholder: "$", ArgumentOrdinal: true, Quoter: postgres.Quote{}, ValueConverter: postgres.ValueConvert{}}
+		query     = builder.Query{BufferFactory: buffer, Filter: builder.Filter{}}
+		sql, args = query.Build(tagged([]string{"work", "home", "work"}, true))
+	)
+
+	assert.Equal(t, `SELECT "todo_tags"."todo_id" FROM "todo_tags" JOIN "tags" ON "tags"."id"="todo_tags"."tag_id" WHERE "tags"."name" IN ($1,$2) GROUP BY "todo_tags"."todo_id" HAVING COUNT(DISTINCT tags.id) = 2;`, sql)
+	assert.Equal(t, []interface{}{"work", "home"}, args)
+}
+
+func TestSearch_page(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		filter     = Filter{Sort: []string{"order"}, Limit: 10}
+		result     = []Todo{{ID: 4, Title: "Sleep", Order: 2}}
+	)
+
+	filter.After = filter.Cursor(Todo{ID: 3, Order: 2})
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortAsc("order").SortAsc("id").Limit(10).
+			Where(accessible(ctx, lists.ActionRead), rel.Or(rel.And(rel.Gt("order", 2)), rel.And(rel.Eq("order", 2), rel.Gt("id", uint(3))))),
+	).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, result, todos)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_pageInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		err    error
+	}{
+		{
+			name:   "limit too large",
+			filter: Filter{Limit: MaxLimit + 1},
+			err:    ErrTodoLimitInvalid,
+		},
+		{
+			name:   "invalid cursor",
+			filter: Filter{Limit: 10, After: "invalid"},
+			err:    ErrTodoCursorInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = users.NewContext(context.TODO(), 1)
+				repository = reltest.New()
+				service    = New(repository, nil, nil)
+				todos      []Todo
+			)
+
+			assert.Equal(t, test.err, service.Search(ctx, &todos, test.filter))
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearch_fullText(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = search{repository: repository, fullText: true}
+		todos      []Todo
+		filter     = Filter{Keyword: "sleep's", Highlight: true}
+		result     = []Todo{{ID: 1, Title: "Sleep"}, {ID: 2, Title: "Sleep <b>early</b>"}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").
+			SortDesc("^ts_rank(search, websearch_to_tsquery('english', 'sleep''s'))").
+			SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).
+			Where(accessible(ctx, lists.ActionRead), rel.FilterFragment("search @@ websearch_to_tsquery('english', 'sleep''s')")),
+	).Result(result)
+	repository.ExpectFindAll(
+		rel.SQL("SELECT id, ts_headline('english', title, websearch_to_tsquery('english', 'sleep''s'), 'StartSel=\ue000, StopSel=\ue001') AS snippet FROM todos WHERE id IN ($1, $2)", uint(1), uint(2)),
+	).Result([]snippet{{ID: 2, Snippet: "\ue000Sleep\ue001 <b>early</b>"}, {ID: 1, Snippet: "\ue000Sleep\ue001"}})
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, []Todo{
+		{ID: 1, Title: "Sleep", Snippet: "<mark>Sleep</mark>"},
+		{ID: 2, Title: "Sleep <b>early</b>", Snippet: "<mark>Sleep</mark> &lt;b&gt;early&lt;/b&gt;"},
+	}, todos)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_fullTextSorted(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = search{repository: repository, fullText: true}
+		todos      []Todo
+		filter     = Filter{Keyword: "sleep", Sort: []string{"title"}}
+		result     = []Todo{{ID: 1, Title: "Sleep"}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortAsc("title").SortAsc("id").Limit(50).
+			Where(accessible(ctx, lists.ActionRead), rel.FilterFragment("search @@ websearch_to_tsquery('english', 'sleep')")),
+	).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, result, todos)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_relevancePage(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = search{repository: repository, fullText: true}
+		todos      []Todo
+		filter     = Filter{Keyword: "sleep", Limit: 1}
+		rank       = "ts_rank(search, websearch_to_tsquery('english', 'sleep'))"
+		last       = rank + " = (SELECT " + rank + " FROM todos WHERE id = 1)"
+		result     = []Todo{{ID: 2, Title: "Sleep early"}}
+	)
+
+	// todos that are ranked lower than the last todo, or ranked the same and sorted after it.
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").
+			SortDesc("^"+rank).SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(1).
+			Where(accessible(ctx, lists.ActionRead), rel.Or(
+				rel.FilterFragment(rank+" < (SELECT "+rank+" FROM todos WHERE id = 1)"),
+				rel.And(rel.FilterFragment(last), rel.Or(
+					rel.And(rel.Lt("priority", 0)),
+					rel.And(rel.Eq("priority", 0), rel.Or(rel.Gt("due_at", today), rel.Nil("due_at"))),
+					rel.And(rel.Eq("priority", 0), rel.Eq("due_at", today), rel.Gt("order", 3)),
+					rel.And(rel.Eq("priority", 0), rel.Eq("due_at", today), rel.Eq("order", 3), rel.Gt("id", uint(1))),
+				)),
+			), rel.FilterFragment("search @@ websearch_to_tsquery('english', 'sleep')")),
+	).Result(result)
+
+	filter.After = service.Cursor(filter, Todo{ID: 1, Title: "Sleep", DueAt: &today, Order: 3})
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, result, todos)
+
+	// cursor of todos that aren't ranked doesn't point to the same position in relevance order.
+	filter.After = Filter{Limit: 1}.Cursor(Todo{ID: 1})
+	assert.Equal(t, ErrTodoCursorInvalid, service.Search(ctx, &todos, filter))
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_likeCursor(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = search{repository: repository}
+		todos      []Todo
+		filter     = Filter{Keyword: "Sleep", Limit: 1}
+		after      = service.Cursor(filter, Todo{ID: 1})
+		result     = []Todo{{ID: 2, Title: "sleep early"}}
+	)
+
+	// keyword matched using LIKE isn't ranked, so todos are paginated in the default sort.
+	assert.Equal(t, Filter{}.Cursor(Todo{ID: 1}), after)
+
+	keys, _ := filter.sortKeys()
+	keyset, _ := keyset(context.TODO(), keys, after, "")
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(1).
+			Where(accessible(ctx, lists.ActionRead), keyset, rel.FilterFragment("LOWER(title) LIKE ?", "%sleep%")),
+	).Result(result)
+
+	filter.After = after
+	assert.Nil(t, service.Search(ctx, &todos, filter))
+	assert.Equal(t, result, todos)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearch_error(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Preload("tags").Preload("tags.tag").Preload("items").SortDesc("priority").SortAsc("due_at").SortAsc("order").SortAsc("id").Limit(50).Where(accessible(ctx, lists.ActionRead)),
+	).ConnectionClosed()
+
+	err := service.Search(ctx, &todos, Filter{})
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+	assert.True(t, errors.Is(err, reltest.ErrConnectionClosed))
+
+	repository.AssertExpectations(t)
+}
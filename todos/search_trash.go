@@ -0,0 +1,22 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/go-rel/rel"
+)
+
+type searchTrash struct {
+	repository rel.Repository
+}
+
+// SearchTrash returns trashed todos, most recently trashed first.
+func (st searchTrash) SearchTrash(ctx context.Context, todos *[]Todo) error {
+	query := rel.Select().Unscoped().Where(accessible(ctx, lists.ActionRead), rel.NotNil("deleted_at")).
+		Preload("tags").Preload("tags.tag").Preload("items").
+		SortDesc("deleted_at").SortAsc("id")
+
+	return dberr.Classify(st.repository.FindAll(ctx, todos, query))
+}
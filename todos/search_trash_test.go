@@ -0,0 +1,33 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/lists"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTrash(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todos      []Todo
+		result     = []Todo{{ID: 1, Title: "Sleep", DeletedAt: &yesterday}}
+	)
+
+	repository.ExpectFindAll(
+		rel.Select().Unscoped().Where(accessible(ctx, lists.ActionRead), rel.NotNil("deleted_at")).
+			Preload("tags").Preload("tags.tag").Preload("items").
+			SortDesc("deleted_at").SortAsc("id"),
+	).Result(result)
+
+	assert.Nil(t, service.SearchTrash(ctx, &todos))
+	assert.Equal(t, result, todos)
+
+	repository.AssertExpectations(t)
+}
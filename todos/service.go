@@ -2,7 +2,9 @@ package todos
 
 import (
 	"context"
+	"time"
 
+	"github.com/Fs02/go-todo-backend/events"
 	"github.com/Fs02/go-todo-backend/scores"
 	"github.com/go-rel/rel"
 	"go.uber.org/zap"
@@ -18,10 +20,26 @@ var (
 // Any operation done to any of object within this domain should use this service.
 type Service interface {
 	Search(ctx context.Context, todos *[]Todo, filter Filter) error
+	Cursor(filter Filter, last Todo) string
 	Create(ctx context.Context, todo *Todo) error
 	Update(ctx context.Context, todo *Todo, changes rel.Changeset) error
-	Delete(ctx context.Context, todo *Todo)
-	Clear(ctx context.Context)
+	Delete(ctx context.Context, todo *Todo) error
+	Move(ctx context.Context, todo *Todo, position Position) error
+	Bulk(ctx context.Context, results *[]BulkResult, operations []BulkOperation, atomic bool) error
+	Clear(ctx context.Context) error
+	TrashList(ctx context.Context, listID uint) error
+	MoveList(ctx context.Context, listID uint, moveTo uint) error
+	SearchTrash(ctx context.Context, todos *[]Todo) error
+	Restore(ctx context.Context, todo *Todo) error
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+	SearchTags(ctx context.Context, tags *[]Tag) error
+	CreateTag(ctx context.Context, tag *Tag) error
+	DeleteTag(ctx context.Context, tag *Tag) error
+	SetTags(ctx context.Context, todo *Todo, names []string) error
+	SearchItems(ctx context.Context, items *[]ChecklistItem, todo Todo) error
+	CreateItem(ctx context.Context, todo *Todo, item *ChecklistItem) error
+	UpdateItem(ctx context.Context, todo *Todo, item *ChecklistItem, changes rel.Changeset) error
+	DeleteItem(ctx context.Context, todo *Todo, item *ChecklistItem) error
 }
 
 // beside embeding the struct, you can also declare the function directly on this struct.
@@ -31,18 +49,62 @@ type service struct {
 	create
 	update
 	delete
+	move
+	bulk
 	clear
+	trashList
+	moveList
+	searchTrash
+	restore
+	purge
+	searchTags
+	createTag
+	deleteTag
+	setTags
+	searchItems
+	createItem
+	updateItem
+	deleteItem
 }
 
-var _ Service = (*service)(nil)
-
-// New Todos service.
-func New(repository rel.Repository, scores scores.Service) Service {
-	return service{
-		search: search{repository: repository},
-		create: create{repository: repository, scores: scores},
-		update: update{repository: repository, scores: scores},
-		delete: delete{repository: repository},
-		clear:  clear{repository: repository},
-	}
+var (
+	_ Service = (*service)(nil)
+	_ Service = (*traced)(nil)
+)
+
+// New Todos service, every operation is traced and changes are published to bus after they're committed, bus can be nil.
+func New(repository rel.Repository, scores scores.Service, bus *events.Bus) Service {
+	var (
+		policy = NewPolicy(repository)
+		create = create{repository: repository, scores: scores, policy: policy, bus: bus}
+		update = update{repository: repository, scores: scores, policy: policy, bus: bus}
+		delete = delete{repository: repository, bus: bus}
+	)
+
+	return traced{service{
+		search: search{repository: repository, fullText: supportsFullText(repository)},
+		create: create,
+		update: update,
+		delete: delete,
+		move:   move{repository: repository, update: update},
+		bulk:   bulk{repository: repository, policy: policy, create: create, update: update, delete: delete},
+		clear:  clear{repository: repository, bus: bus},
+
+		trashList: trashList{repository: repository, bus: bus},
+		moveList:  moveList{repository: repository, bus: bus},
+
+		searchTrash: searchTrash{repository: repository},
+		restore:     restore{repository: repository, bus: bus},
+		purge:       purge{repository: repository},
+
+		searchTags: searchTags{repository: repository},
+		createTag:  createTag{repository: repository},
+		deleteTag:  deleteTag{repository: repository},
+		setTags:    setTags{repository: repository, bus: bus},
+
+		searchItems: searchItems{repository: repository},
+		createItem:  createItem{repository: repository, bus: bus},
+		updateItem:  updateItem{repository: repository, update: update, bus: bus},
+		deleteItem:  deleteItem{repository: repository, bus: bus},
+	}}
 }
@@ -0,0 +1,87 @@
+package todos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type setTags struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+// SetTags replaces tags of a todo, tags that doesn't exist yet will be created.
+func (st setTags) SetTags(ctx context.Context, todo *Todo, names []string) error {
+	var (
+		seen   = make(map[string]bool, len(names))
+		unique = make([]string, 0, len(names))
+	)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			logger.Warn("validation error", zap.Error(ErrTagNameBlank), tracing.Field(ctx))
+			return ErrTagNameBlank
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return events.Transaction(ctx, st.repository, func(ctx context.Context) error {
+		var (
+			userID   = users.FromContext(ctx)
+			existing []Tag
+			tags     = make(map[string]Tag, len(unique))
+			todoTags = make([]TodoTag, len(unique))
+		)
+
+		if len(unique) > 0 {
+			if err := st.repository.FindAll(ctx, &existing, rel.Where(rel.Eq("user_id", userID), rel.InString("name", unique))); err != nil {
+				return dberr.Classify(err)
+			}
+		}
+
+		for _, tag := range existing {
+			tags[tag.Name] = tag
+		}
+
+		for i, name := range unique {
+			tag, ok := tags[name]
+			if !ok {
+				tag = Tag{UserID: userID, Name: name}
+				if err := st.repository.Insert(ctx, &tag); err != nil {
+					return dberr.Classify(err)
+				}
+			}
+
+			todoTags[i] = TodoTag{TodoID: todo.ID, TagID: tag.ID, Tag: tag}
+		}
+
+		if _, err := st.repository.DeleteAny(ctx, rel.From("todo_tags").Where(rel.Eq("todo_id", todo.ID))); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if len(todoTags) > 0 {
+			if err := st.repository.InsertAll(ctx, &todoTags); err != nil {
+				return dberr.Classify(err)
+			}
+		}
+
+		if err := touch(ctx, st.repository, todo); err != nil {
+			return err
+		}
+
+		todo.Tags = todoTags
+		return st.bus.Publish(ctx, todoEvent(events.TodoUpdated, *todo))
+	})
+}
@@ -0,0 +1,69 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTags(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+		work       = Tag{ID: 1, Name: "work"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1)), rel.InString("name", []string{"work", "home"}))).Result([]Tag{work})
+		repository.ExpectInsert().For(&Tag{UserID: 1, Name: "home"})
+		repository.ExpectDeleteAny(rel.From("todo_tags").Where(rel.Eq("todo_id", uint(1)))).Success()
+		repository.ExpectInsertAll().ForType("[]todos.TodoTag")
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.SetTags(ctx, &todo, []string{"work", " home ", "work"}))
+	assert.Equal(t, []string{"work", "home"}, todo.TagNames())
+	assert.Equal(t, work.ID, todo.Tags[0].TagID)
+	assert.NotEmpty(t, todo.Tags[1].TagID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSetTags_empty(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", Tags: []TodoTag{{TodoID: 1, TagID: 1}}}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectDeleteAny(rel.From("todo_tags").Where(rel.Eq("todo_id", uint(1)))).Success()
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.SetTags(ctx, &todo, nil))
+	assert.Empty(t, todo.Tags)
+	assert.Equal(t, 1, todo.LockVersion)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSetTags_blank(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+	)
+
+	assert.Equal(t, ErrTagNameBlank, service.SetTags(ctx, &todo, []string{"work", " "}))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package todos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrTagNameBlank validation error.
+	ErrTagNameBlank = validation.New("name", "blank", "Tag name can't be blank")
+	// ErrTagNameTaken validation error.
+	ErrTagNameTaken = errors.New("Tag name has already been taken")
+)
+
+// Tag respresent a record stored in tags table.
+type Tag struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"-"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate tag.
+func (t Tag) Validate() error {
+	var errs validation.Errors
+
+	if len(t.Name) == 0 {
+		errs.Add(ErrTagNameBlank)
+	}
+
+	return errs.Err()
+}
+
+// TodoTag respresent a record stored in todo_tags table, which links todo with its tags.
+type TodoTag struct {
+	ID     uint
+	TodoID uint
+	TagID  uint
+	Tag    Tag
+}
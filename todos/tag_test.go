@@ -0,0 +1,21 @@
+package todos
+
+import (
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTag_Validate(t *testing.T) {
+	var tag Tag
+
+	t.Run("name is blank", func(t *testing.T) {
+		assert.Equal(t, validation.Errors{ErrTagNameBlank}, tag.Validate())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		tag.Name = "work"
+		assert.Nil(t, tag.Validate())
+	})
+}
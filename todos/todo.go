@@ -4,36 +4,192 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
 	"time"
+	"unicode/utf8"
+
+	"github.com/Fs02/go-todo-backend/recurrence"
+	"github.com/Fs02/go-todo-backend/validation"
 )
 
 var (
 	// TodoURLPrefix to be returned when encoding todo.
 	TodoURLPrefix = os.Getenv("URL") + "todos/"
 	// ErrTodoTitleBlank validation error.
-	ErrTodoTitleBlank = errors.New("Title can't be blank")
+	ErrTodoTitleBlank = validation.New("title", "blank", "Title can't be blank")
+	// ErrTodoTitleTooLong validation error.
+	ErrTodoTitleTooLong = validation.New("title", "too_long", "Title can't be longer than 255 characters")
+	// ErrTodoOrderOutOfRange validation error.
+	ErrTodoOrderOutOfRange = validation.New("order", "out_of_range", "Order must be between 0 and 2147483647")
+	// ErrTodoDueBeforeCreated validation error.
+	ErrTodoDueBeforeCreated = validation.New("due_at", "before_created", "Due date can't be before created date")
+	// ErrTodoConflict returned when todo is modified since it's loaded.
+	ErrTodoConflict = errors.New("Todo has been modified, reload it and try again")
+	// ErrTodoRecurrenceInvalid validation error.
+	ErrTodoRecurrenceInvalid = validation.New("recurrence", "invalid", "Recurrence must be a valid rule such as FREQ=WEEKLY;BYDAY=MO,FR")
+	// ErrTodoListNotFound validation error.
+	ErrTodoListNotFound = validation.New("list_id", "not_found", "List not found")
+
+	// now is used to determine the current time, overridable for testing.
+	now = time.Now
+	// maxTitleLength is the length of title column.
+	maxTitleLength = 255
 )
 
 // Todo respresent a record stored in todos table.
 type Todo struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	Order     int       `json:"order"`
-	Completed bool      `json:"completed"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint            `json:"id"`
+	UserID     uint            `json:"-"`
+	ListID     *uint           `json:"list_id,omitempty"`
+	Title      string          `json:"title"`
+	Order      int             `json:"order"`
+	Completed  bool            `json:"completed"`
+	Priority   Priority        `json:"priority"`
+	DueAt      *time.Time      `json:"due_at,omitempty"`
+	Recurrence string          `json:"recurrence,omitempty"`
+	Tags       []TodoTag       `json:"-"`
+	Items      []ChecklistItem `json:"-"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	// Snippet of title as escaped html with matched keyword wrapped in mark tag, only set by search with highlight.
+	Snippet string `db:"-" json:"snippet,omitempty"`
+	// DeletedAt marks todo as trashed, rel uses it to soft delete todo and exclude trashed todos from queries.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// LockVersion is incremented by rel on every update, rel only updates or deletes todo if its version is unchanged.
+	LockVersion int `json:"-"`
 }
 
-// Validate todo.
+// Validate todo, every invalid field is reported.
 func (t Todo) Validate() error {
-	var err error
+	var errs validation.Errors
+
 	switch {
 	case len(t.Title) == 0:
-		err = ErrTodoTitleBlank
+		errs.Add(ErrTodoTitleBlank)
+	case utf8.RuneCountInString(t.Title) > maxTitleLength:
+		errs.Add(ErrTodoTitleTooLong)
+	}
+
+	if !t.Priority.Valid() {
+		errs.Add(ErrTodoPriorityInvalid)
+	}
+
+	// order is stored as 32 bit integer.
+	if t.Order < 0 || t.Order > math.MaxInt32 {
+		errs.Add(ErrTodoOrderOutOfRange)
+	}
+
+	if t.DueAt != nil && t.DueAt.Before(t.createdAt()) {
+		errs.Add(ErrTodoDueBeforeCreated)
 	}
 
-	return err
+	if t.Recurrence != "" && !validRecurrence(t.Recurrence) {
+		errs.Add(ErrTodoRecurrenceInvalid)
+	}
+
+	return errs.Err()
+}
+
+// NextOccurrence returns the next todo of a recurring todo.
+// The next todo is due at the first occurrence after the current due date that is still in the future.
+func (t Todo) NextOccurrence() (Todo, error) {
+	rule, err := recurrence.Parse(t.Recurrence)
+	if err != nil {
+		return Todo{}, ErrTodoRecurrenceInvalid
+	}
+
+	due := now()
+	if t.DueAt != nil {
+		due = *t.DueAt
+	}
+
+	// skip occurrences that already passed, in case todo is completed late.
+	due = rule.Next(due)
+	for !due.After(now()) {
+		due = rule.Next(due)
+	}
+
+	return Todo{
+		UserID:     t.UserID,
+		ListID:     t.ListID,
+		Title:      t.Title,
+		Order:      t.Order,
+		Priority:   t.Priority,
+		DueAt:      &due,
+		Recurrence: t.Recurrence,
+	}, nil
+}
+
+func validRecurrence(str string) bool {
+	_, err := recurrence.Parse(str)
+	return err == nil
+}
+
+// createdAt returns creation time, or current time if todo is not persisted yet.
+func (t Todo) createdAt() time.Time {
+	if t.CreatedAt.IsZero() {
+		return now()
+	}
+
+	return t.CreatedAt
+}
+
+// ETag returns entity tag of todo's current version.
+func (t Todo) ETag() string {
+	return fmt.Sprintf("%q", strconv.Itoa(t.LockVersion))
+}
+
+// TagNames returns name of preloaded tags.
+func (t Todo) TagNames() []string {
+	if len(t.Tags) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(t.Tags))
+	for i := range t.Tags {
+		names[i] = t.Tags[i].Tag.Name
+	}
+
+	return names
+}
+
+// Progress returns summary of preloaded checklist items, nil if todo doesn't have any item.
+func (t Todo) Progress() *Progress {
+	if len(t.Items) == 0 {
+		return nil
+	}
+
+	progress := Progress{Total: len(t.Items)}
+	for i := range t.Items {
+		if t.Items[i].Completed {
+			progress.Done++
+		}
+	}
+
+	return &progress
+}
+
+// Decode fields of todo from json, fields that are managed by server such as id and version are kept as they are.
+// rel updates todo by its primary key, so id from client would update another todo.
+func (t *Todo) Decode(data []byte) error {
+	var (
+		loaded = *t
+	)
+
+	if err := json.Unmarshal(data, t); err != nil {
+		return err
+	}
+
+	t.ID = loaded.ID
+	t.UserID = loaded.UserID
+	t.LockVersion = loaded.LockVersion
+	t.CreatedAt = loaded.CreatedAt
+	t.UpdatedAt = loaded.UpdatedAt
+	t.DeletedAt = loaded.DeletedAt
+
+	return nil
 }
 
 // MarshalJSON implement custom marshaller to marshal url.
@@ -42,9 +198,15 @@ func (t Todo) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(struct {
 		Alias
-		URL string `json:"url"`
+		Tags     []string  `json:"tags,omitempty"`
+		Progress *Progress `json:"progress,omitempty"`
+		Version  int       `json:"version"`
+		URL      string    `json:"url"`
 	}{
-		Alias: Alias(t),
-		URL:   fmt.Sprint(TodoURLPrefix, t.ID),
+		Alias:    Alias(t),
+		Version:  t.LockVersion,
+		Tags:     t.TagNames(),
+		Progress: t.Progress(),
+		URL:      fmt.Sprint(TodoURLPrefix, t.ID),
 	})
 }
@@ -2,26 +2,151 @@ package todos
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Fs02/go-todo-backend/validation"
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	today     = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+	yesterday = today.AddDate(0, 0, -1)
+	tomorrow  = today.AddDate(0, 0, 1)
+)
+
 func init() {
 	TodoURLPrefix = "http://localhost:3000/"
+	now = func() time.Time { return today }
 }
 
 func TestTodo_Validate(t *testing.T) {
 	var todo Todo
 
 	t.Run("title is blank", func(t *testing.T) {
-		assert.Equal(t, ErrTodoTitleBlank, todo.Validate())
+		assert.Equal(t, validation.Errors{ErrTodoTitleBlank}, todo.Validate())
 	})
 
 	t.Run("valid", func(t *testing.T) {
 		todo.Title = "Sleep"
 		assert.Nil(t, todo.Validate())
 	})
+
+	t.Run("title is too long", func(t *testing.T) {
+		todo := Todo{Title: strings.Repeat("z", 256)}
+		assert.Equal(t, validation.Errors{ErrTodoTitleTooLong}, todo.Validate())
+	})
+
+	t.Run("title at max length", func(t *testing.T) {
+		todo := Todo{Title: strings.Repeat("ż", 255)}
+		assert.Nil(t, todo.Validate())
+	})
+
+	t.Run("order out of range", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", Order: -1}
+		assert.Equal(t, validation.Errors{ErrTodoOrderOutOfRange}, todo.Validate())
+	})
+
+	t.Run("every invalid field", func(t *testing.T) {
+		todo := Todo{Priority: Priority(10), DueAt: &yesterday, Recurrence: "FREQ=SOMETIMES"}
+		assert.Equal(t, validation.Errors{ErrTodoTitleBlank, ErrTodoPriorityInvalid, ErrTodoDueBeforeCreated, ErrTodoRecurrenceInvalid}, todo.Validate())
+	})
+
+	t.Run("invalid priority", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", Priority: Priority(10)}
+		assert.Equal(t, validation.Errors{ErrTodoPriorityInvalid}, todo.Validate())
+	})
+
+	t.Run("due before created", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", DueAt: &yesterday, CreatedAt: today}
+		assert.Equal(t, validation.Errors{ErrTodoDueBeforeCreated}, todo.Validate())
+	})
+
+	t.Run("due before now when not yet created", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", DueAt: &yesterday}
+		assert.Equal(t, validation.Errors{ErrTodoDueBeforeCreated}, todo.Validate())
+	})
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", Recurrence: "FREQ=SOMETIMES"}
+		assert.Equal(t, validation.Errors{ErrTodoRecurrenceInvalid}, todo.Validate())
+	})
+
+	t.Run("valid recurrence", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", Recurrence: "FREQ=DAILY"}
+		assert.Nil(t, todo.Validate())
+	})
+
+	t.Run("valid due date", func(t *testing.T) {
+		todo := Todo{Title: "Sleep", DueAt: &tomorrow, CreatedAt: today}
+		assert.Nil(t, todo.Validate())
+	})
+}
+
+func TestTodo_NextOccurrence(t *testing.T) {
+	var (
+		lastWeek = today.AddDate(0, 0, -7)
+		nextWeek = today.AddDate(0, 0, 7)
+	)
+
+	tests := []struct {
+		name string
+		todo Todo
+		next Todo
+		err  error
+	}{
+		{
+			name: "without due date",
+			todo: Todo{ID: 1, UserID: 1, Title: "Sleep", Priority: PriorityHigh, Order: 2, Recurrence: "FREQ=DAILY", Completed: true},
+			next: Todo{UserID: 1, Title: "Sleep", Priority: PriorityHigh, Order: 2, Recurrence: "FREQ=DAILY", DueAt: &tomorrow},
+		},
+		{
+			name: "with due date",
+			todo: Todo{ID: 1, Title: "Sleep", Recurrence: "FREQ=WEEKLY", DueAt: &today},
+			next: Todo{Title: "Sleep", Recurrence: "FREQ=WEEKLY", DueAt: &nextWeek},
+		},
+		{
+			name: "completed late skips passed occurrences",
+			todo: Todo{ID: 1, Title: "Sleep", Recurrence: "FREQ=DAILY;INTERVAL=3", DueAt: &lastWeek},
+			next: Todo{Title: "Sleep", Recurrence: "FREQ=DAILY;INTERVAL=3", DueAt: &[]time.Time{today.AddDate(0, 0, 2)}[0]},
+		},
+		{
+			name: "invalid recurrence",
+			todo: Todo{ID: 1, Title: "Sleep", Recurrence: "FREQ=SOMETIMES"},
+			err:  ErrTodoRecurrenceInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, err := test.todo.NextOccurrence()
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.next, next)
+		})
+	}
+}
+
+func TestTodo_ETag(t *testing.T) {
+	assert.Equal(t, `"0"`, Todo{ID: 1}.ETag())
+	assert.Equal(t, `"3"`, Todo{ID: 1, LockVersion: 3}.ETag())
+}
+
+func TestTodo_Decode(t *testing.T) {
+	var (
+		todo = Todo{ID: 1, UserID: 1, Title: "Sleep", CreatedAt: yesterday, LockVersion: 3}
+	)
+
+	assert.Nil(t, todo.Decode([]byte(`{"id": 2, "title": "Wake", "created_at": "2020-06-28T00:00:00Z", "deleted_at": "2020-06-28T00:00:00Z"}`)))
+	assert.Equal(t, Todo{ID: 1, UserID: 1, Title: "Wake", CreatedAt: yesterday, LockVersion: 3}, todo)
+}
+
+func TestTodo_Decode_invalid(t *testing.T) {
+	var (
+		todo Todo
+	)
+
+	assert.Equal(t, ErrTodoPriorityInvalid, todo.Decode([]byte(`{"priority": "critical"}`)))
 }
 
 func TestTodo_MarshalJSON(t *testing.T) {
@@ -40,6 +165,88 @@ func TestTodo_MarshalJSON(t *testing.T) {
 		"title": "Sleep",
 		"completed": true,
 		"order": 0,
+		"priority": "normal",
+		"version": 0,
+		"url": "http://localhost:3000/1",
+		"created_at": "0001-01-01T00:00:00Z",
+		"updated_at": "0001-01-01T00:00:00Z"
+	}`, string(encoded))
+}
+
+func TestTodo_MarshalJSON_dueAt(t *testing.T) {
+	var (
+		todo = Todo{
+			ID:    1,
+			Title: "Sleep",
+			DueAt: &tomorrow,
+		}
+		encoded, err = json.Marshal(todo)
+	)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"title": "Sleep",
+		"completed": false,
+		"order": 0,
+		"priority": "normal",
+		"version": 0,
+		"due_at": "2020-06-29T00:00:00Z",
+		"url": "http://localhost:3000/1",
+		"created_at": "0001-01-01T00:00:00Z",
+		"updated_at": "0001-01-01T00:00:00Z"
+	}`, string(encoded))
+}
+
+func TestTodo_MarshalJSON_tags(t *testing.T) {
+	var (
+		todo = Todo{
+			ID:    1,
+			Title: "Sleep",
+			Tags: []TodoTag{
+				{TodoID: 1, TagID: 1, Tag: Tag{ID: 1, Name: "home"}},
+			},
+		}
+		encoded, err = json.Marshal(todo)
+	)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"title": "Sleep",
+		"completed": false,
+		"order": 0,
+		"priority": "normal",
+		"version": 0,
+		"tags": ["home"],
+		"url": "http://localhost:3000/1",
+		"created_at": "0001-01-01T00:00:00Z",
+		"updated_at": "0001-01-01T00:00:00Z"
+	}`, string(encoded))
+}
+
+func TestTodo_MarshalJSON_progress(t *testing.T) {
+	var (
+		todo = Todo{
+			ID:    1,
+			Title: "Sleep",
+			Items: []ChecklistItem{
+				{ID: 1, TodoID: 1, Title: "Brush teeth", Completed: true},
+				{ID: 2, TodoID: 1, Title: "Floss"},
+			},
+		}
+		encoded, err = json.Marshal(todo)
+	)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"title": "Sleep",
+		"completed": false,
+		"order": 0,
+		"priority": "normal",
+		"version": 0,
+		"progress": {"done": 1, "total": 2},
 		"url": "http://localhost:3000/1",
 		"created_at": "0001-01-01T00:00:00Z",
 		"updated_at": "0001-01-01T00:00:00Z"
@@ -8,6 +8,8 @@ import (
 	rel "github.com/go-rel/rel"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	todos "github.com/Fs02/go-todo-backend/todos"
 )
 
@@ -16,9 +18,32 @@ type Service struct {
 	mock.Mock
 }
 
+// Bulk provides a mock function with given fields: ctx, results, operations, atomic
+func (_m *Service) Bulk(ctx context.Context, results *[]todos.BulkResult, operations []todos.BulkOperation, atomic bool) error {
+	ret := _m.Called(ctx, results, operations, atomic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]todos.BulkResult, []todos.BulkOperation, bool) error); ok {
+		r0 = rf(ctx, results, operations, atomic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Clear provides a mock function with given fields: ctx
-func (_m *Service) Clear(ctx context.Context) {
-	_m.Called(ctx)
+func (_m *Service) Clear(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // Create provides a mock function with given fields: ctx, todo
@@ -35,9 +60,151 @@ func (_m *Service) Create(ctx context.Context, todo *todos.Todo) error {
 	return r0
 }
 
+// CreateItem provides a mock function with given fields: ctx, todo, item
+func (_m *Service) CreateItem(ctx context.Context, todo *todos.Todo, item *todos.ChecklistItem) error {
+	ret := _m.Called(ctx, todo, item)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo, *todos.ChecklistItem) error); ok {
+		r0 = rf(ctx, todo, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTag provides a mock function with given fields: ctx, tag
+func (_m *Service) CreateTag(ctx context.Context, tag *todos.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cursor provides a mock function with given fields: filter, last
+func (_m *Service) Cursor(filter todos.Filter, last todos.Todo) string {
+	ret := _m.Called(filter, last)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(todos.Filter, todos.Todo) string); ok {
+		r0 = rf(filter, last)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, todo
-func (_m *Service) Delete(ctx context.Context, todo *todos.Todo) {
-	_m.Called(ctx, todo)
+func (_m *Service) Delete(ctx context.Context, todo *todos.Todo) error {
+	ret := _m.Called(ctx, todo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo) error); ok {
+		r0 = rf(ctx, todo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteItem provides a mock function with given fields: ctx, todo, item
+func (_m *Service) DeleteItem(ctx context.Context, todo *todos.Todo, item *todos.ChecklistItem) error {
+	ret := _m.Called(ctx, todo, item)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo, *todos.ChecklistItem) error); ok {
+		r0 = rf(ctx, todo, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTag provides a mock function with given fields: ctx, tag
+func (_m *Service) DeleteTag(ctx context.Context, tag *todos.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Move provides a mock function with given fields: ctx, todo, position
+func (_m *Service) Move(ctx context.Context, todo *todos.Todo, position todos.Position) error {
+	ret := _m.Called(ctx, todo, position)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo, todos.Position) error); ok {
+		r0 = rf(ctx, todo, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MoveList provides a mock function with given fields: ctx, listID, moveTo
+func (_m *Service) MoveList(ctx context.Context, listID uint, moveTo uint) error {
+	ret := _m.Called(ctx, listID, moveTo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, listID, moveTo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Purge provides a mock function with given fields: ctx, retention
+func (_m *Service) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	ret := _m.Called(ctx, retention)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int); ok {
+		r0 = rf(ctx, retention)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, todo
+func (_m *Service) Restore(ctx context.Context, todo *todos.Todo) error {
+	ret := _m.Called(ctx, todo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo) error); ok {
+		r0 = rf(ctx, todo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // Search provides a mock function with given fields: ctx, _a1, filter
@@ -54,6 +221,76 @@ func (_m *Service) Search(ctx context.Context, _a1 *[]todos.Todo, filter todos.F
 	return r0
 }
 
+// SearchItems provides a mock function with given fields: ctx, items, todo
+func (_m *Service) SearchItems(ctx context.Context, items *[]todos.ChecklistItem, todo todos.Todo) error {
+	ret := _m.Called(ctx, items, todo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]todos.ChecklistItem, todos.Todo) error); ok {
+		r0 = rf(ctx, items, todo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTags provides a mock function with given fields: ctx, tags
+func (_m *Service) SearchTags(ctx context.Context, tags *[]todos.Tag) error {
+	ret := _m.Called(ctx, tags)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]todos.Tag) error); ok {
+		r0 = rf(ctx, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTrash provides a mock function with given fields: ctx, _a1
+func (_m *Service) SearchTrash(ctx context.Context, _a1 *[]todos.Todo) error {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]todos.Todo) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTags provides a mock function with given fields: ctx, todo, names
+func (_m *Service) SetTags(ctx context.Context, todo *todos.Todo, names []string) error {
+	ret := _m.Called(ctx, todo, names)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo, []string) error); ok {
+		r0 = rf(ctx, todo, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TrashList provides a mock function with given fields: ctx, listID
+func (_m *Service) TrashList(ctx context.Context, listID uint) error {
+	ret := _m.Called(ctx, listID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, listID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, todo, changes
 func (_m *Service) Update(ctx context.Context, todo *todos.Todo, changes rel.Changeset) error {
 	ret := _m.Called(ctx, todo, changes)
@@ -67,3 +304,17 @@ func (_m *Service) Update(ctx context.Context, todo *todos.Todo, changes rel.Cha
 
 	return r0
 }
+
+// UpdateItem provides a mock function with given fields: ctx, todo, item, changes
+func (_m *Service) UpdateItem(ctx context.Context, todo *todos.Todo, item *todos.ChecklistItem, changes rel.Changeset) error {
+	ret := _m.Called(ctx, todo, item, changes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *todos.Todo, *todos.ChecklistItem, rel.Changeset) error); ok {
+		r0 = rf(ctx, todo, item, changes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
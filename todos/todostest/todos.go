@@ -31,6 +31,13 @@ func MockSearch(result []todos.Todo, filter todos.Filter, err error) MockFunc {
 	}
 }
 
+// MockCursor util.
+func MockCursor(cursor string) MockFunc {
+	return func(service *Service) {
+		service.On("Cursor", mock.Anything, mock.Anything).Return(cursor)
+	}
+}
+
 // MockCreate util.
 func MockCreate(result todos.Todo, err error) MockFunc {
 	return func(service *Service) {
@@ -58,15 +65,165 @@ func MockUpdate(result todos.Todo, err error) MockFunc {
 }
 
 // MockClear util.
-func MockClear() MockFunc {
+func MockClear(err error) MockFunc {
 	return func(service *Service) {
-		service.On("Clear", mock.Anything)
+		service.On("Clear", mock.Anything).Return(err)
+	}
+}
+
+// MockTrashList util.
+func MockTrashList(listID uint, err error) MockFunc {
+	return func(service *Service) {
+		service.On("TrashList", mock.Anything, listID).Return(err)
+	}
+}
+
+// MockMoveList util.
+func MockMoveList(listID uint, moveTo uint, err error) MockFunc {
+	return func(service *Service) {
+		service.On("MoveList", mock.Anything, listID, moveTo).Return(err)
 	}
 }
 
 // MockDelete util.
-func MockDelete() MockFunc {
+func MockDelete(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Delete", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockMove util.
+func MockMove(result todos.Todo, position todos.Position, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Move", mock.Anything, mock.Anything, position).
+			Return(func(ctx context.Context, out *todos.Todo, position todos.Position) error {
+				if result.ID != out.ID {
+					panic("inconsistent id")
+				}
+
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockBulk util.
+func MockBulk(result []todos.BulkResult, operations []todos.BulkOperation, atomic bool, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Bulk", mock.Anything, mock.Anything, operations, atomic).
+			Return(func(ctx context.Context, out *[]todos.BulkResult, operations []todos.BulkOperation, atomic bool) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockSearchTrash util.
+func MockSearchTrash(result []todos.Todo, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchTrash", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]todos.Todo) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockRestore util.
+func MockRestore(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Restore", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *todos.Todo) error {
+				out.DeletedAt = nil
+				return err
+			})
+	}
+}
+
+// MockSearchTags util.
+func MockSearchTags(result []todos.Tag, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchTags", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]todos.Tag) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreateTag util.
+func MockCreateTag(result todos.Tag, err error) MockFunc {
+	return func(service *Service) {
+		service.On("CreateTag", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *todos.Tag) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDeleteTag util.
+func MockDeleteTag(err error) MockFunc {
+	return func(service *Service) {
+		service.On("DeleteTag", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockSetTags util.
+func MockSetTags(names []string, result todos.Todo, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SetTags", mock.Anything, mock.Anything, names).
+			Return(func(ctx context.Context, out *todos.Todo, names []string) error {
+				if result.ID != out.ID {
+					panic("inconsistent id")
+				}
+
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockSearchItems util.
+func MockSearchItems(result []todos.ChecklistItem, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchItems", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]todos.ChecklistItem, todo todos.Todo) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreateItem util.
+func MockCreateItem(result todos.ChecklistItem, err error) MockFunc {
+	return func(service *Service) {
+		service.On("CreateItem", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, todo *todos.Todo, out *todos.ChecklistItem) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockUpdateItem util.
+func MockUpdateItem(result todos.ChecklistItem, err error) MockFunc {
+	return func(service *Service) {
+		service.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, todo *todos.Todo, out *todos.ChecklistItem, changeset rel.Changeset) error {
+				if result.ID != out.ID {
+					panic("inconsistent id")
+				}
+
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDeleteItem util.
+func MockDeleteItem(err error) MockFunc {
 	return func(service *Service) {
-		service.On("Delete", mock.Anything, mock.Anything)
+		service.On("DeleteItem", mock.Anything, mock.Anything, mock.Anything).Return(err)
 	}
 }
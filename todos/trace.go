@@ -0,0 +1,155 @@
+package todos
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+)
+
+// traced service starts a span around every operation, so time spent in the service shows up in the request's trace.
+type traced struct {
+	Service
+}
+
+func (t traced) Search(ctx context.Context, todos *[]Todo, filter Filter) error {
+	ctx, span := tracing.Start(ctx, "todos.Search")
+	defer span.End()
+
+	return span.Record(t.Service.Search(ctx, todos, filter))
+}
+
+func (t traced) Create(ctx context.Context, todo *Todo) error {
+	ctx, span := tracing.Start(ctx, "todos.Create")
+	defer span.End()
+
+	return span.Record(t.Service.Create(ctx, todo))
+}
+
+func (t traced) Update(ctx context.Context, todo *Todo, changes rel.Changeset) error {
+	ctx, span := tracing.Start(ctx, "todos.Update")
+	defer span.End()
+
+	return span.Record(t.Service.Update(ctx, todo, changes))
+}
+
+func (t traced) Delete(ctx context.Context, todo *Todo) error {
+	ctx, span := tracing.Start(ctx, "todos.Delete")
+	defer span.End()
+
+	return span.Record(t.Service.Delete(ctx, todo))
+}
+
+func (t traced) Move(ctx context.Context, todo *Todo, position Position) error {
+	ctx, span := tracing.Start(ctx, "todos.Move")
+	defer span.End()
+
+	return span.Record(t.Service.Move(ctx, todo, position))
+}
+
+func (t traced) Bulk(ctx context.Context, results *[]BulkResult, operations []BulkOperation, atomic bool) error {
+	ctx, span := tracing.Start(ctx, "todos.Bulk")
+	defer span.End()
+
+	return span.Record(t.Service.Bulk(ctx, results, operations, atomic))
+}
+
+func (t traced) Clear(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "todos.Clear")
+	defer span.End()
+
+	return span.Record(t.Service.Clear(ctx))
+}
+
+func (t traced) TrashList(ctx context.Context, listID uint) error {
+	ctx, span := tracing.Start(ctx, "todos.TrashList")
+	defer span.End()
+
+	return span.Record(t.Service.TrashList(ctx, listID))
+}
+
+func (t traced) MoveList(ctx context.Context, listID uint, moveTo uint) error {
+	ctx, span := tracing.Start(ctx, "todos.MoveList")
+	defer span.End()
+
+	return span.Record(t.Service.MoveList(ctx, listID, moveTo))
+}
+
+func (t traced) SearchTrash(ctx context.Context, todos *[]Todo) error {
+	ctx, span := tracing.Start(ctx, "todos.SearchTrash")
+	defer span.End()
+
+	return span.Record(t.Service.SearchTrash(ctx, todos))
+}
+
+func (t traced) Restore(ctx context.Context, todo *Todo) error {
+	ctx, span := tracing.Start(ctx, "todos.Restore")
+	defer span.End()
+
+	return span.Record(t.Service.Restore(ctx, todo))
+}
+
+func (t traced) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	ctx, span := tracing.Start(ctx, "todos.Purge")
+	defer span.End()
+
+	count, err := t.Service.Purge(ctx, retention)
+	return count, span.Record(err)
+}
+
+func (t traced) SearchTags(ctx context.Context, tags *[]Tag) error {
+	ctx, span := tracing.Start(ctx, "todos.SearchTags")
+	defer span.End()
+
+	return span.Record(t.Service.SearchTags(ctx, tags))
+}
+
+func (t traced) CreateTag(ctx context.Context, tag *Tag) error {
+	ctx, span := tracing.Start(ctx, "todos.CreateTag")
+	defer span.End()
+
+	return span.Record(t.Service.CreateTag(ctx, tag))
+}
+
+func (t traced) DeleteTag(ctx context.Context, tag *Tag) error {
+	ctx, span := tracing.Start(ctx, "todos.DeleteTag")
+	defer span.End()
+
+	return span.Record(t.Service.DeleteTag(ctx, tag))
+}
+
+func (t traced) SetTags(ctx context.Context, todo *Todo, names []string) error {
+	ctx, span := tracing.Start(ctx, "todos.SetTags")
+	defer span.End()
+
+	return span.Record(t.Service.SetTags(ctx, todo, names))
+}
+
+func (t traced) SearchItems(ctx context.Context, items *[]ChecklistItem, todo Todo) error {
+	ctx, span := tracing.Start(ctx, "todos.SearchItems")
+	defer span.End()
+
+	return span.Record(t.Service.SearchItems(ctx, items, todo))
+}
+
+func (t traced) CreateItem(ctx context.Context, todo *Todo, item *ChecklistItem) error {
+	ctx, span := tracing.Start(ctx, "todos.CreateItem")
+	defer span.End()
+
+	return span.Record(t.Service.CreateItem(ctx, todo, item))
+}
+
+func (t traced) UpdateItem(ctx context.Context, todo *Todo, item *ChecklistItem, changes rel.Changeset) error {
+	ctx, span := tracing.Start(ctx, "todos.UpdateItem")
+	defer span.End()
+
+	return span.Record(t.Service.UpdateItem(ctx, todo, item, changes))
+}
+
+func (t traced) DeleteItem(ctx context.Context, todo *Todo, item *ChecklistItem) error {
+	ctx, span := tracing.Start(ctx, "todos.DeleteItem")
+	defer span.End()
+
+	return span.Record(t.Service.DeleteItem(ctx, todo, item))
+}
@@ -0,0 +1,42 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraced(t *testing.T) {
+	var (
+		exporter   = tracing.NewMemory()
+		ctx, span  = tracing.Start(users.NewContext(context.TODO(), 1), "POST /todos/")
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		todo       = Todo{Title: "Sleep"}
+		invalid    = Todo{}
+	)
+
+	tracing.Default.SetExporter(exporter)
+	defer tracing.Default.SetExporter(nil)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().For(&todo)
+	})
+
+	assert.Nil(t, service.Create(ctx, &todo))
+	assert.Equal(t, ErrTodoTitleBlank.Error(), service.Create(ctx, &invalid).Error())
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "todos.Create", spans[0].Name)
+	assert.Equal(t, span.Context().TraceID, spans[0].Context.TraceID)
+	assert.Equal(t, span.Context().SpanID, spans[0].Parent)
+	assert.Empty(t, spans[0].Error)
+	assert.Equal(t, ErrTodoTitleBlank.Error(), spans[1].Error)
+
+	repository.AssertExpectations(t)
+}
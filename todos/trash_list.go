@@ -0,0 +1,43 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/go-rel/rel"
+)
+
+type trashList struct {
+	repository rel.Repository
+	bus        *events.Bus
+}
+
+// TrashList moves todos of the list that's going to be deleted to trash of their creators, the todos are detached from the list,
+// including the ones that are already trashed, so they're kept after the list is deleted and can be restored without the list.
+// cleared event is published to each creator, as the todos no longer belong to the list.
+func (t trashList) TrashList(ctx context.Context, listID uint) error {
+	return events.Transaction(ctx, t.repository, func(ctx context.Context) error {
+		var (
+			todos []Todo
+			query = rel.Select("id", "user_id", "list_id").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+		)
+
+		if err := t.repository.FindAll(ctx, &todos, query); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if len(todos) > 0 {
+			if _, err := t.repository.UpdateAny(ctx, rel.From("todos").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")),
+				rel.Set("deleted_at", now()), rel.Inc("lock_version")); err != nil {
+				return dberr.Classify(err)
+			}
+		}
+
+		if _, err := t.repository.UpdateAny(ctx, rel.From("todos").Where(rel.Eq("list_id", listID)), rel.Set("list_id", nil)); err != nil {
+			return dberr.Classify(err)
+		}
+
+		return t.bus.Publish(ctx, detachedEvents(todos)...)
+	})
+}
@@ -0,0 +1,87 @@
+package todos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashList(t *testing.T) {
+	var (
+		listID     = uint(3)
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		bus        = events.NewBus(events.DefaultBufferSize)
+		service    = New(repository, nil, bus)
+		sub, _, _  = bus.Subscribe(func(events.Event) bool { return true }, 0)
+		query      = rel.Select("id", "user_id", "list_id").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{{ID: 1, UserID: 1, ListID: &listID}, {ID: 2, UserID: 2, ListID: &listID}, {ID: 4, UserID: 1, ListID: &listID}})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")), rel.Set("deleted_at", today), rel.Inc("lock_version")).UpdatedCount(3)
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("list_id", listID)), rel.Set("list_id", nil)).UpdatedCount(4)
+	})
+
+	assert.Nil(t, service.TrashList(ctx, listID))
+
+	// todos no longer belong to the list, so each creator is notified of their own todos.
+	event := <-sub.Events()
+	assert.Equal(t, events.TodosCleared, event.Type)
+	assert.Equal(t, uint(1), event.UserID)
+	assert.Nil(t, event.ListID)
+	assert.JSONEq(t, `{"ids":[1,4]}`, string(event.Data))
+
+	event = <-sub.Events()
+	assert.Equal(t, events.TodosCleared, event.Type)
+	assert.Equal(t, uint(2), event.UserID)
+	assert.Nil(t, event.ListID)
+	assert.JSONEq(t, `{"ids":[2]}`, string(event.Data))
+
+	repository.AssertExpectations(t)
+}
+
+func TestTrashList_trashed(t *testing.T) {
+	var (
+		listID     = uint(3)
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		query      = rel.Select("id", "user_id", "list_id").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+	)
+
+	// todos that are already trashed are still detached, so they're kept after the list is deleted.
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).Result([]Todo{})
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("list_id", listID)), rel.Set("list_id", nil)).UpdatedCount(2)
+	})
+
+	assert.Nil(t, service.TrashList(ctx, listID))
+
+	repository.AssertExpectations(t)
+}
+
+func TestTrashList_error(t *testing.T) {
+	var (
+		listID     = uint(3)
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		query      = rel.Select("id", "user_id", "list_id").Where(rel.Eq("list_id", listID), rel.Nil("deleted_at")).SortAsc("id").Lock("FOR UPDATE")
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(query).ConnectionClosed()
+	})
+
+	assert.True(t, errors.Is(service.TrashList(ctx, listID), dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
@@ -2,8 +2,12 @@ package todos
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
 	"github.com/Fs02/go-todo-backend/scores"
+	"github.com/Fs02/go-todo-backend/tracing"
 	"github.com/go-rel/rel"
 	"go.uber.org/zap"
 )
@@ -11,27 +15,93 @@ import (
 type update struct {
 	repository rel.Repository
 	scores     scores.Service
+	policy     Policy
+	bus        *events.Bus
 }
 
 func (u update) Update(ctx context.Context, todo *Todo, changes rel.Changeset) error {
 	if err := todo.Validate(); err != nil {
-		logger.Warn("validation error", zap.Error(err))
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
 		return err
 	}
 
-	// update score if completed is changed.
-	if changes.FieldChanged("completed") {
-		return u.repository.Transaction(ctx, func(ctx context.Context) error {
-			u.repository.MustUpdate(ctx, todo, changes)
+	// todo is moved to other list.
+	if changes.FieldChanged("list_id") {
+		if err := u.policy.authorizeList(ctx, *todo); err != nil {
+			return err
+		}
+	}
+
+	return events.Transaction(ctx, u.repository, func(ctx context.Context) error {
+		// update score only if completed is changed.
+		if !changes.FieldChanged("completed") {
+			return u.save(ctx, todo, changes)
+		}
+
+		return u.complete(ctx, todo, changes)
+	})
+}
+
+// complete saves change of completed, and earns or loses points.
+func (u update) complete(ctx context.Context, todo *Todo, changes rel.Changeset) error {
+	var (
+		next *Todo
+	)
+
+	// the series of recurring todo continues on the next occurrence,
+	// recurrence is removed from completed todo, so it won't repeat twice when it's uncompleted and completed again.
+	if todo.Completed && todo.Recurrence != "" {
+		occurrence, err := todo.NextOccurrence()
+		if err != nil {
+			return err
+		}
+
+		next = &occurrence
+		todo.Recurrence = ""
+	}
+
+	if err := u.save(ctx, todo, changes); err != nil {
+		return err
+	}
 
-			if todo.Completed {
-				return u.scores.Earn(ctx, "todo completed", 1)
-			}
+	if next != nil {
+		if err := u.repository.Insert(ctx, next); err != nil {
+			return dberr.Classify(err)
+		}
+
+		if err := u.bus.Publish(ctx, todoEvent(events.TodoCreated, *next)); err != nil {
+			return err
+		}
+	}
+
+	if todo.Completed {
+		return u.scores.Earn(ctx, "todo completed", 1)
+	}
+
+	return u.scores.Earn(ctx, "todo uncompleted", -2)
+}
+
+// save updates todo only if it's still at the version it's loaded, rel adds the version to where clause of the update.
+// it must be called within a transaction, so the change is published after it's committed.
+func (u update) save(ctx context.Context, todo *Todo, changes rel.Changeset) error {
+	if err := u.repository.Update(ctx, todo, changes); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			logger.Warn("conflict error", zap.Error(ErrTodoConflict), zap.Uint("id", todo.ID), zap.Int("version", todo.LockVersion), tracing.Field(ctx))
+			return ErrTodoConflict
+		}
+
+		return dberr.Classify(err)
+	}
+
+	return u.bus.Publish(ctx, todoEvent(events.TodoUpdated, *todo))
+}
 
-			return u.scores.Earn(ctx, "todo uncompleted", -2)
-		})
+// touch increments version of todo whose tags or items are changed, so its etag changes along with them.
+func touch(ctx context.Context, repository rel.Repository, todo *Todo) error {
+	if _, err := repository.UpdateAny(ctx, rel.From("todos").Where(rel.Eq("id", todo.ID)), rel.Inc("lock_version")); err != nil {
+		return dberr.Classify(err)
 	}
 
-	u.repository.MustUpdate(ctx, todo, changes)
+	todo.LockVersion++
 	return nil
 }
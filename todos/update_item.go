@@ -0,0 +1,71 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+type updateItem struct {
+	repository rel.Repository
+	update     update
+	bus        *events.Bus
+}
+
+// UpdateItem updates the checklist item, completing the last open item will also complete the todo.
+func (ui updateItem) UpdateItem(ctx context.Context, todo *Todo, item *ChecklistItem, changes rel.Changeset) error {
+	if err := item.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	return events.Transaction(ctx, ui.repository, func(ctx context.Context) error {
+		if err := ui.repository.Update(ctx, item, changes); err != nil {
+			return dberr.Classify(err)
+		}
+
+		for i := range todo.Items {
+			if todo.Items[i].ID == item.ID {
+				todo.Items[i] = *item
+			}
+		}
+
+		complete, err := ui.completesTodo(ctx, todo, item, changes)
+		if err != nil {
+			return err
+		}
+
+		// completing the todo increments its version as well.
+		if !complete {
+			if err := touch(ctx, ui.repository, todo); err != nil {
+				return err
+			}
+
+			return ui.bus.Publish(ctx, todoEvent(events.TodoUpdated, *todo))
+		}
+
+		todoChanges := rel.NewChangeset(todo)
+		todo.Completed = true
+
+		// update publishes the change, including the updated item.
+		return ui.update.Update(ctx, todo, todoChanges)
+	})
+}
+
+// completesTodo reports whether completing the item leaves no open item in the todo.
+func (ui updateItem) completesTodo(ctx context.Context, todo *Todo, item *ChecklistItem, changes rel.Changeset) (bool, error) {
+	if !changes.FieldChanged("completed") || !item.Completed || todo.Completed {
+		return false, nil
+	}
+
+	open, err := ui.repository.Count(ctx, "checklist_items", rel.Eq("todo_id", todo.ID), rel.Eq("completed", false))
+	if err != nil {
+		return false, dberr.Classify(err)
+	}
+
+	return open == 0, nil
+}
@@ -0,0 +1,115 @@
+package todos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/scores/scorestest"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateItem(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		item       = ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"}
+		todo       = Todo{ID: 1, Title: "Sleep", Items: []ChecklistItem{item}}
+		changes    = rel.NewChangeset(&item)
+	)
+
+	item.Title = "Floss"
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.ChecklistItem")
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.UpdateItem(ctx, &todo, &item, changes))
+	assert.Equal(t, "Floss", todo.Items[0].Title)
+	assert.Equal(t, 1, todo.LockVersion)
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestUpdateItem_completedWithOpenItems(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		item       = ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"}
+		todo       = Todo{ID: 1, Title: "Sleep", Items: []ChecklistItem{item, {ID: 2, TodoID: 1, Title: "Floss"}}}
+		changes    = rel.NewChangeset(&item)
+	)
+
+	item.Completed = true
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.ChecklistItem")
+		repository.ExpectCount("checklist_items", rel.Eq("todo_id", uint(1)), rel.Eq("completed", false)).Result(1)
+		repository.ExpectUpdateAny(rel.From("todos").Where(rel.Eq("id", uint(1))), rel.Inc("lock_version")).UpdatedCount(1)
+	})
+
+	assert.Nil(t, service.UpdateItem(ctx, &todo, &item, changes))
+	assert.False(t, todo.Completed)
+	assert.Equal(t, &Progress{Done: 1, Total: 2}, todo.Progress())
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestUpdateItem_completeTodo(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		item       = ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"}
+		todo       = Todo{ID: 1, Title: "Sleep", Items: []ChecklistItem{item}}
+		changes    = rel.NewChangeset(&item)
+	)
+
+	item.Completed = true
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.ChecklistItem")
+		repository.ExpectCount("checklist_items", rel.Eq("todo_id", uint(1)), rel.Eq("completed", false)).Result(0)
+		repository.ExpectTransaction(func(repository *reltest.Repository) {
+			scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil)
+			repository.ExpectUpdate().ForType("todos.Todo")
+		})
+	})
+
+	assert.Nil(t, service.UpdateItem(ctx, &todo, &item, changes))
+	assert.True(t, todo.Completed)
+	assert.Equal(t, &Progress{Done: 1, Total: 1}, todo.Progress())
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestUpdateItem_validateError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		item       = ChecklistItem{ID: 1, TodoID: 1, Title: "Brush teeth"}
+		todo       = Todo{ID: 1, Title: "Sleep", Items: []ChecklistItem{item}}
+		changes    = rel.NewChangeset(&item)
+	)
+
+	item.Title = ""
+
+	assert.Equal(t, validation.Errors{ErrChecklistItemTitleBlank}, service.UpdateItem(ctx, &todo, &item, changes))
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
@@ -2,9 +2,14 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/lists"
 	"github.com/Fs02/go-todo-backend/scores/scorestest"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
 	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
@@ -16,14 +21,16 @@ func TestUpdate(t *testing.T) {
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{ID: 1, Title: "Sleep"}
 		changes    = rel.NewChangeset(&todo)
 	)
 
 	todo.Title = "Wake up"
 
-	repository.ExpectUpdate(changes).ForType("todos.Todo")
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.Todo")
+	})
 
 	assert.Nil(t, service.Update(ctx, &todo, changes))
 	assert.NotEmpty(t, todo.ID)
@@ -32,12 +39,75 @@ func TestUpdate(t *testing.T) {
 	scores.AssertExpectations(t)
 }
 
+func TestUpdate_list(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil, nil)
+		listID     = uint(1)
+		todo       = Todo{ID: 1, UserID: 1, Title: "Sleep"}
+		changes    = rel.NewChangeset(&todo)
+	)
+
+	todo.ListID = &listID
+
+	repository.ExpectFind(rel.Eq("list_id", listID), rel.Eq("user_id", uint(1))).Result(lists.Member{ListID: 1, UserID: 1, Role: lists.RoleViewer})
+
+	assert.Equal(t, lists.ErrForbidden, service.Update(ctx, &todo, changes))
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdate_conflict(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", LockVersion: 2}
+		changes    = rel.NewChangeset(&todo)
+	)
+
+	todo.Title = "Wake up"
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.Todo").Error(rel.NotFoundError{})
+	})
+
+	assert.Equal(t, ErrTodoConflict, service.Update(ctx, &todo, changes))
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestUpdate_completedConflict(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", LockVersion: 2}
+		changes    = rel.NewChangeset(&todo)
+	)
+
+	todo.Completed = true
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.Todo").Error(rel.NotFoundError{})
+	})
+
+	assert.Equal(t, ErrTodoConflict, service.Update(ctx, &todo, changes))
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
 func TestUpdate_completed(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{ID: 1, Title: "Sleep"}
 		changes    = rel.NewChangeset(&todo)
 	)
@@ -56,12 +126,37 @@ func TestUpdate_completed(t *testing.T) {
 	scores.AssertExpectations(t)
 }
 
+func TestUpdate_completedRecurring(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		todo       = Todo{ID: 1, Title: "Sleep", Recurrence: "FREQ=DAILY", DueAt: &today}
+		changes    = rel.NewChangeset(&todo)
+	)
+
+	todo.Completed = true
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		scores.On("Earn", mock.Anything, "todo completed", 1).Return(nil)
+		repository.ExpectUpdate(changes).ForContains(Todo{ID: 1, Title: "Sleep", Completed: true, DueAt: &today})
+		repository.ExpectInsert().For(&Todo{Title: "Sleep", Recurrence: "FREQ=DAILY", DueAt: &tomorrow})
+	})
+
+	assert.Nil(t, service.Update(ctx, &todo, changes))
+	assert.Empty(t, todo.Recurrence)
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
 func TestUpdate_uncompleted(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{ID: 1, Title: "Sleep", Completed: true}
 		changes    = rel.NewChangeset(&todo)
 	)
@@ -85,14 +180,38 @@ func TestUpdate_validateError(t *testing.T) {
 		ctx        = context.TODO()
 		repository = reltest.New()
 		scores     = &scorestest.Service{}
-		service    = New(repository, scores)
+		service    = New(repository, scores, nil)
 		todo       = Todo{ID: 1, Title: "Sleep"}
 		changes    = rel.NewChangeset(&todo)
 	)
 
 	todo.Title = ""
 
-	assert.Equal(t, ErrTodoTitleBlank, service.Update(ctx, &todo, changes))
+	assert.Equal(t, validation.Errors{ErrTodoTitleBlank}, service.Update(ctx, &todo, changes))
+
+	repository.AssertExpectations(t)
+	scores.AssertExpectations(t)
+}
+
+func TestUpdate_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		scores     = &scorestest.Service{}
+		service    = New(repository, scores, nil)
+		todo       = Todo{ID: 1, Title: "Sleep"}
+		changes    = rel.NewChangeset(&todo)
+	)
+
+	todo.Title = "Wake up"
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(changes).ForType("todos.Todo").ConnectionClosed()
+	})
+
+	err := service.Update(ctx, &todo, changes)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+	assert.True(t, errors.Is(err, reltest.ErrConnectionClosed))
 
 	repository.AssertExpectations(t)
 	scores.AssertExpectations(t)
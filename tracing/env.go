@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"context"
+	"os"
+)
+
+// ExportEnv exports spans of Default tracer to OTEL_EXPORTER_OTLP_ENDPOINT through OTLP/HTTP, OTEL_SERVICE_NAME names the service
+// in tracing backend and suffix tells processes of the service apart. spans are still started without the endpoint, so log lines carry trace id of the caller.
+// the returned function sends spans that are still queued, it should be called on shutdown.
+func ExportEnv(suffix string) func() error {
+	var (
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		service  = os.Getenv("OTEL_SERVICE_NAME")
+	)
+
+	if endpoint == "" {
+		return func() error { return nil }
+	}
+
+	if service == "" {
+		service = "go-todo-backend"
+	}
+
+	exporter := NewOTLP(endpoint, service+suffix)
+	Default.SetExporter(exporter)
+
+	return func() error {
+		return exporter.Shutdown(context.Background())
+	}
+}
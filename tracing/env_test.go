@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportEnv(t *testing.T) {
+	var (
+		requests = make(chan string, 1)
+		server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- string(body)
+		}))
+	)
+
+	defer server.Close()
+	defer Default.SetExporter(nil)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	shutdown := ExportEnv("-worker")
+
+	_, span := Default.Start(context.TODO(), "jobs.Run")
+	span.End()
+
+	assert.Nil(t, shutdown())
+	assert.Contains(t, <-requests, `"stringValue":"go-todo-backend-worker"`)
+}
+
+func TestExportEnv_withoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	assert.Nil(t, ExportEnv("")())
+	assert.Nil(t, Default.exporter)
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory exporter keeps ended spans in memory, it's meant for tests.
+type Memory struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// Export keeps the span.
+func (m *Memory) Export(span SpanData) {
+	m.mu.Lock()
+	m.spans = append(m.spans, span)
+	m.mu.Unlock()
+}
+
+// Shutdown does nothing, spans are kept until reset.
+func (m *Memory) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns exported spans in the order they're ended.
+func (m *Memory) Spans() []SpanData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]SpanData(nil), m.spans...)
+}
+
+// Reset removes every exported span.
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	m.spans = nil
+	m.mu.Unlock()
+}
+
+// NewMemory exporter.
+func NewMemory() *Memory {
+	return &Memory{}
+}
@@ -0,0 +1,234 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// otlpQueueSize is number of spans waiting to be sent, spans are dropped when the queue is full so requests never wait for exporter.
+	otlpQueueSize = 2048
+	// otlpBatchSize is maximum number of spans sent in one request.
+	otlpBatchSize = 512
+	// otlpInterval is how often queued spans are sent.
+	otlpInterval = 5 * time.Second
+)
+
+// OTLP exporter sends spans in batches to collector through OTLP/HTTP using JSON encoding.
+type OTLP struct {
+	url     string
+	service string
+	client  *http.Client
+	spans   chan SpanData
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Export queues the span to be sent in the next batch.
+func (o *OTLP) Export(span SpanData) {
+	select {
+	case o.spans <- span:
+	default:
+		logger.Warn("span dropped", zap.String("name", span.Name))
+	}
+}
+
+// Shutdown sends every queued span and stops the exporter.
+func (o *OTLP) Shutdown(ctx context.Context) error {
+	o.once.Do(func() {
+		close(o.stop)
+	})
+
+	select {
+	case <-o.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *OTLP) run() {
+	var (
+		ticker = time.NewTicker(otlpInterval)
+		batch  = make([]SpanData, 0, otlpBatchSize)
+	)
+
+	defer close(o.done)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) > 0 {
+			o.send(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case span := <-o.spans:
+			if batch = append(batch, span); len(batch) == otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-o.stop:
+			for {
+				select {
+				case span := <-o.spans:
+					if batch = append(batch, span); len(batch) == otlpBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (o *OTLP) send(spans []SpanData) {
+	body, err := json.Marshal(o.encode(spans))
+	if err != nil {
+		logger.Error("encode spans error", zap.Error(err))
+		return
+	}
+
+	resp, err := o.client.Post(o.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		logger.Warn("export spans error", zap.Error(err), zap.Int("count", len(spans)))
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		logger.Warn("export spans error", zap.Int("status", resp.StatusCode), zap.Int("count", len(spans)))
+	}
+}
+
+// encode spans as ExportTraceServiceRequest, ids are hex encoded as required by OTLP JSON encoding.
+func (o *OTLP) encode(spans []SpanData) otlpRequest {
+	scope := otlpScopeSpans{
+		Scope: otlpScope{Name: "github.com/Fs02/go-todo-backend"},
+		Spans: make([]otlpSpan, len(spans)),
+	}
+
+	for i, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+		}
+
+		if span.Parent.IsValid() {
+			s.ParentSpanID = span.Parent.String()
+		}
+
+		if span.Error != "" {
+			s.Status = &otlpStatus{Code: 2, Message: span.Error}
+		}
+
+		scope.Spans[i] = s
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource:   otlpResource{Attributes: otlpAttributes(map[string]string{"service.name": o.service})},
+			ScopeSpans: []otlpScopeSpans{scope},
+		}},
+	}
+}
+
+// NewOTLP exporter that sends spans to /v1/traces of the endpoint, service identifies the process in tracing backend.
+func NewOTLP(endpoint string, service string) *OTLP {
+	o := &OTLP{
+		url:     strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		spans:   make(chan SpanData, otlpQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go o.run()
+
+	return o
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpAttributes converts attributes sorted by key, so the encoded request is stable.
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]otlpAttribute, len(keys))
+	for i, key := range keys {
+		result[i] = otlpAttribute{Key: key, Value: otlpValue{StringValue: attributes[key]}}
+	}
+
+	return result
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOTLP(t *testing.T) {
+	var (
+		requests = make(chan string, 1)
+		server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "/v1/traces", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			requests <- string(body)
+		}))
+		exporter = NewOTLP(server.URL+"/", "todo")
+		start    = time.Unix(1, 0)
+	)
+
+	defer server.Close()
+
+	exporter.Export(SpanData{
+		Name:       "GET /todos/{ID}",
+		Context:    SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}, Sampled: true},
+		Kind:       KindServer,
+		Start:      start,
+		End:        start.Add(time.Millisecond),
+		Attributes: map[string]string{"http.status_code": "500", "http.method": "GET"},
+		Error:      "Internal Server Error",
+	})
+	exporter.Export(SpanData{
+		Name:    "todos.Search",
+		Context: SpanContext{TraceID: TraceID{1}, SpanID: SpanID{3}, Sampled: true},
+		Parent:  SpanID{2},
+		Kind:    KindInternal,
+		Start:   start,
+		End:     start,
+	})
+
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	assert.JSONEq(t, `{"resourceSpans":[{
+		"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"todo"}}]},
+		"scopeSpans":[{"scope":{"name":"github.com/Fs02/go-todo-backend"},"spans":[
+			{
+				"traceId":"01000000000000000000000000000000","spanId":"0200000000000000","name":"GET /todos/{ID}","kind":2,
+				"startTimeUnixNano":"1000000000","endTimeUnixNano":"1001000000",
+				"attributes":[{"key":"http.method","value":{"stringValue":"GET"}},{"key":"http.status_code","value":{"stringValue":"500"}}],
+				"status":{"code":2,"message":"Internal Server Error"}
+			},
+			{
+				"traceId":"01000000000000000000000000000000","spanId":"0300000000000000","parentSpanId":"0200000000000000","name":"todos.Search","kind":1,
+				"startTimeUnixNano":"1000000000","endTimeUnixNano":"1000000000"
+			}
+		]}]
+	}]}`, <-requests)
+
+	// exporter is stopped, span is neither sent nor blocks the caller.
+	exporter.Export(SpanData{Name: "todos.Search"})
+	assert.Empty(t, requests)
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is header of W3C trace context.
+const TraceparentHeader = "traceparent"
+
+// Extract returns context that carries span context of traceparent header, ctx is returned as is when the header is missing or invalid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := ParseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return ctx
+	}
+
+	return ContextWithRemote(ctx, sc)
+}
+
+// Inject sets traceparent header from span context in ctx, so the called service continues the trace.
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(TraceparentHeader, FormatTraceparent(sc))
+	}
+}
+
+// FormatTraceparent formats span context as version 00 traceparent.
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses traceparent as described by W3C trace context.
+// fields added by future versions are ignored, while version ff and all zeros ids are invalid.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	var (
+		sc    SpanContext
+		parts = strings.Split(strings.TrimSpace(value), "-")
+	)
+
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, false
+	}
+
+	var (
+		version, flags [1]byte
+	)
+
+	if !decodeHex(version[:], parts[0]) || !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) || !decodeHex(flags[:], parts[3]) {
+		return sc, false
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+	return sc, sc.IsValid()
+}
+
+// decodeHex decodes lowercase hex that exactly fills dst.
+func decodeHex(dst []byte, src string) bool {
+	if len(src) != hex.EncodedLen(len(dst)) || strings.ToLower(src) != src {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(src))
+	return err == nil
+}
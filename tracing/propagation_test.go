@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	var (
+		traceID = TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+		spanID  = SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	)
+
+	tests := []struct {
+		name  string
+		value string
+		sc    SpanContext
+		valid bool
+	}{
+		{
+			name:  "sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			sc:    SpanContext{TraceID: traceID, SpanID: spanID, Sampled: true},
+			valid: true,
+		},
+		{
+			name:  "not sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			sc:    SpanContext{TraceID: traceID, SpanID: spanID},
+			valid: true,
+		},
+		{
+			name:  "future version",
+			value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			sc:    SpanContext{TraceID: traceID, SpanID: spanID, Sampled: true},
+			valid: true,
+		},
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name:  "invalid version",
+			value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "extra field of version 00",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			name:  "uppercase",
+			value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "short trace id",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "zero trace id",
+			value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "zero span id",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc, valid := ParseTraceparent(test.value)
+
+			assert.Equal(t, test.valid, valid)
+			if test.valid {
+				assert.Equal(t, test.sc, sc)
+			}
+		})
+	}
+}
+
+func TestExtractInject(t *testing.T) {
+	var (
+		header = http.Header{}
+		out    = http.Header{}
+	)
+
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, span := NewTracer().Start(Extract(context.TODO(), header), "GET /todos/")
+	Inject(ctx, out)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.Context().SpanID.String()+"-01", out.Get("traceparent"))
+	assert.Equal(t, context.TODO(), Extract(context.TODO(), http.Header{}))
+}
@@ -0,0 +1,257 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "tracing")))
+
+	// Default tracer, spans of every package are started from it.
+	Default = NewTracer()
+)
+
+// TraceID identifies a trace across services.
+type TraceID [16]byte
+
+// IsValid reports whether trace id isn't all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns trace id in lowercase hex.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid reports whether span id isn't all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns span id in lowercase hex.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext is the part of a span that's propagated to child spans and other services.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both trace id and span id are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Kind of span, it tells whether the span serves a request, calls other service or just measures internal operation.
+type Kind int
+
+const (
+	// KindInternal span.
+	KindInternal Kind = 1
+	// KindServer span.
+	KindServer Kind = 2
+	// KindClient span.
+	KindClient Kind = 3
+)
+
+// SpanData is the recorded state of an ended span, it's what exporters receive.
+type SpanData struct {
+	Name       string
+	Context    SpanContext
+	Parent     SpanID
+	Kind       Kind
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	Error      string
+}
+
+// Span measures a single operation, it must be ended exactly once.
+type Span struct {
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+	tracer *Tracer
+}
+
+// Context returns span context of the span.
+func (s *Span) Context() SpanContext {
+	return s.data.Context
+}
+
+// SetName replaces name of the span, useful when the name is only known after the operation, such as route of a request.
+func (s *Span) SetName(name string) {
+	s.mu.Lock()
+	s.data.Name = name
+	s.mu.Unlock()
+}
+
+// SetKind sets kind of the span.
+func (s *Span) SetKind(kind Kind) {
+	s.mu.Lock()
+	s.data.Kind = kind
+	s.mu.Unlock()
+}
+
+// SetAttribute sets attribute of the span.
+func (s *Span) SetAttribute(key string, value string) {
+	s.mu.Lock()
+	s.data.Attributes[key] = value
+	s.mu.Unlock()
+}
+
+// Record marks the span as failed when err isn't nil, err is returned as is so it can wrap the returned error.
+func (s *Span) Record(err error) error {
+	if err != nil {
+		s.mu.Lock()
+		s.data.Error = err.Error()
+		s.mu.Unlock()
+	}
+
+	return err
+}
+
+// End ends the span and hands it to the exporter, only sampled span is exported.
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if data.Context.Sampled {
+		s.tracer.export(data)
+	}
+}
+
+// Tracer starts spans and hands ended spans to its exporter.
+type Tracer struct {
+	mu       sync.RWMutex
+	exporter Exporter
+}
+
+// SetExporter replaces exporter of the tracer, spans are dropped when exporter is nil.
+func (t *Tracer) SetExporter(exporter Exporter) {
+	t.mu.Lock()
+	t.exporter = exporter
+	t.mu.Unlock()
+}
+
+// Start starts a span as a child of the span in ctx, or as root of a new trace if ctx doesn't carry any span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	var (
+		parent = SpanContextFromContext(ctx)
+		span   = &Span{
+			data: SpanData{
+				Name:       name,
+				Kind:       KindInternal,
+				Start:      time.Now(),
+				Attributes: make(map[string]string),
+			},
+			tracer: t,
+		}
+	)
+
+	if parent.IsValid() {
+		span.data.Context = SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), Sampled: parent.Sampled}
+		span.data.Parent = parent.SpanID
+	} else {
+		span.data.Context = SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	}
+
+	return context.WithValue(ctx, spanKey, span), span
+}
+
+func (t *Tracer) export(data SpanData) {
+	t.mu.RLock()
+	exporter := t.exporter
+	t.mu.RUnlock()
+
+	if exporter != nil {
+		exporter.Export(data)
+	}
+}
+
+// NewTracer returns tracer without exporter.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Start starts a span from the default tracer.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return Default.Start(ctx, name)
+}
+
+// Exporter sends ended spans to tracing backend.
+type Exporter interface {
+	Export(span SpanData)
+	Shutdown(ctx context.Context) error
+}
+
+type ctxKey int
+
+const (
+	spanKey   ctxKey = 0
+	remoteKey ctxKey = 1
+)
+
+// SpanContextFromContext returns span context of the current span in ctx, or the remote parent extracted from request.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey).(*Span); ok {
+		return span.Context()
+	}
+
+	sc, _ := ctx.Value(remoteKey).(SpanContext)
+	return sc
+}
+
+// ContextWithRemote returns context that carries span context of the caller, spans started from it continue the caller's trace.
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey, sc)
+}
+
+// Field returns zap field of trace id in ctx, so log lines can be joined with the trace.
+// the field is skipped if ctx doesn't carry any span.
+func Field(ctx context.Context) zap.Field {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		return zap.String("trace_id", sc.TraceID.String())
+	}
+
+	return zap.Skip()
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	randomize(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	randomize(id[:])
+	return id
+}
+
+func randomize(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		logger.Error("random id error", zap.Error(err))
+	}
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestTracer_Start(t *testing.T) {
+	var (
+		exporter    = NewMemory()
+		tracer      = NewTracer()
+		ctx, parent = tracer.Start(context.TODO(), "todos.Create")
+		_, child    = tracer.Start(ctx, "rel-insert")
+	)
+
+	tracer.SetExporter(exporter)
+
+	child.SetKind(KindClient)
+	child.SetAttribute("db.system", "postgresql")
+	assert.Equal(t, errors.New("connection refused"), child.Record(errors.New("connection refused")))
+	child.End()
+	child.End()
+
+	parent.SetName("todos.Update")
+	assert.Nil(t, parent.Record(nil))
+	parent.End()
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "rel-insert", spans[0].Name)
+	assert.Equal(t, KindClient, spans[0].Kind)
+	assert.Equal(t, map[string]string{"db.system": "postgresql"}, spans[0].Attributes)
+	assert.Equal(t, "connection refused", spans[0].Error)
+	assert.Equal(t, parent.Context().TraceID, spans[0].Context.TraceID)
+	assert.Equal(t, parent.Context().SpanID, spans[0].Parent)
+	assert.False(t, spans[0].End.Before(spans[0].Start))
+
+	assert.Equal(t, "todos.Update", spans[1].Name)
+	assert.Equal(t, KindInternal, spans[1].Kind)
+	assert.Empty(t, spans[1].Error)
+	assert.True(t, spans[1].Context.IsValid())
+	assert.False(t, spans[1].Parent.IsValid())
+	assert.True(t, spans[1].Context.Sampled)
+
+	exporter.Reset()
+	assert.Empty(t, exporter.Spans())
+}
+
+func TestTracer_Start_remote(t *testing.T) {
+	var (
+		exporter = NewMemory()
+		tracer   = NewTracer()
+		remote   = SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}, Sampled: true}
+		_, span  = tracer.Start(ContextWithRemote(context.TODO(), remote), "GET /todos/")
+	)
+
+	tracer.SetExporter(exporter)
+	span.End()
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, remote.TraceID, spans[0].Context.TraceID)
+	assert.Equal(t, remote.SpanID, spans[0].Parent)
+	assert.NotEqual(t, remote.SpanID, spans[0].Context.SpanID)
+}
+
+func TestTracer_Start_notSampled(t *testing.T) {
+	var (
+		exporter = NewMemory()
+		tracer   = NewTracer()
+		remote   = SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}}
+		_, span  = tracer.Start(ContextWithRemote(context.TODO(), remote), "GET /todos/")
+	)
+
+	tracer.SetExporter(exporter)
+	span.End()
+
+	assert.Equal(t, remote.TraceID, span.Context().TraceID)
+	assert.Empty(t, exporter.Spans())
+}
+
+func TestField(t *testing.T) {
+	ctx, span := NewTracer().Start(context.TODO(), "todos.Search")
+
+	assert.Equal(t, zap.String("trace_id", span.Context().TraceID.String()), Field(ctx))
+	assert.Equal(t, zap.Skip(), Field(context.TODO()))
+}
@@ -0,0 +1,23 @@
+package users
+
+import (
+	"context"
+)
+
+type ctxKey int
+
+const (
+	userIDKey ctxKey = 0
+)
+
+// NewContext returns context that carries id of the user making the request.
+func NewContext(ctx context.Context, id uint) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// FromContext returns id of the user making the request, zero if context doesn't carry any user.
+// zero doesn't match any record, so queries scoped by it returns nothing.
+func FromContext(ctx context.Context) uint {
+	id, _ := ctx.Value(userIDKey).(uint)
+	return id
+}
@@ -0,0 +1,17 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	var (
+		ctx = context.TODO()
+	)
+
+	assert.Equal(t, uint(0), FromContext(ctx))
+	assert.Equal(t, uint(1), FromContext(NewContext(ctx, 1)))
+}
@@ -0,0 +1,32 @@
+package users
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+type provision struct {
+	repository rel.Repository
+	// provisioned holds id of users that are known to exist, so a user is only inserted once by each process.
+	provisioned *sync.Map
+}
+
+// Provision inserts user authenticated by the identity provider if it doesn't exist yet, existing user is kept as is.
+// records of the user reference users table, so the user must exist before any of them is inserted.
+func (p provision) Provision(ctx context.Context, user *User) error {
+	if _, ok := p.provisioned.Load(user.ID); ok {
+		return nil
+	}
+
+	// primary value of ignored insert isn't returned, so the user is inserted from a copy.
+	record := *user
+	if err := p.repository.Insert(ctx, &record, rel.OnConflictKeyIgnore("id")); err != nil {
+		return dberr.Classify(err)
+	}
+
+	p.provisioned.Store(user.ID, struct{}{})
+	return nil
+}
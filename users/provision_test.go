@@ -0,0 +1,50 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvision(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		user       = User{ID: 2, Name: "John", Email: "john@example.com"}
+	)
+
+	repository.ExpectInsert(rel.OnConflictKeyIgnore("id")).For(&User{ID: 2, Name: "John", Email: "john@example.com"})
+
+	assert.Nil(t, service.Provision(ctx, &user))
+	assert.Equal(t, uint(2), user.ID)
+
+	// user is only inserted once, the expectation above would fail if it's inserted again.
+	assert.Nil(t, service.Provision(ctx, &user))
+
+	repository.AssertExpectations(t)
+}
+
+func TestProvision_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		user       = User{ID: 2}
+	)
+
+	repository.ExpectInsert(rel.OnConflictKeyIgnore("id")).For(&User{ID: 2}).ConnectionClosed()
+	repository.ExpectInsert(rel.OnConflictKeyIgnore("id")).For(&User{ID: 2})
+
+	assert.True(t, errors.Is(service.Provision(ctx, &user), dberr.ErrUnavailable))
+
+	// failed insert isn't remembered, so it's retried by the next request.
+	assert.Nil(t, service.Provision(ctx, &user))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+package users
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-rel/rel"
+)
+
+//go:generate mockery --name=Service --case=underscore --output userstest --outpkg userstest
+
+// Service instance for user's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Provision(ctx context.Context, user *User) error
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	provision
+}
+
+var _ Service = (*service)(nil)
+
+// New users service.
+func New(repository rel.Repository) Service {
+	return service{
+		provision: provision{repository: repository, provisioned: &sync.Map{}},
+	}
+}
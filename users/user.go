@@ -0,0 +1,14 @@
+package users
+
+import (
+	"time"
+)
+
+// User respresent a record stored in users table.
+type User struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
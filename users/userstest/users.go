@@ -0,0 +1,25 @@
+package userstest
+
+import (
+	users "github.com/Fs02/go-todo-backend/users"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock user functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockProvision util.
+func MockProvision(user users.User, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Provision", mock.Anything, &user).Return(err)
+	}
+}
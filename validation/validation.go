@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error of a single invalid field, code identifies the problem for client while message is meant for human.
+type Error struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// Error returns message of the error.
+func (e Error) Error() string {
+	return e.Message
+}
+
+// New validation error of the field, the error is comparable so it can be used as sentinel error.
+func New(field string, code string, message string) error {
+	return Error{Field: field, Code: code, Message: message}
+}
+
+// Errors collects every invalid field instead of just the first one.
+type Errors []error
+
+// Add err to the list, nested errors are flattened.
+func (e *Errors) Add(err error) {
+	var errs Errors
+	if errors.As(err, &errs) {
+		*e = append(*e, errs...)
+		return
+	}
+
+	*e = append(*e, err)
+}
+
+// Err returns the errors, or nil if there's none.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Error returns messages of every error.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// Is reports whether any of the errors matches target.
+func (e Errors) Is(target error) bool {
+	for i := range e {
+		if errors.Is(e[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fields returns every field error of err, err is not a validation error if nothing is returned.
+func Fields(err error) []Error {
+	var (
+		errs  Errors
+		field Error
+	)
+
+	if errors.As(err, &errs) {
+		var result []Error
+		for i := range errs {
+			result = append(result, Fields(errs[i])...)
+		}
+
+		return result
+	}
+
+	if errors.As(err, &field) {
+		return []Error{field}
+	}
+
+	return nil
+}
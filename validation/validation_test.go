@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTitleBlank   = New("title", "blank", "Title can't be blank")
+	errOrderInvalid = New("order", "out_of_range", "Order is out of range")
+)
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+
+	assert.Nil(t, errs.Err())
+
+	errs.Add(errTitleBlank)
+	errs.Add(Errors{errOrderInvalid})
+
+	err := errs.Err()
+	assert.Equal(t, Errors{errTitleBlank, errOrderInvalid}, err)
+	assert.Equal(t, "Title can't be blank, Order is out of range", err.Error())
+	assert.True(t, errors.Is(err, errTitleBlank))
+	assert.True(t, errors.Is(err, errOrderInvalid))
+	assert.False(t, errors.Is(err, errors.New("Title can't be blank")))
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		fields []Error
+	}{
+		{
+			name:   "field",
+			err:    errTitleBlank,
+			fields: []Error{{Field: "title", Code: "blank", Message: "Title can't be blank"}},
+		},
+		{
+			name: "errors",
+			err:  Errors{errTitleBlank, errOrderInvalid},
+			fields: []Error{
+				{Field: "title", Code: "blank", Message: "Title can't be blank"},
+				{Field: "order", Code: "out_of_range", Message: "Order is out of range"},
+			},
+		},
+		{
+			name:   "wrapped",
+			err:    fmt.Errorf("decode: %w", errTitleBlank),
+			fields: []Error{{Field: "title", Code: "blank", Message: "Title can't be blank"}},
+		},
+		{
+			name: "not validation error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.fields, Fields(test.err))
+		})
+	}
+}
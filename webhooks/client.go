@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrAddressBlocked returned when webhook host resolves to loopback, private or other internal address.
+	ErrAddressBlocked = errors.New("webhooks: address is not allowed")
+
+	// allowed reports whether deliveries can connect to the ip, it's replaced in tests to deliver to local server.
+	allowed = public
+)
+
+// public reports whether ip is reachable from the internet, rather than address of this host or its network.
+func public(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// newClient returns client that only connects to public address, the address is checked when connecting
+// so host that resolves to internal address after it's validated is refused too.
+// redirects aren't followed, the redirect response is recorded as failed attempt.
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return ErrAddressBlocked
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// SecretPrefix distinguishes webhook secret from other kind of secret.
+const SecretPrefix = "whsec_"
+
+type create struct {
+	repository rel.Repository
+}
+
+// Create webhook with a new signing secret, the secret is set to SigningSecret and can't be retrieved later.
+func (c create) Create(ctx context.Context, webhook *Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		logger.Warn("validation error", zap.Error(err), tracing.Field(ctx))
+		return err
+	}
+
+	webhook.UserID = users.FromContext(ctx)
+	webhook.Secret = generateSecret()
+	webhook.SigningSecret = webhook.Secret
+
+	return dberr.Classify(c.repository.Insert(ctx, webhook))
+}
+
+// generateSecret returns a new random secret, with 256 bits of randomness.
+func generateSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return SecretPrefix + base64.RawURLEncoding.EncodeToString(b)
+}
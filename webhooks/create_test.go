@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		webhook    = Webhook{URL: "https://example.com/hook", Events: Events{events.TodoCreated}}
+	)
+
+	repository.ExpectInsert().ForType("webhooks.Webhook")
+
+	assert.Nil(t, service.Create(ctx, &webhook))
+	assert.NotEmpty(t, webhook.ID)
+	assert.Equal(t, uint(1), webhook.UserID)
+	assert.True(t, strings.HasPrefix(webhook.Secret, SecretPrefix))
+	assert.Len(t, webhook.Secret, 49)
+	assert.Equal(t, webhook.Secret, webhook.SigningSecret)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreate_validateError(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		webhook    = Webhook{URL: "https://example.com/hook"}
+	)
+
+	assert.Equal(t, validation.Errors{ErrWebhookEventsInvalid}, service.Create(ctx, &webhook))
+	assert.Empty(t, webhook.Secret)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,24 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelete(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		webhook    = Webhook{ID: 1, UserID: 1}
+	)
+
+	repository.ExpectDelete().For(&webhook)
+
+	assert.Nil(t, service.Delete(ctx, &webhook))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,214 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/Fs02/go-todo-backend/tracing"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+// Headers of delivery request.
+const (
+	DeliveryHeader  = "X-Webhook-ID"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// DeliverJob is name of the job that sends due deliveries.
+	DeliverJob = "webhooks.deliver"
+	// MaxAttempts of a delivery, the delivery is dead once every attempt fails.
+	MaxAttempts = 8
+	// deliverBatchSize is maximum number of deliveries sent by a single Deliver.
+	deliverBatchSize = 20
+	// retryBackoff is the delay before the first retry, it's doubled on every following retry.
+	retryBackoff = time.Minute
+	// maxRetryBackoff is the longest delay between retries.
+	maxRetryBackoff = 6 * time.Hour
+	// leaseTimeout is how long claimed deliveries are hidden from other workers,
+	// deliveries of worker that stops before recording the result are sent again afterward.
+	leaseTimeout = 5 * time.Minute
+)
+
+var (
+	now = time.Now
+)
+
+type deliver struct {
+	repository rel.Repository
+	jobs       jobs.Service
+	client     *http.Client
+}
+
+// Deliver sends due deliveries and records the result, it returns number of deliveries sent whether they succeed or not.
+// deliveries are claimed with SKIP LOCKED so multiple workers can run at the same time, and sent outside of transaction.
+func (d deliver) Deliver(ctx context.Context) (int, error) {
+	var (
+		deliveries []Delivery
+	)
+
+	if err := d.claim(ctx, &deliveries); err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		status, err := d.send(ctx, deliveries[i])
+		if err := d.record(ctx, &deliveries[i], status, err); err != nil {
+			return i, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (d deliver) claim(ctx context.Context, deliveries *[]Delivery) error {
+	t := now()
+
+	err := d.repository.Transaction(ctx, func(ctx context.Context) error {
+		query := rel.Where(rel.Eq("status", DeliveryPending), rel.Lte("next_attempt_at", t)).
+			SortAsc("next_attempt_at").Limit(deliverBatchSize).Lock("FOR UPDATE SKIP LOCKED")
+
+		if err := d.repository.FindAll(ctx, deliveries, query); err != nil {
+			return err
+		}
+
+		if len(*deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, len(*deliveries))
+		for i := range *deliveries {
+			ids[i] = (*deliveries)[i].ID
+		}
+
+		_, err := d.repository.UpdateAny(ctx, rel.From("webhook_deliveries").Where(rel.In("id", ids...)), rel.Set("next_attempt_at", t.Add(leaseTimeout)))
+		return err
+	})
+
+	if err != nil || len(*deliveries) == 0 {
+		return dberr.Classify(err)
+	}
+
+	return dberr.Classify(d.repository.Preload(ctx, deliveries, "webhook"))
+}
+
+// send posts the delivery to its webhook, status is zero when there's no response.
+func (d deliver) send(ctx context.Context, delivery Delivery) (int, error) {
+	ctx, span := tracing.Start(ctx, "webhooks.Deliver")
+	defer span.End()
+
+	span.SetKind(tracing.KindClient)
+	span.SetAttribute("webhook.id", strconv.FormatUint(uint64(delivery.WebhookID), 10))
+	span.SetAttribute("webhook.event", string(delivery.Event))
+
+	body, err := json.Marshal(struct {
+		ID   uint        `json:"id"`
+		Type events.Type `json:"type"`
+		Data Payload     `json:"data"`
+	}{ID: delivery.ID, Type: delivery.Event, Data: delivery.Payload})
+	if err != nil {
+		return 0, span.Record(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, span.Record(err)
+	}
+
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, timestamp, body))
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, span.Record(err)
+	}
+
+	// response body is drained so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, span.Record(fmt.Errorf("webhooks: unexpected status %d", resp.StatusCode))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// record result of an attempt, failed delivery is retried with exponential backoff until it runs out of attempts,
+// the retry is enqueued as a job in the same transaction.
+func (d deliver) record(ctx context.Context, delivery *Delivery, status int, err error) error {
+	var (
+		t        = now()
+		attempts = delivery.Attempts + 1
+		mutators = []rel.Mutator{rel.Set("attempts", attempts), rel.Set("response_status", status)}
+	)
+
+	switch {
+	case err == nil:
+		mutators = append(mutators, rel.Set("status", DeliveryDelivered), rel.Set("delivered_at", &t), rel.Set("last_error", ""))
+	case attempts >= MaxAttempts:
+		logger.Warn("delivery dead", zap.Error(err), zap.Uint("id", delivery.ID), zap.Uint("webhook_id", delivery.WebhookID), tracing.Field(ctx))
+		mutators = append(mutators, rel.Set("status", DeliveryDead), rel.Set("last_error", err.Error()))
+	default:
+		logger.Info("delivery failed", zap.Error(err), zap.Uint("id", delivery.ID), zap.Uint("webhook_id", delivery.WebhookID), zap.Int("attempts", attempts), tracing.Field(ctx))
+		next := t.Add(backoff(attempts))
+		mutators = append(mutators, rel.Set("next_attempt_at", next), rel.Set("last_error", err.Error()))
+
+		return d.repository.Transaction(ctx, func(ctx context.Context) error {
+			if err := d.repository.Update(ctx, delivery, mutators...); err != nil {
+				return dberr.Classify(err)
+			}
+
+			return d.jobs.Enqueue(ctx, &jobs.Job{Name: DeliverJob, RunAt: next})
+		})
+	}
+
+	return dberr.Classify(d.repository.Update(ctx, delivery, mutators...))
+}
+
+// backoff before the next attempt, after the given number of failed attempts.
+func backoff(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return delay
+}
+
+// Sign body of delivery, signature is hex encoded HMAC-SHA256 of timestamp and body joined by a dot,
+// so receiver can verify that the delivery is sent by this service and reject replayed deliveries by the timestamp.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
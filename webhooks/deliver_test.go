@@ -0,0 +1,226 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs/jobstest"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliver(t *testing.T) {
+	var (
+		today     = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		claim     = rel.Where(rel.Eq("status", DeliveryPending), rel.Lte("next_attempt_at", today)).SortAsc("next_attempt_at").Limit(20).Lock("FOR UPDATE SKIP LOCKED")
+		leased    = rel.From("webhook_deliveries").Where(rel.In("id", uint(1)))
+		receivers = map[string]int{}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	// test server listens on loopback address.
+	allowed = func(net.IP) bool { return true }
+	defer func() { allowed = public }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		assert.Equal(t, "1", r.Header.Get(DeliveryHeader))
+		assert.Equal(t, "todo.created", r.Header.Get(EventHeader))
+		assert.Equal(t, "1593302400", r.Header.Get(TimestampHeader))
+		assert.Equal(t, Sign("whsec_secret", "1593302400", body), r.Header.Get(SignatureHeader))
+		assert.JSONEq(t, `{"id":1, "type":"todo.created", "data":{"id":3}}`, string(body))
+
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/ok", http.StatusFound)
+			return
+		}
+
+		w.WriteHeader(receivers[r.URL.Path])
+	}))
+	defer server.Close()
+
+	receivers["/ok"] = http.StatusNoContent
+	receivers["/error"] = http.StatusInternalServerError
+
+	tests := []struct {
+		name     string
+		path     string
+		attempts int
+		mutators []rel.Mutator
+		retried  bool
+	}{
+		{
+			name: "delivered",
+			path: "/ok",
+			mutators: []rel.Mutator{
+				rel.Set("attempts", 1), rel.Set("response_status", 204), rel.Set("status", DeliveryDelivered), rel.Set("delivered_at", &today), rel.Set("last_error", ""),
+			},
+		},
+		{
+			name:     "retried",
+			path:     "/error",
+			attempts: 2,
+			mutators: []rel.Mutator{
+				rel.Set("attempts", 3), rel.Set("response_status", 500), rel.Set("next_attempt_at", today.Add(4*time.Minute)), rel.Set("last_error", "webhooks: unexpected status 500"),
+			},
+			retried: true,
+		},
+		{
+			name: "redirect isn't followed",
+			path: "/redirect",
+			mutators: []rel.Mutator{
+				rel.Set("attempts", 1), rel.Set("response_status", 302), rel.Set("next_attempt_at", today.Add(time.Minute)), rel.Set("last_error", "webhooks: unexpected status 302"),
+			},
+			retried: true,
+		},
+		{
+			name:     "dead",
+			path:     "/error",
+			attempts: MaxAttempts - 1,
+			mutators: []rel.Mutator{
+				rel.Set("attempts", MaxAttempts), rel.Set("response_status", 500), rel.Set("status", DeliveryDead), rel.Set("last_error", "webhooks: unexpected status 500"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				jobs       = &jobstest.Service{}
+				service    = New(repository, jobs)
+				delivery   = Delivery{ID: 1, WebhookID: 1, Event: events.TodoCreated, Payload: `{"id":3}`, Status: DeliveryPending, Attempts: test.attempts}
+			)
+
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectFindAll(claim).Result([]Delivery{delivery})
+				repository.ExpectUpdateAny(leased, rel.Set("next_attempt_at", today.Add(leaseTimeout))).UpdatedCount(1)
+			})
+			repository.ExpectPreload("webhook").Result([]Webhook{{ID: 1, URL: server.URL + test.path, Secret: "whsec_secret"}})
+			if test.retried {
+				repository.ExpectTransaction(func(repository *reltest.Repository) {
+					repository.ExpectUpdate(test.mutators...).ForType("webhooks.Delivery")
+				})
+				jobstest.Mock(jobs, jobstest.MockEnqueue(DeliverJob, nil))
+			} else {
+				repository.ExpectUpdate(test.mutators...).ForType("webhooks.Delivery")
+			}
+
+			count, err := service.Deliver(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, count)
+
+			repository.AssertExpectations(t)
+			jobs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeliver_blocked(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		jobs       = &jobstest.Service{}
+		service    = New(repository, jobs)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		claim      = rel.Where(rel.Eq("status", DeliveryPending), rel.Lte("next_attempt_at", today)).SortAsc("next_attempt_at").Limit(20).Lock("FOR UPDATE SKIP LOCKED")
+		delivery   = Delivery{ID: 1, WebhookID: 1, Event: events.TodoCreated, Payload: `{"id":3}`, Status: DeliveryPending}
+		requested  bool
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(claim).Result([]Delivery{delivery})
+		repository.ExpectUpdateAny(rel.From("webhook_deliveries").Where(rel.In("id", uint(1))), rel.Set("next_attempt_at", today.Add(leaseTimeout))).UpdatedCount(1)
+	})
+	repository.ExpectPreload("webhook").Result([]Webhook{{ID: 1, URL: server.URL, Secret: "whsec_secret"}})
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(
+			rel.Set("attempts", 1), rel.Set("response_status", 0), rel.Set("next_attempt_at", today.Add(time.Minute)),
+			rel.Set("last_error", "Post \""+server.URL+"\": dial tcp "+server.Listener.Addr().String()+": webhooks: address is not allowed"),
+		).ForType("webhooks.Delivery")
+	})
+	jobstest.Mock(jobs, jobstest.MockEnqueue(DeliverJob, nil))
+
+	count, err := service.Deliver(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.False(t, requested)
+
+	repository.AssertExpectations(t)
+	jobs.AssertExpectations(t)
+}
+
+func TestDeliver_empty(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Eq("status", DeliveryPending), rel.Lte("next_attempt_at", today)).SortAsc("next_attempt_at").Limit(20).Lock("FOR UPDATE SKIP LOCKED")).Result([]Delivery{})
+	})
+
+	count, err := service.Deliver(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeliver_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(rel.Where(rel.Eq("status", DeliveryPending), rel.Lte("next_attempt_at", today)).SortAsc("next_attempt_at").Limit(20).Lock("FOR UPDATE SKIP LOCKED")).ConnectionClosed()
+	})
+
+	_, err := service.Deliver(ctx)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, backoff(1))
+	assert.Equal(t, 2*time.Minute, backoff(2))
+	assert.Equal(t, 64*time.Minute, backoff(7))
+	assert.Equal(t, 6*time.Hour, backoff(20))
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=c1c866e30ab02f461b1e281583e87adef997d1c54b3e5e612156df98a6c3e006", Sign("secret", "1593302400", []byte(`{}`)))
+}
@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/go-rel/rel"
+)
+
+type redeliver struct {
+	repository rel.Repository
+	jobs       jobs.Service
+}
+
+// Redeliver puts the delivery back to pending with a fresh set of attempts, so it's sent again as soon as possible.
+// the delivery keeps its id, so receiver can recognize the event it has already processed.
+func (r redeliver) Redeliver(ctx context.Context, delivery *Delivery) error {
+	t := now()
+
+	return r.repository.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.repository.Update(ctx, delivery,
+			rel.Set("status", DeliveryPending),
+			rel.Set("attempts", 0),
+			rel.Set("next_attempt_at", t),
+		); err != nil {
+			return dberr.Classify(err)
+		}
+
+		return r.jobs.Enqueue(ctx, &jobs.Job{Name: DeliverJob, RunAt: t})
+	})
+}
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/jobs/jobstest"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedeliver(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		jobs       = &jobstest.Service{}
+		service    = New(repository, jobs)
+		today      = time.Date(2020, 6, 28, 0, 0, 0, 0, time.UTC)
+		delivery   = Delivery{ID: 1, WebhookID: 1, Status: DeliveryDead, Attempts: MaxAttempts, LastError: "timeout"}
+	)
+
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdate(rel.Set("status", DeliveryPending), rel.Set("attempts", 0), rel.Set("next_attempt_at", today)).ForType("webhooks.Delivery")
+	})
+	jobstest.Mock(jobs, jobstest.MockEnqueue(DeliverJob, nil))
+
+	assert.Nil(t, service.Redeliver(ctx, &delivery))
+	assert.Equal(t, Delivery{ID: 1, WebhookID: 1, Status: DeliveryPending, NextAttemptAt: today, LastError: "timeout"}, delivery)
+
+	repository.AssertExpectations(t)
+	jobs.AssertExpectations(t)
+}
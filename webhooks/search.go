@@ -0,0 +1,18 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+)
+
+type search struct {
+	repository rel.Repository
+}
+
+// Search webhooks of the user.
+func (s search) Search(ctx context.Context, webhooks *[]Webhook) error {
+	return dberr.Classify(s.repository.FindAll(ctx, webhooks, rel.Where(rel.Eq("user_id", users.FromContext(ctx))).SortAsc("id")))
+}
@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+)
+
+// MaxDeliveries returned by a single search.
+var MaxDeliveries = 100
+
+type searchDeliveries struct {
+	repository rel.Repository
+}
+
+// SearchDeliveries of the webhook, the most recent deliveries come first.
+func (sd searchDeliveries) SearchDeliveries(ctx context.Context, deliveries *[]Delivery, webhook Webhook) error {
+	return dberr.Classify(sd.repository.FindAll(ctx, deliveries, rel.Where(rel.Eq("webhook_id", webhook.ID)).SortDesc("id").Limit(MaxDeliveries)))
+}
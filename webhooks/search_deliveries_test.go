@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchDeliveries(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		deliveries []Delivery
+		result     = []Delivery{{ID: 2, WebhookID: 1}, {ID: 1, WebhookID: 1}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("webhook_id", uint(1))).SortDesc("id").Limit(100)).Result(result)
+
+	assert.Nil(t, service.SearchDeliveries(ctx, &deliveries, Webhook{ID: 1}))
+	assert.Equal(t, result, deliveries)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSearchDeliveries_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		deliveries []Delivery
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("webhook_id", uint(1))).SortDesc("id").Limit(100)).ConnectionClosed()
+
+	err := service.SearchDeliveries(ctx, &deliveries, Webhook{ID: 1})
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
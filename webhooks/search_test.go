@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/users"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var (
+		ctx        = users.NewContext(context.TODO(), 1)
+		repository = reltest.New()
+		service    = New(repository, nil)
+		webhooks   []Webhook
+		result     = []Webhook{{ID: 1, UserID: 1, URL: "https://example.com/hook"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1))).SortAsc("id")).Result(result)
+
+	assert.Nil(t, service.Search(ctx, &webhooks))
+	assert.Equal(t, result, webhooks)
+
+	repository.AssertExpectations(t)
+}
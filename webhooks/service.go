@@ -0,0 +1,57 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/go-rel/rel"
+	"go.uber.org/zap"
+)
+
+var (
+	logger, _ = zap.NewProduction(zap.Fields(zap.String("type", "webhooks")))
+)
+
+//go:generate mockery --name=Service --case=underscore --output webhookstest --outpkg webhookstest
+
+// Service instance for webhook's domain.
+// Any operation done to any of object within this domain should use this service.
+type Service interface {
+	Search(ctx context.Context, webhooks *[]Webhook) error
+	Create(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, webhook *Webhook) error
+	SearchDeliveries(ctx context.Context, deliveries *[]Delivery, webhook Webhook) error
+	Redeliver(ctx context.Context, delivery *Delivery) error
+	Store(ctx context.Context, events []events.Event) error
+	Deliver(ctx context.Context) (int, error)
+}
+
+// beside embeding the struct, you can also declare the function directly on this struct.
+// the advantage of embedding the struct is it allows spreading the implementation across multiple files.
+type service struct {
+	search
+	create
+	delete
+	searchDeliveries
+	redeliver
+	store
+	deliver
+}
+
+var _ Service = (*service)(nil)
+var _ events.Outbox = (*service)(nil)
+
+// New webhooks service, it's also the outbox that stores deliveries of published events.
+// deliveries are sent by DeliverJob enqueued to jobs whenever a delivery is due.
+func New(repository rel.Repository, jobs jobs.Service) Service {
+	return service{
+		search:           search{repository: repository},
+		create:           create{repository: repository},
+		delete:           delete{repository: repository},
+		searchDeliveries: searchDeliveries{repository: repository},
+		redeliver:        redeliver{repository: repository, jobs: jobs},
+		store:            store{repository: repository, jobs: jobs},
+		deliver:          deliver{repository: repository, jobs: jobs, client: newClient()},
+	}
+}
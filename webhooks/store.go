@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs"
+	"github.com/go-rel/rel"
+)
+
+type store struct {
+	repository rel.Repository
+	jobs       jobs.Service
+}
+
+// Store deliveries of events to every webhook that subscribes them, it's the outbox of events bus.
+// events of a list are delivered to webhooks of every member of the list, other events only to webhooks of their user.
+// it runs in the transaction of the change, so a rolled back change is never delivered,
+// and the job that sends deliveries is enqueued in the same transaction.
+func (s store) Store(ctx context.Context, evs []events.Event) error {
+	var (
+		t          = now()
+		deliveries []Delivery
+	)
+
+	for _, event := range evs {
+		var (
+			webhooks []Webhook
+		)
+
+		if err := s.repository.FindAll(ctx, &webhooks, rel.Where(recipients(event))); err != nil {
+			return dberr.Classify(err)
+		}
+
+		for _, webhook := range webhooks {
+			if !webhook.Events.Contains(event.Type) {
+				continue
+			}
+
+			deliveries = append(deliveries, Delivery{
+				WebhookID:     webhook.ID,
+				UserID:        webhook.UserID,
+				Event:         event.Type,
+				Payload:       Payload(event.Data),
+				Status:        DeliveryPending,
+				NextAttemptAt: t,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := s.repository.InsertAll(ctx, &deliveries); err != nil {
+		return dberr.Classify(err)
+	}
+
+	return s.jobs.Enqueue(ctx, &jobs.Job{Name: DeliverJob, RunAt: t})
+}
+
+func recipients(event events.Event) rel.FilterQuery {
+	if event.ListID != nil {
+		return rel.In("user_id", rel.Select("user_id").From("list_members").Where(rel.Eq("list_id", *event.ListID)))
+	}
+
+	return rel.Eq("user_id", event.UserID)
+}
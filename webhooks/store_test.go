@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/dberr"
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/jobs/jobstest"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		jobs       = &jobstest.Service{}
+		service    = New(repository, jobs)
+		listID     = uint(2)
+		evs        = []events.Event{
+			events.New(events.TodoCreated, 1, nil, struct {
+				ID uint `json:"id"`
+			}{ID: 1}),
+			events.New(events.TodoDeleted, 1, &listID, struct {
+				ID uint `json:"id"`
+			}{ID: 2}),
+		}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1)))).Result([]Webhook{
+		{ID: 1, UserID: 1, Events: Events{events.TodoCreated}},
+		{ID: 2, UserID: 1, Events: Events{events.ScoreChanged}},
+	})
+	repository.ExpectFindAll(rel.Where(rel.In("user_id", rel.Select("user_id").From("list_members").Where(rel.Eq("list_id", uint(2)))))).Result([]Webhook{
+		{ID: 1, UserID: 1, Events: Events{events.TodoCreated}},
+		{ID: 3, UserID: 2, Events: Events{events.TodoDeleted}},
+	})
+	repository.ExpectInsertAll().ForType("*[]webhooks.Delivery")
+	jobstest.Mock(jobs, jobstest.MockEnqueue(DeliverJob, nil))
+
+	assert.Nil(t, service.Store(ctx, evs))
+
+	repository.AssertExpectations(t)
+	jobs.AssertExpectations(t)
+}
+
+func TestStore_unsubscribed(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		evs        = []events.Event{events.New(events.TodosCleared, 1, nil, struct{}{})}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1)))).Result([]Webhook{
+		{ID: 1, UserID: 1, Events: Events{events.TodoCreated}},
+	})
+
+	assert.Nil(t, service.Store(ctx, evs))
+
+	repository.AssertExpectations(t)
+}
+
+func TestStore_error(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, nil)
+		evs        = []events.Event{events.New(events.TodosCleared, 1, nil, struct{}{})}
+	)
+
+	repository.ExpectFindAll(rel.Where(rel.Eq("user_id", uint(1)))).ConnectionClosed()
+
+	err := service.Store(ctx, evs)
+	assert.True(t, errors.Is(err, dberr.ErrUnavailable))
+
+	repository.AssertExpectations(t)
+}
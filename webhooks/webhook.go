@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/validation"
+)
+
+var (
+	// ErrWebhookURLInvalid validation error.
+	ErrWebhookURLInvalid = validation.New("url", "invalid", "URL must be an absolute http or https URL")
+	// ErrWebhookURLPrivate validation error.
+	ErrWebhookURLPrivate = validation.New("url", "private", "URL must not point to local or private network")
+	// ErrWebhookEventsInvalid validation error.
+	ErrWebhookEventsInvalid = validation.New("events", "invalid", "Events must contain at least one of todo.created, todo.updated, todo.deleted, todos.cleared or score.changed")
+)
+
+// Events subscribed by webhook, stored as space separated string.
+type Events []events.Type
+
+// Contains returns true if the event type is subscribed.
+func (e Events) Contains(typ events.Type) bool {
+	for i := range e {
+		if e[i] == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valid returns true if there's at least one event and every event is known.
+func (e Events) valid() bool {
+	if len(e) == 0 {
+		return false
+	}
+
+	for i := range e {
+		switch e[i] {
+		case events.TodoCreated, events.TodoUpdated, events.TodoDeleted, events.TodosCleared, events.ScoreChanged:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Value implements driver.Valuer.
+func (e Events) Value() (driver.Value, error) {
+	strs := make([]string, len(e))
+	for i := range e {
+		strs[i] = string(e[i])
+	}
+
+	return strings.Join(strs, " "), nil
+}
+
+// Scan implements sql.Scanner.
+func (e *Events) Scan(src interface{}) error {
+	var str string
+	switch v := src.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case nil:
+	default:
+		return errors.New("webhooks: unsupported events type")
+	}
+
+	*e = nil
+	for _, field := range strings.Fields(str) {
+		*e = append(*e, events.Type(field))
+	}
+
+	return nil
+}
+
+// Webhook respresent a record stored in webhooks table.
+// subscribed events of the user are posted to the url, signed using the secret.
+type Webhook struct {
+	ID     uint   `json:"id"`
+	UserID uint   `json:"-"`
+	URL    string `json:"url"`
+	Events Events `json:"events"`
+	Secret string `json:"-"`
+	// SigningSecret is the secret shown to the user, it's only available right after the webhook is created.
+	SigningSecret string    `json:"secret,omitempty" db:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate webhook.
+func (w Webhook) Validate() error {
+	var errs validation.Errors
+
+	// host that resolves to internal address is refused when delivering, only obviously internal host is rejected here.
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.Add(ErrWebhookURLInvalid)
+	} else if host := u.Hostname(); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		errs.Add(ErrWebhookURLPrivate)
+	} else if ip := net.ParseIP(host); ip != nil && !public(ip) {
+		errs.Add(ErrWebhookURLPrivate)
+	}
+
+	if !w.Events.valid() {
+		errs.Add(ErrWebhookEventsInvalid)
+	}
+
+	return errs.Err()
+}
+
+// DeliveryStatus of webhook delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for the next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered is accepted by the receiver.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead failed every attempt, it's only retried when redelivered.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Payload is event data encoded as json, it's stored as text.
+type Payload string
+
+// MarshalJSON embeds the payload as is.
+func (p Payload) MarshalJSON() ([]byte, error) {
+	if p == "" {
+		return []byte("null"), nil
+	}
+
+	return []byte(p), nil
+}
+
+// Delivery respresent a record stored in webhook_deliveries table.
+// it's inserted in the same transaction as the change, so only committed changes are delivered.
+type Delivery struct {
+	ID             uint           `json:"id"`
+	WebhookID      uint           `json:"webhook_id"`
+	Webhook        Webhook        `json:"-"`
+	UserID         uint           `json:"-"`
+	Event          events.Type    `json:"event"`
+	Payload        Payload        `json:"payload"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastError      string         `json:"last_error,omitempty"`
+	ResponseStatus int            `json:"response_status,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Table name of delivery.
+func (Delivery) Table() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Fs02/go-todo-backend/events"
+	"github.com/Fs02/go-todo-backend/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook Webhook
+		err     error
+	}{
+		{
+			name:    "valid",
+			webhook: Webhook{URL: "https://example.com/hook", Events: Events{events.TodoCreated, events.ScoreChanged}},
+		},
+		{
+			name:    "relative url",
+			webhook: Webhook{URL: "/hook", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLInvalid},
+		},
+		{
+			name:    "unsupported scheme",
+			webhook: Webhook{URL: "ftp://example.com/hook", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLInvalid},
+		},
+		{
+			name:    "localhost",
+			webhook: Webhook{URL: "http://localhost:8080/hook", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLPrivate},
+		},
+		{
+			name:    "metadata address",
+			webhook: Webhook{URL: "http://169.254.169.254/latest/meta-data", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLPrivate},
+		},
+		{
+			name:    "private address",
+			webhook: Webhook{URL: "https://10.0.0.1/hook", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLPrivate},
+		},
+		{
+			name:    "loopback ipv6 address",
+			webhook: Webhook{URL: "http://[::1]/hook", Events: Events{events.TodoCreated}},
+			err:     validation.Errors{ErrWebhookURLPrivate},
+		},
+		{
+			name:    "without events",
+			webhook: Webhook{URL: "https://example.com/hook"},
+			err:     validation.Errors{ErrWebhookEventsInvalid},
+		},
+		{
+			name:    "unknown event",
+			webhook: Webhook{URL: "https://example.com/hook", Events: Events{events.TodoCreated, "todo.archived"}},
+			err:     validation.Errors{ErrWebhookEventsInvalid},
+		},
+		{
+			name:    "every invalid field",
+			webhook: Webhook{},
+			err:     validation.Errors{ErrWebhookURLInvalid, ErrWebhookEventsInvalid},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.webhook.Validate())
+		})
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var (
+		subscribed = Events{events.TodoCreated, events.TodoDeleted}
+		scanned    Events
+	)
+
+	assert.True(t, subscribed.Contains(events.TodoCreated))
+	assert.False(t, subscribed.Contains(events.TodoUpdated))
+
+	value, err := subscribed.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "todo.created todo.deleted", value)
+
+	assert.Nil(t, scanned.Scan([]byte("todo.created todo.deleted")))
+	assert.Equal(t, subscribed, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.NotNil(t, scanned.Scan(1))
+}
+
+func TestDelivery_MarshalJSON(t *testing.T) {
+	delivery := Delivery{ID: 1, WebhookID: 2, Event: events.TodoCreated, Payload: `{"id":3}`, Status: DeliveryPending}
+
+	encoded, err := json.Marshal(delivery)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id":1, "webhook_id":2, "event":"todo.created", "payload":{"id":3}, "status":"pending", "attempts":0,
+		"next_attempt_at":"0001-01-01T00:00:00Z", "delivered_at":null, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`, string(encoded))
+}
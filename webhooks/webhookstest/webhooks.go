@@ -0,0 +1,71 @@
+package webhookstest
+
+import (
+	context "context"
+
+	webhooks "github.com/Fs02/go-todo-backend/webhooks"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockFunc function.
+type MockFunc func(service *Service)
+
+// Mock apply mock webhook functions.
+func Mock(service *Service, funcs ...MockFunc) {
+	for i := range funcs {
+		if funcs[i] != nil {
+			funcs[i](service)
+		}
+	}
+}
+
+// MockSearch util.
+func MockSearch(result []webhooks.Webhook, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Search", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *[]webhooks.Webhook) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockCreate util.
+func MockCreate(result webhooks.Webhook, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Create", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *webhooks.Webhook) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockDelete util.
+func MockDelete(err error) MockFunc {
+	return func(service *Service) {
+		service.On("Delete", mock.Anything, mock.Anything).Return(err)
+	}
+}
+
+// MockSearchDeliveries util.
+func MockSearchDeliveries(result []webhooks.Delivery, webhook webhooks.Webhook, err error) MockFunc {
+	return func(service *Service) {
+		service.On("SearchDeliveries", mock.Anything, mock.Anything, webhook).
+			Return(func(ctx context.Context, out *[]webhooks.Delivery, webhook webhooks.Webhook) error {
+				*out = result
+				return err
+			})
+	}
+}
+
+// MockRedeliver util.
+func MockRedeliver(result webhooks.Delivery, err error) MockFunc {
+	return func(service *Service) {
+		service.On("Redeliver", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, out *webhooks.Delivery) error {
+				*out = result
+				return err
+			})
+	}
+}
@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+)
+
+// Group tracks open connections, hijacked connections aren't tracked by http server, so they're closed by the group on shutdown.
+type Group struct {
+	mu     sync.Mutex
+	conns  map[*Conn]struct{}
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// Add tracks the connection until Done is called, false is returned when the group is already shut down.
+func (g *Group) Add(conn *Conn) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return false
+	}
+
+	g.conns[conn] = struct{}{}
+	g.wg.Add(1)
+	return true
+}
+
+// Done stops tracking the connection, it must be called once the connection is no longer used.
+func (g *Group) Done(conn *Conn) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.conns[conn]; ok {
+		delete(g.conns, conn)
+		g.wg.Done()
+	}
+}
+
+// Shutdown sends going away close message to every connection, and waits for them to be done.
+// connections that aren't done when ctx ends are closed without waiting for closing handshake.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	conns := make([]*Conn, 0, len(g.conns))
+	for conn := range g.conns {
+		conns = append(conns, conn)
+	}
+	g.mu.Unlock()
+
+	// peer that doesn't read blocks the write, so it mustn't delay closing the others.
+	for _, conn := range conns {
+		go conn.WriteClose(CloseGoingAway, "server shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, conn := range conns {
+			conn.Close()
+		}
+		return ctx.Err()
+	}
+}
+
+// NewGroup returns empty group.
+func NewGroup() *Group {
+	return &Group{conns: make(map[*Conn]struct{})}
+}
@@ -0,0 +1,434 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Message types, they're the opcodes of RFC 6455.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+
+	continuation = 0
+)
+
+// Close codes sent in close message.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	CloseTryAgainLater   = 1013
+)
+
+const (
+	// writeTimeout is how long a single frame may take to be written, the connection is broken when the peer doesn't read.
+	writeTimeout = 10 * time.Second
+	// closeTimeout is how long the peer has to answer close message.
+	closeTimeout = 5 * time.Second
+	// defaultReadLimit is maximum size of a message in bytes, unless changed by SetReadLimit.
+	defaultReadLimit = 64 << 10
+
+	// acceptGUID is appended to the key of the handshake as described by RFC 6455.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+var (
+	// ErrBadHandshake is returned when the request isn't a valid websocket handshake.
+	ErrBadHandshake = errors.New("websocket: bad handshake")
+	// ErrClosed is returned when writing to connection after close message is sent.
+	ErrClosed = errors.New("websocket: connection closed")
+	// ErrMessageTooBig is returned when a message exceeds the read limit.
+	ErrMessageTooBig = errors.New("websocket: message too big")
+
+	errProtocol    = errors.New("websocket: protocol error")
+	errInvalidUTF8 = errors.New("websocket: invalid utf-8 in text message")
+)
+
+// CloseError is returned by ReadMessage when the peer closes the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed %d %s", e.Code, e.Reason)
+}
+
+// Conn is a websocket connection, a single goroutine may read while other goroutines write, writes are serialized.
+type Conn struct {
+	conn      net.Conn
+	br        *bufio.Reader
+	client    bool
+	readLimit int64
+	onPong    func(data []byte)
+
+	mu        sync.Mutex
+	bw        *bufio.Writer
+	closeSent bool
+}
+
+// ReadMessage reads the next text or binary message, control messages are handled while reading:
+// ping is answered with pong, and close is answered with close before CloseError is returned.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.WriteControl(PongMessage, payload); err != nil && err != ErrClosed {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			if c.onPong != nil {
+				c.onPong(payload)
+			}
+			continue
+		case CloseMessage:
+			return 0, nil, c.closed(payload)
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(errProtocol)
+			}
+			messageType = opcode
+		case continuation:
+			if messageType == 0 {
+				return 0, nil, c.fail(errProtocol)
+			}
+		default:
+			return 0, nil, c.fail(errProtocol)
+		}
+
+		if int64(len(message)+len(payload)) > c.readLimit {
+			return 0, nil, c.fail(ErrMessageTooBig)
+		}
+		message = append(message, payload...)
+
+		if fin {
+			if messageType == TextMessage && !utf8.Valid(message) {
+				return 0, nil, c.fail(errInvalidUTF8)
+			}
+
+			return messageType, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, payload of frame sent by client is unmasked.
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	var (
+		fin    = header[0]&0x80 != 0
+		opcode = int(header[0] & 0x0f)
+		masked = header[1]&0x80 != 0
+		length = uint64(header[1] & 0x7f)
+	)
+
+	// extensions aren't negotiated, so reserved bits must not be set, and only frames sent by client are masked.
+	if header[0]&0x70 != 0 || masked == c.client {
+		return false, 0, nil, errProtocol
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	// control frames can't be fragmented, and their payload is at most 125 bytes.
+	if opcode >= CloseMessage && (!fin || length > 125) {
+		return false, 0, nil, errProtocol
+	}
+
+	if length > uint64(c.readLimit) {
+		return false, 0, nil, ErrMessageTooBig
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// fail closes the connection with code that matches the error, so the peer knows why, err is returned as is.
+func (c *Conn) fail(err error) error {
+	switch err {
+	case errProtocol:
+		c.WriteClose(CloseProtocolError, "")
+	case ErrMessageTooBig:
+		c.WriteClose(CloseMessageTooBig, "")
+	case errInvalidUTF8:
+		c.WriteClose(CloseInvalidPayload, "")
+	}
+
+	return err
+}
+
+// closed answers close message of the peer, and returns it as CloseError.
+func (c *Conn) closed(payload []byte) error {
+	err := &CloseError{Code: CloseNoStatus}
+	if len(payload) >= 2 {
+		err.Code = int(binary.BigEndian.Uint16(payload))
+		err.Reason = string(payload[2:])
+	}
+
+	c.WriteClose(err.Code, "")
+	return err
+}
+
+// WriteMessage writes a text or binary message in a single frame.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeSent {
+		return ErrClosed
+	}
+
+	return c.writeFrame(messageType, data)
+}
+
+// WriteControl writes ping or pong message, data is at most 125 bytes.
+func (c *Conn) WriteControl(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeSent {
+		return ErrClosed
+	}
+
+	return c.writeFrame(messageType, data)
+}
+
+// WriteClose starts closing handshake, no message can be written afterward,
+// and the peer has a short time to answer it before reading fails.
+// it's safe to be called multiple times, only the first close message is sent.
+func (c *Conn) WriteClose(code int, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeSent {
+		return nil
+	}
+	c.closeSent = true
+
+	var payload []byte
+	if code != CloseNoStatus {
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+	return c.writeFrame(CloseMessage, payload)
+}
+
+func (c *Conn) writeFrame(opcode int, data []byte) error {
+	var (
+		header = make([]byte, 2, 14)
+		length = len(data)
+	)
+
+	header[0] = 0x80 | byte(opcode)
+
+	switch {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	// frames sent by client must be masked with unpredictable key.
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+
+		header[1] |= 0x80
+		header = append(header, mask[:]...)
+
+		masked := make([]byte, length)
+		for i := range data {
+			masked[i] = data[i] ^ mask[i%4]
+		}
+		data = masked
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	if _, err := c.bw.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := c.bw.Write(data); err != nil {
+		return err
+	}
+
+	return c.bw.Flush()
+}
+
+// SetReadDeadline sets deadline of reading the next message, it's ignored once closing handshake is started.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeSent {
+		return nil
+	}
+
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetReadLimit sets maximum size of a message in bytes, larger message closes the connection.
+func (c *Conn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+// SetPongHandler sets function called by ReadMessage when pong is received, it's usually used to extend read deadline.
+func (c *Conn) SetPongHandler(fn func(data []byte)) {
+	c.onPong = fn
+}
+
+// Close closes the underlying connection without closing handshake.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{
+		conn:      conn,
+		br:        br,
+		bw:        bufio.NewWriter(conn),
+		client:    client,
+		readLimit: defaultReadLimit,
+	}
+}
+
+// Upgrade takes over connection of the request, and completes websocket handshake.
+// nothing is written when the request isn't a valid handshake, so caller can still respond with error.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	var (
+		key = r.Header.Get("Sec-WebSocket-Key")
+	)
+
+	if r.Method != http.MethodGet || !hasToken(r.Header, "Connection", "upgrade") || !hasToken(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" || !validKey(key) {
+		return nil, ErrBadHandshake
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("websocket: response doesn't support hijacking")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// client must wait for the handshake to complete before sending any frame, so nothing should've been buffered.
+	if rw.Reader.Buffered() > 0 {
+		conn.Close()
+		return nil, ErrBadHandshake
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// connection is no longer managed by http server, so deadlines set by the server are removed.
+	conn.SetDeadline(time.Time{})
+
+	return newConn(conn, rw.Reader, false), nil
+}
+
+// hasToken reports whether comma separated header contains token, case insensitively.
+func hasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// validKey reports whether key is base64 encoded 16 bytes value.
+func validKey(key string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(decoded) == 16
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,215 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// echo server answers every message with the same message, until the connection is closed.
+func echo(group *Group) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		if group != nil && group.Add(conn) {
+			defer group.Done(conn)
+		}
+
+		conn.SetReadLimit(1024)
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			conn.WriteMessage(messageType, data)
+		}
+	}
+}
+
+func dial(t *testing.T, server *httptest.Server) *Conn {
+	conn, _, err := Dial(context.TODO(), "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	return conn
+}
+
+func TestUpgrade(t *testing.T) {
+	var (
+		server = httptest.NewServer(echo(nil))
+		conn   = dial(t, server)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	tests := []struct {
+		name        string
+		messageType int
+		data        []byte
+	}{
+		{name: "text", messageType: TextMessage, data: []byte(`{"type":"subscribe"}`)},
+		{name: "binary", messageType: BinaryMessage, data: []byte{0, 1, 2}},
+		{name: "extended length", messageType: TextMessage, data: bytes.Repeat([]byte("a"), 300)},
+		{name: "empty", messageType: TextMessage, data: []byte{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Nil(t, conn.WriteMessage(test.messageType, test.data))
+
+			messageType, data, err := conn.ReadMessage()
+			assert.Nil(t, err)
+			assert.Equal(t, test.messageType, messageType)
+			assert.Equal(t, string(test.data), string(data))
+		})
+	}
+}
+
+func TestUpgrade_badHandshake(t *testing.T) {
+	server := httptest.NewServer(echo(nil))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+	}{
+		{name: "plain request", header: map[string]string{}},
+		{name: "unsupported version", header: map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ=="}},
+		{name: "invalid key", header: map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "abc"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			for name, value := range test.header {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// example of RFC 6455 section 1.3.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestConn_ReadMessage_ping(t *testing.T) {
+	var (
+		server = httptest.NewServer(echo(nil))
+		conn   = dial(t, server)
+		pong   = make(chan string, 1)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	conn.SetPongHandler(func(data []byte) { pong <- string(data) })
+
+	assert.Nil(t, conn.WriteControl(PingMessage, []byte("keepalive")))
+	assert.Nil(t, conn.WriteMessage(TextMessage, []byte("after ping")))
+
+	_, data, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "after ping", string(data))
+	assert.Equal(t, "keepalive", <-pong)
+}
+
+func TestConn_ReadMessage_tooBig(t *testing.T) {
+	var (
+		server = httptest.NewServer(echo(nil))
+		conn   = dial(t, server)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteMessage(TextMessage, bytes.Repeat([]byte("a"), 2048)))
+
+	_, _, err := conn.ReadMessage()
+	assert.Equal(t, &CloseError{Code: CloseMessageTooBig}, err)
+}
+
+func TestConn_WriteClose(t *testing.T) {
+	var (
+		server = httptest.NewServer(echo(nil))
+		conn   = dial(t, server)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteClose(CloseNormal, "bye"))
+	assert.Equal(t, ErrClosed, conn.WriteMessage(TextMessage, []byte("late")))
+
+	// server answers close message with the same code.
+	_, _, err := conn.ReadMessage()
+	assert.Equal(t, &CloseError{Code: CloseNormal}, err)
+}
+
+func TestGroup_Shutdown(t *testing.T) {
+	var (
+		group  = NewGroup()
+		server = httptest.NewServer(echo(group))
+		conn   = dial(t, server)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	// make sure the server has added the connection.
+	assert.Nil(t, conn.WriteMessage(TextMessage, []byte("hello")))
+	_, _, err := conn.ReadMessage()
+	assert.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- group.Shutdown(context.TODO())
+	}()
+
+	_, _, err = conn.ReadMessage()
+	assert.Equal(t, &CloseError{Code: CloseGoingAway, Reason: "server shutting down"}, err)
+	assert.Nil(t, <-done)
+	assert.False(t, group.Add(conn))
+}
+
+func TestGroup_Shutdown_timeout(t *testing.T) {
+	var (
+		group  = NewGroup()
+		server = httptest.NewServer(echo(group))
+		conn   = dial(t, server)
+	)
+
+	defer server.Close()
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteMessage(TextMessage, []byte("hello")))
+	_, _, err := conn.ReadMessage()
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	// client never answers the close message.
+	assert.True(t, errors.Is(group.Shutdown(ctx), context.DeadlineExceeded))
+}